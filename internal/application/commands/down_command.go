@@ -2,26 +2,210 @@ package commands
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/dependency"
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+	"github.com/vivekkundariya/grund/internal/ui"
 )
 
-// DownCommand represents the command to stop all services
-type DownCommand struct{}
+// DownCommand represents the command to stop services
+type DownCommand struct {
+	ServiceNames []string // services to stop; everything when empty
+	WithDeps     bool     // also stop dependencies no remaining service needs
+	Infra        bool     // also stop infrastructure no remaining service requires
+}
 
 // DownCommandHandler handles the down command
 type DownCommandHandler struct {
+	serviceRepo  ports.ServiceRepository
 	orchestrator ports.ContainerOrchestrator
 }
 
+// infrastructureContainers are the shared containers, in the order they are stopped
+var infrastructureContainers = []infrastructure.InfrastructureType{
+	infrastructure.InfrastructureTypePostgres,
+	infrastructure.InfrastructureTypeMongoDB,
+	infrastructure.InfrastructureTypeRedis,
+	infrastructure.InfrastructureTypeLocalStack,
+}
+
 // NewDownCommandHandler creates a new down command handler
-func NewDownCommandHandler(orchestrator ports.ContainerOrchestrator) *DownCommandHandler {
+func NewDownCommandHandler(serviceRepo ports.ServiceRepository, orchestrator ports.ContainerOrchestrator) *DownCommandHandler {
 	return &DownCommandHandler{
+		serviceRepo:  serviceRepo,
 		orchestrator: orchestrator,
 	}
 }
 
 // Handle executes the down command
+// Without service names the whole stack is stopped. Otherwise only the named services
+// are, plus, on request, the dependencies and infrastructure nothing else still uses.
 func (h *DownCommandHandler) Handle(ctx context.Context, cmd DownCommand) error {
-	return h.orchestrator.StopServices(ctx)
+	if len(cmd.ServiceNames) == 0 {
+		return h.orchestrator.StopServices(ctx)
+	}
+
+	statuses, err := h.orchestrator.GetAllServiceStatuses(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get service statuses: %w", err)
+	}
+
+	// A service is up while it has a container, running or not
+	up := make(map[service.ServiceName]bool)
+	for _, status := range statuses {
+		if status.Status != "not running" {
+			up[service.ServiceName(status.Name)] = true
+		}
+	}
+	isInfra := make(map[service.ServiceName]bool, len(infrastructureContainers))
+	for _, infra := range infrastructureContainers {
+		isInfra[service.ServiceName(infra)] = true
+	}
+
+	stop := make(map[service.ServiceName]bool)
+	var targets []service.ServiceName
+	for _, name := range cmd.ServiceNames {
+		svcName := service.ServiceName(name)
+		if !up[svcName] {
+			ui.Warnf("%s is not running", name)
+			continue
+		}
+		if !stop[svcName] {
+			stop[svcName] = true
+			targets = append(targets, svcName)
+		}
+	}
+	if len(targets) == 0 {
+		ui.Infof("No services to stop")
+		return nil
+	}
+
+	// The graph covers every service that is up and its dependencies
+	var graphRoots []service.ServiceName
+	for name := range up {
+		if !isInfra[name] {
+			graphRoots = append(graphRoots, name)
+		}
+	}
+	graph := h.buildGraph(graphRoots)
+
+	if cmd.WithDeps {
+		candidates := make(map[service.ServiceName]bool)
+		for _, target := range targets {
+			deps, _ := graph.GetAllDependencies(target)
+			for _, dep := range deps {
+				if up[dep] && !stop[dep] {
+					candidates[dep] = true
+				}
+			}
+		}
+
+		// Whatever stays up keeps its transitive dependencies
+		needed := make(map[service.ServiceName]bool)
+		for name := range up {
+			if isInfra[name] || stop[name] || candidates[name] {
+				continue
+			}
+			deps, _ := graph.GetAllDependencies(name)
+			for _, dep := range deps {
+				needed[dep] = true
+			}
+		}
+
+		for _, dep := range sortedNames(candidates) {
+			if needed[dep] {
+				ui.Infof("Keeping %s, still needed by running services", dep)
+				continue
+			}
+			stop[dep] = true
+			targets = append(targets, dep)
+		}
+	}
+
+	// Warn about services left running without a dependency
+	for _, name := range sortedNames(up) {
+		if isInfra[name] || stop[name] {
+			continue
+		}
+		node, err := graph.GetNode(name)
+		if err != nil {
+			continue
+		}
+		for _, dep := range node.Dependencies {
+			if stop[dep] {
+				ui.Warnf("%s depends on %s, which is being stopped", name, dep)
+			}
+		}
+	}
+
+	if cmd.Infra {
+		var requirements []infrastructure.InfrastructureRequirements
+		for name := range up {
+			if isInfra[name] || stop[name] {
+				continue
+			}
+			if node, err := graph.GetNode(name); err == nil {
+				requirements = append(requirements, node.Service.Dependencies.Infrastructure)
+			}
+		}
+		remaining := infrastructure.Aggregate(requirements...)
+		for _, infra := range infrastructureContainers {
+			name := service.ServiceName(infra)
+			if up[name] && !stop[name] && !remaining.Has(string(infra)) {
+				stop[name] = true
+				targets = append(targets, name)
+			}
+		}
+	}
+
+	if err := h.orchestrator.RemoveServices(ctx, targets); err != nil {
+		return err
+	}
+
+	stopped := make([]string, len(targets))
+	for i, name := range targets {
+		stopped[i] = name.String()
+	}
+	ui.Successf("Stopped %s", strings.Join(stopped, ", "))
+	return nil
+}
+
+// buildGraph loads the given services and their transitive dependencies into a graph
+// Services whose config can't be loaded (e.g. no longer registered) have no dependencies.
+func (h *DownCommandHandler) buildGraph(names []service.ServiceName) *dependency.Graph {
+	graph := dependency.NewGraph()
+	loaded := make(map[service.ServiceName]bool)
+	queue := append([]service.ServiceName{}, names...)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if loaded[name] {
+			continue
+		}
+		loaded[name] = true
+
+		svc, err := h.serviceRepo.FindByName(name)
+		if err != nil {
+			ui.Debug("Cannot load %s, treating it as having no dependencies: %v", name, err)
+			svc = &service.Service{Name: name.String()}
+		}
+		graph.AddService(svc)
+		queue = append(queue, svc.Dependencies.Services...)
+	}
+	return graph
+}
+
+// sortedNames returns the names in set in alphabetical order
+func sortedNames(set map[service.ServiceName]bool) []service.ServiceName {
+	names := make([]service.ServiceName, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
 }
@@ -3,22 +3,26 @@ package commands
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
 	"github.com/vivekkundariya/grund/internal/domain/service"
 )
 
 type mockDownOrchestrator struct {
-	stopErr   error
-	stopCalls int
+	stopErr     error
+	stopCalls   int
+	up          []string // services with a container
+	removeCalls [][]service.ServiceName
 }
 
 func (m *mockDownOrchestrator) StartInfrastructure(ctx context.Context) error {
 	return nil
 }
 
-func (m *mockDownOrchestrator) StartServices(ctx context.Context, services []service.ServiceName) error {
+func (m *mockDownOrchestrator) StartServices(ctx context.Context, services []service.ServiceName, build bool) error {
 	return nil
 }
 
@@ -27,20 +31,33 @@ func (m *mockDownOrchestrator) StopServices(ctx context.Context) error {
 	return m.stopErr
 }
 
+func (m *mockDownOrchestrator) RemoveServices(ctx context.Context, names []service.ServiceName) error {
+	m.removeCalls = append(m.removeCalls, names)
+	return nil
+}
+
 func (m *mockDownOrchestrator) RestartService(ctx context.Context, name service.ServiceName) error {
 	return nil
 }
 
+func (m *mockDownOrchestrator) RecreateServices(ctx context.Context, names []service.ServiceName, build bool) error {
+	return nil
+}
+
 func (m *mockDownOrchestrator) GetServiceStatus(ctx context.Context, name service.ServiceName) (ports.ServiceStatus, error) {
 	return ports.ServiceStatus{}, nil
 }
 
-func (m *mockDownOrchestrator) GetLogs(ctx context.Context, name service.ServiceName, follow bool, tail int) (ports.LogStream, error) {
+func (m *mockDownOrchestrator) GetLogs(ctx context.Context, names []service.ServiceName, opts ports.LogOptions) (ports.LogStream, error) {
 	return nil, nil
 }
 
 func (m *mockDownOrchestrator) GetAllServiceStatuses(ctx context.Context) ([]ports.ServiceStatus, error) {
-	return nil, nil
+	statuses := []ports.ServiceStatus{{Name: "stale", Status: "not running"}}
+	for _, name := range m.up {
+		statuses = append(statuses, ports.ServiceStatus{Name: name, Status: "running"})
+	}
+	return statuses, nil
 }
 
 func (m *mockDownOrchestrator) SetComposeFiles(files []string) {
@@ -49,7 +66,7 @@ func (m *mockDownOrchestrator) SetComposeFiles(files []string) {
 
 func TestDownCommandHandler_Handle_Success(t *testing.T) {
 	orchestrator := &mockDownOrchestrator{}
-	handler := NewDownCommandHandler(orchestrator)
+	handler := NewDownCommandHandler(&mockServiceRepository{}, orchestrator)
 
 	cmd := DownCommand{}
 
@@ -67,7 +84,7 @@ func TestDownCommandHandler_Handle_OrchestratorFails(t *testing.T) {
 	orchestrator := &mockDownOrchestrator{
 		stopErr: fmt.Errorf("docker compose down failed"),
 	}
-	handler := NewDownCommandHandler(orchestrator)
+	handler := NewDownCommandHandler(&mockServiceRepository{}, orchestrator)
 
 	cmd := DownCommand{}
 
@@ -79,7 +96,7 @@ func TestDownCommandHandler_Handle_OrchestratorFails(t *testing.T) {
 
 func TestDownCommandHandler_Handle_MultipleCalls(t *testing.T) {
 	orchestrator := &mockDownOrchestrator{}
-	handler := NewDownCommandHandler(orchestrator)
+	handler := NewDownCommandHandler(&mockServiceRepository{}, orchestrator)
 
 	// Call Handle twice
 	_ = handler.Handle(context.Background(), DownCommand{})
@@ -89,3 +106,99 @@ func TestDownCommandHandler_Handle_MultipleCalls(t *testing.T) {
 		t.Errorf("Expected 2 StopServices calls, got %d", orchestrator.stopCalls)
 	}
 }
+
+// newDownTestRepository registers a chain of services:
+// orders -> payments -> ledger, reports -> ledger, with infrastructure for each
+func newDownTestRepository() *mockServiceRepository {
+	newService := func(name string, deps ...service.ServiceName) *service.Service {
+		svc := &service.Service{Name: name}
+		svc.Dependencies.Services = deps
+		return svc
+	}
+	orders := newService("orders", "payments")
+	orders.Dependencies.Infrastructure.Redis = &infrastructure.RedisConfig{}
+	payments := newService("payments", "ledger")
+	payments.Dependencies.Infrastructure.Postgres = &infrastructure.PostgresConfig{Database: "payments"}
+	ledger := newService("ledger")
+	ledger.Dependencies.Infrastructure.Postgres = &infrastructure.PostgresConfig{Database: "ledger"}
+	reports := newService("reports", "ledger")
+
+	return &mockServiceRepository{services: map[service.ServiceName]*service.Service{
+		"orders": orders, "payments": payments, "ledger": ledger, "reports": reports,
+	}}
+}
+
+func TestDownCommandHandler_Handle_Selective(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  DownCommand
+		up   []string
+		want []service.ServiceName
+	}{
+		{
+			name: "only the named service",
+			cmd:  DownCommand{ServiceNames: []string{"orders"}},
+			up:   []string{"orders", "payments", "ledger", "redis", "postgres"},
+			want: []service.ServiceName{"orders"},
+		},
+		{
+			name: "with deps no one else needs",
+			cmd:  DownCommand{ServiceNames: []string{"orders"}, WithDeps: true},
+			up:   []string{"orders", "payments", "ledger", "redis", "postgres"},
+			want: []service.ServiceName{"orders", "ledger", "payments"},
+		},
+		{
+			name: "with deps keeps what a running service needs",
+			cmd:  DownCommand{ServiceNames: []string{"orders"}, WithDeps: true},
+			up:   []string{"orders", "payments", "ledger", "reports", "redis", "postgres"},
+			want: []service.ServiceName{"orders", "payments"},
+		},
+		{
+			name: "infra no remaining service requires",
+			cmd:  DownCommand{ServiceNames: []string{"orders"}, Infra: true},
+			up:   []string{"orders", "payments", "ledger", "redis", "postgres"},
+			want: []service.ServiceName{"orders", "redis"},
+		},
+		{
+			name: "with deps and infra",
+			cmd:  DownCommand{ServiceNames: []string{"orders"}, WithDeps: true, Infra: true},
+			up:   []string{"orders", "payments", "ledger", "redis", "postgres"},
+			want: []service.ServiceName{"orders", "ledger", "payments", "postgres", "redis"},
+		},
+		{
+			name: "not running services are skipped",
+			cmd:  DownCommand{ServiceNames: []string{"stale", "reports"}},
+			up:   []string{"reports", "ledger"},
+			want: []service.ServiceName{"reports"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orchestrator := &mockDownOrchestrator{up: tt.up}
+			handler := NewDownCommandHandler(newDownTestRepository(), orchestrator)
+
+			if err := handler.Handle(context.Background(), tt.cmd); err != nil {
+				t.Fatalf("Handle() returned error: %v", err)
+			}
+			if orchestrator.stopCalls != 0 {
+				t.Error("a selective down should not stop the whole stack")
+			}
+			if len(orchestrator.removeCalls) != 1 || !reflect.DeepEqual(orchestrator.removeCalls[0], tt.want) {
+				t.Errorf("RemoveServices() calls = %v, want [%v]", orchestrator.removeCalls, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownCommandHandler_Handle_NothingRunning(t *testing.T) {
+	orchestrator := &mockDownOrchestrator{}
+	handler := NewDownCommandHandler(newDownTestRepository(), orchestrator)
+
+	if err := handler.Handle(context.Background(), DownCommand{ServiceNames: []string{"orders"}, WithDeps: true}); err != nil {
+		t.Fatalf("Handle() returned error: %v", err)
+	}
+	if len(orchestrator.removeCalls) != 0 {
+		t.Errorf("RemoveServices() calls = %v, want none", orchestrator.removeCalls)
+	}
+}
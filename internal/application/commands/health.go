@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+	"github.com/vivekkundariya/grund/internal/ui"
+)
+
+// unhealthyLogLines is how many recent log lines are shown for a service that never becomes healthy
+const unhealthyLogLines = 30
+
+// healthWaiter waits for started containers to pass their health checks
+// Shared by up and restart.
+type healthWaiter struct {
+	orchestrator  ports.ContainerOrchestrator
+	healthChecker ports.HealthChecker
+}
+
+// waitForServices polls each container's health endpoint through its published host
+// port, honouring the service's health interval, timeout and retries. A live line per
+// service shows progress; services that never become healthy get their recent logs shown.
+func (w healthWaiter) waitForServices(ctx context.Context, services []*service.Service, names []service.ServiceName) error {
+	inContainer := make(map[string]bool, len(names))
+	for _, name := range names {
+		inContainer[name.String()] = true
+	}
+
+	var checked []*service.Service
+	var boardNames []string
+	for _, svc := range services {
+		if inContainer[svc.Name] && svc.Health.Endpoint != "" {
+			checked = append(checked, svc)
+			boardNames = append(boardNames, svc.Name)
+		}
+	}
+	if len(checked) == 0 {
+		return nil
+	}
+
+	ui.Step("Waiting for services to become healthy...")
+	board := ui.NewStatusBoard(boardNames)
+	for _, name := range boardNames {
+		board.Set(name, "waiting")
+	}
+
+	type result struct {
+		name string
+		err  error
+	}
+	results := make(chan result, len(checked))
+	start := time.Now()
+	for _, svc := range checked {
+		svc := svc
+		go func() {
+			results <- result{name: svc.Name, err: w.waitForHealthy(ctx, svc)}
+		}()
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	pending := len(checked)
+	waiting := make(map[string]bool, len(checked))
+	for _, name := range boardNames {
+		waiting[name] = true
+	}
+	failures := make(map[string]error)
+	for pending > 0 {
+		select {
+		case r := <-results:
+			pending--
+			delete(waiting, r.name)
+			if r.err != nil {
+				failures[r.name] = r.err
+				board.Set(r.name, "✗ unhealthy")
+			} else {
+				board.Set(r.name, "✓ healthy (%s)", time.Since(start).Round(100*time.Millisecond))
+			}
+		case <-ticker.C:
+			for name := range waiting {
+				board.Progress(name, "waiting (%s)", time.Since(start).Round(time.Second))
+			}
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	var failed []string
+	for _, name := range boardNames {
+		if err, ok := failures[name]; ok {
+			failed = append(failed, name)
+			ui.Errorf("%s did not become healthy: %v", name, err)
+			w.showRecentLogs(ctx, service.ServiceName(name))
+		}
+	}
+	return fmt.Errorf("services did not become healthy: %s", strings.Join(failed, ", "))
+}
+
+// waitForHealthy polls a service's health endpoint on its published host port
+func (w healthWaiter) waitForHealthy(ctx context.Context, svc *service.Service) error {
+	status, err := w.orchestrator.GetServiceStatus(ctx, service.ServiceName(svc.Name))
+	if err != nil {
+		return err
+	}
+	if status.Endpoint == "" {
+		return fmt.Errorf("container is %s with no published port", status.Status)
+	}
+
+	endpoint := status.Endpoint + svc.Health.Endpoint
+	if !strings.HasPrefix(svc.Health.Endpoint, "/") {
+		endpoint = status.Endpoint + "/" + svc.Health.Endpoint
+	}
+	return w.healthChecker.WaitForHealthy(ctx, endpoint, svc.Health.Interval, svc.Health.Timeout, svc.Health.Retries)
+}
+
+// showRecentLogs prints the last lines a service logged, to explain a failed start
+func (w healthWaiter) showRecentLogs(ctx context.Context, name service.ServiceName) {
+	stream, err := w.orchestrator.GetLogs(ctx, []service.ServiceName{name}, ports.LogOptions{Tail: unhealthyLogLines})
+	if err != nil {
+		ui.Warnf("Could not read logs of %s: %v", name, err)
+		return
+	}
+	defer stream.Close()
+
+	ui.Infof("Last %d log lines of %s:", unhealthyLogLines, name)
+	for {
+		entry, err := stream.Next()
+		if err != nil {
+			return
+		}
+		ui.SubStep("%s", entry.Message)
+	}
+}
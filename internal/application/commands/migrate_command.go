@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+	"github.com/vivekkundariya/grund/internal/ui"
+)
+
+// MigrateCommand represents the command to run a service's database migrations
+type MigrateCommand struct {
+	ServiceName string
+	Down        int // number of migrations to revert; 0 applies pending migrations
+}
+
+// MigrateCommandHandler handles the migrate command
+type MigrateCommandHandler struct {
+	serviceRepo ports.ServiceRepository
+	migrator    ports.DatabaseMigrator
+}
+
+// NewMigrateCommandHandler creates a new migrate command handler
+func NewMigrateCommandHandler(serviceRepo ports.ServiceRepository, migrator ports.DatabaseMigrator) *MigrateCommandHandler {
+	return &MigrateCommandHandler{
+		serviceRepo: serviceRepo,
+		migrator:    migrator,
+	}
+}
+
+// Handle executes the migrate command
+func (h *MigrateCommandHandler) Handle(ctx context.Context, cmd MigrateCommand) error {
+	svc, err := h.serviceRepo.FindByName(service.ServiceName(cmd.ServiceName))
+	if err != nil {
+		return fmt.Errorf("service %s: %w", cmd.ServiceName, err)
+	}
+
+	pg := svc.Dependencies.Infrastructure.Postgres
+	if pg == nil {
+		return fmt.Errorf("service %s does not require postgres", svc.Name)
+	}
+	if pg.Migrations == "" {
+		return fmt.Errorf("service %s has no postgres migrations configured", svc.Name)
+	}
+	db := pg.AsDatabase()
+
+	if cmd.Down > 0 {
+		ui.Step("Reverting %d migration(s) on %s...", cmd.Down, db.Database)
+		count, err := h.migrator.Rollback(ctx, db, cmd.Down)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			ui.Infof("No applied migrations to revert")
+		} else {
+			ui.Successf("Reverted %d migration(s)", count)
+		}
+		return nil
+	}
+
+	ui.Step("Running migrations on %s...", db.Database)
+	count, err := h.migrator.Migrate(ctx, db)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		ui.Infof("Database %s is up to date", db.Database)
+	} else {
+		ui.Successf("Applied %d migration(s)", count)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+type mockMigrator struct {
+	migrated   []string
+	rolledBack int
+}
+
+func (m *mockMigrator) Migrate(ctx context.Context, db infrastructure.PostgresDatabase) (int, error) {
+	m.migrated = append(m.migrated, db.Database)
+	return 1, nil
+}
+
+func (m *mockMigrator) Rollback(ctx context.Context, db infrastructure.PostgresDatabase, steps int) (int, error) {
+	m.rolledBack += steps
+	return steps, nil
+}
+
+func (m *mockMigrator) Status(ctx context.Context, db infrastructure.PostgresDatabase) ([]ports.MigrationStatus, error) {
+	return nil, nil
+}
+
+func newMigrateTestRepo() *mockServiceRepository {
+	return &mockServiceRepository{
+		services: map[service.ServiceName]*service.Service{
+			"orders": {
+				Name: "orders",
+				Dependencies: service.ServiceDependencies{
+					Infrastructure: infrastructure.InfrastructureRequirements{
+						Postgres: &infrastructure.PostgresConfig{Database: "orders_db", Migrations: "/tmp/migrations"},
+					},
+				},
+			},
+			"web": {Name: "web"},
+		},
+	}
+}
+
+func TestMigrateCommandHandler_Handle_Up(t *testing.T) {
+	migrator := &mockMigrator{}
+	handler := NewMigrateCommandHandler(newMigrateTestRepo(), migrator)
+
+	if err := handler.Handle(context.Background(), MigrateCommand{ServiceName: "orders"}); err != nil {
+		t.Fatalf("Handle() returned error: %v", err)
+	}
+
+	if len(migrator.migrated) != 1 || migrator.migrated[0] != "orders_db" {
+		t.Errorf("Expected migration of orders_db, got %v", migrator.migrated)
+	}
+}
+
+func TestMigrateCommandHandler_Handle_Down(t *testing.T) {
+	migrator := &mockMigrator{}
+	handler := NewMigrateCommandHandler(newMigrateTestRepo(), migrator)
+
+	if err := handler.Handle(context.Background(), MigrateCommand{ServiceName: "orders", Down: 2}); err != nil {
+		t.Fatalf("Handle() returned error: %v", err)
+	}
+
+	if migrator.rolledBack != 2 {
+		t.Errorf("Expected 2 migrations reverted, got %d", migrator.rolledBack)
+	}
+	if len(migrator.migrated) != 0 {
+		t.Errorf("Expected no migrations applied, got %v", migrator.migrated)
+	}
+}
+
+func TestMigrateCommandHandler_Handle_NoPostgres(t *testing.T) {
+	handler := NewMigrateCommandHandler(newMigrateTestRepo(), &mockMigrator{})
+
+	if err := handler.Handle(context.Background(), MigrateCommand{ServiceName: "web"}); err == nil {
+		t.Error("Expected error for service without postgres")
+	}
+}
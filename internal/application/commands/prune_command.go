@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+	"github.com/vivekkundariya/grund/internal/ui"
+)
+
+// PruneCommand represents the command to remove what renamed or removed services left behind
+type PruneCommand struct {
+	DryRun bool // only list what would be removed
+}
+
+// PruneCommandHandler handles the prune command
+type PruneCommandHandler struct {
+	registryRepo ports.ServiceRegistryRepository
+	collector    ports.OrphanCollector
+}
+
+// NewPruneCommandHandler creates a new prune command handler
+func NewPruneCommandHandler(registryRepo ports.ServiceRegistryRepository, collector ports.OrphanCollector) *PruneCommandHandler {
+	return &PruneCommandHandler{
+		registryRepo: registryRepo,
+		collector:    collector,
+	}
+}
+
+// Handle executes the prune command
+func (h *PruneCommandHandler) Handle(ctx context.Context, cmd PruneCommand) error {
+	entries, err := h.registryRepo.GetAllServices()
+	if err != nil {
+		return fmt.Errorf("failed to load services registry: %w", err)
+	}
+	registered := make([]service.ServiceName, 0, len(entries))
+	for name := range entries {
+		registered = append(registered, name)
+	}
+
+	ui.Step("Looking for resources of unregistered services...")
+	orphans, err := h.collector.FindOrphans(ctx, registered)
+	if err != nil {
+		return err
+	}
+	if len(orphans) == 0 {
+		ui.Successf("Nothing to prune")
+		return nil
+	}
+
+	for _, o := range orphans {
+		if o.Service != "" && o.Kind != ports.OrphanComposeFile {
+			ui.SubStep("%s %s (%s)", o.Kind, o.Name, o.Service)
+		} else {
+			ui.SubStep("%s %s", o.Kind, o.Name)
+		}
+	}
+
+	if cmd.DryRun {
+		ui.Infof("Dry run: %d resource(s) would be removed", len(orphans))
+		return nil
+	}
+
+	if err := h.collector.RemoveOrphans(ctx, orphans); err != nil {
+		return err
+	}
+	ui.Successf("Removed %d resource(s)", len(orphans))
+	return nil
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+type mockPruneRegistry struct {
+	mockRegistryRepository
+	names []service.ServiceName
+}
+
+func (m *mockPruneRegistry) GetAllServices() (map[service.ServiceName]ports.ServiceEntry, error) {
+	entries := make(map[service.ServiceName]ports.ServiceEntry, len(m.names))
+	for _, name := range m.names {
+		entries[name] = ports.ServiceEntry{}
+	}
+	return entries, nil
+}
+
+type mockOrphanCollector struct {
+	orphans    []ports.Orphan
+	registered []service.ServiceName
+	removed    []ports.Orphan
+}
+
+func (m *mockOrphanCollector) FindOrphans(ctx context.Context, registered []service.ServiceName) ([]ports.Orphan, error) {
+	m.registered = registered
+	return m.orphans, nil
+}
+
+func (m *mockOrphanCollector) RemoveOrphans(ctx context.Context, orphans []ports.Orphan) error {
+	m.removed = orphans
+	return nil
+}
+
+func TestPruneCommandHandler_Handle(t *testing.T) {
+	orphans := []ports.Orphan{
+		{Kind: ports.OrphanComposeFile, Name: "/tmp/grund/old-billing", Service: "old-billing"},
+		{Kind: ports.OrphanContainer, Name: "grund-old-billing", Service: "old-billing"},
+	}
+
+	t.Run("removes orphans", func(t *testing.T) {
+		collector := &mockOrphanCollector{orphans: orphans}
+		handler := NewPruneCommandHandler(&mockPruneRegistry{names: []service.ServiceName{"billing"}}, collector)
+
+		if err := handler.Handle(context.Background(), PruneCommand{}); err != nil {
+			t.Fatalf("Handle() returned error: %v", err)
+		}
+		if len(collector.registered) != 1 || collector.registered[0] != "billing" {
+			t.Errorf("FindOrphans() registered = %v, want [billing]", collector.registered)
+		}
+		if len(collector.removed) != len(orphans) {
+			t.Errorf("RemoveOrphans() = %v, want %v", collector.removed, orphans)
+		}
+	})
+
+	t.Run("dry run removes nothing", func(t *testing.T) {
+		collector := &mockOrphanCollector{orphans: orphans}
+		handler := NewPruneCommandHandler(&mockPruneRegistry{}, collector)
+
+		if err := handler.Handle(context.Background(), PruneCommand{DryRun: true}); err != nil {
+			t.Fatalf("Handle() returned error: %v", err)
+		}
+		if collector.removed != nil {
+			t.Errorf("RemoveOrphans() called with %v during a dry run", collector.removed)
+		}
+	})
+}
@@ -2,35 +2,271 @@ package commands
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
 	"github.com/vivekkundariya/grund/internal/domain/service"
+	"github.com/vivekkundariya/grund/internal/ui"
 )
 
 // RestartCommand represents the command to restart services
 type RestartCommand struct {
 	ServiceNames []string
-	Build        bool
+	Build        bool // rebuild images even when their build context is unchanged
+	NoWait       bool // return once containers are recreated, without waiting for health checks
 }
 
 // RestartCommandHandler handles the restart command
 type RestartCommandHandler struct {
-	orchestrator ports.ContainerOrchestrator
+	serviceRepo      ports.ServiceRepository
+	orchestrator     ports.ContainerOrchestrator
+	composeGenerator ports.ComposeGenerator
+	healthChecker    ports.HealthChecker
 }
 
 // NewRestartCommandHandler creates a new restart command handler
-func NewRestartCommandHandler(orchestrator ports.ContainerOrchestrator) *RestartCommandHandler {
+func NewRestartCommandHandler(
+	serviceRepo ports.ServiceRepository,
+	orchestrator ports.ContainerOrchestrator,
+	composeGenerator ports.ComposeGenerator,
+	healthChecker ports.HealthChecker,
+) *RestartCommandHandler {
 	return &RestartCommandHandler{
-		orchestrator: orchestrator,
+		serviceRepo:      serviceRepo,
+		orchestrator:     orchestrator,
+		composeGenerator: composeGenerator,
+		healthChecker:    healthChecker,
 	}
 }
 
 // Handle executes the restart command
+// Each service's grund.yaml is re-read and its compose file regenerated, so env_refs and
+// secrets are resolved again. The containers are then recreated, rebuilding images whose
+// build context changed (or all of them with Build), and waited on until healthy.
 func (h *RestartCommandHandler) Handle(ctx context.Context, cmd RestartCommand) error {
-	for _, name := range cmd.ServiceNames {
-		if err := h.orchestrator.RestartService(ctx, service.ServiceName(name)); err != nil {
+	if len(cmd.ServiceNames) == 0 {
+		return nil
+	}
+	names := make([]service.ServiceName, len(cmd.ServiceNames))
+	for i, name := range cmd.ServiceNames {
+		names[i] = service.ServiceName(name)
+	}
+
+	// Peers are loaded too, so env_refs to them resolve as under grund up
+	ui.Step("Reloading service configurations...")
+	services, err := h.loadServices(names)
+	if err != nil {
+		return fmt.Errorf("failed to load services: %w", err)
+	}
+	reqs := make([]infrastructure.InfrastructureRequirements, len(services))
+	for i, svc := range services {
+		reqs[i] = svc.Dependencies.Infrastructure
+	}
+	infra := infrastructure.Aggregate(reqs...)
+
+	// Tunnel URLs only exist while grund up runs them, so a restarted service using one can't
+	// be resolved here; dependencies with tunnels are fine, their compose files aren't touched
+	restarting := make(map[string]bool, len(names))
+	for _, name := range names {
+		restarting[name.String()] = true
+	}
+	var tunneled []string
+	for _, svc := range services {
+		if restarting[svc.Name] && usesTunnel(svc) {
+			tunneled = append(tunneled, svc.Name)
+		}
+	}
+	if len(tunneled) > 0 {
+		return fmt.Errorf("%s uses tunnels, which only grund up runs; restart with 'grund up %s' instead",
+			strings.Join(tunneled, ", "), strings.Join(cmd.ServiceNames, " "))
+	}
+
+	previous := make(map[service.ServiceName]map[string]string, len(names))
+	for _, name := range names {
+		env, err := h.composeGenerator.GeneratedEnv(name)
+		if err != nil {
+			ui.Debug("Cannot read the current environment of %s: %v", name, err)
+		}
+		previous[name] = env
+	}
+
+	ui.Step("Regenerating docker-compose configuration...")
+	fileSet, err := h.composeGenerator.GenerateServices(services, infra, names)
+	if err != nil {
+		return fmt.Errorf("failed to generate compose file: %w", err)
+	}
+	h.orchestrator.SetComposeFiles(fileSet.AllPaths())
+
+	// Services grund up ran on the host only get a new env file; their process isn't ours to restart
+	var containers []service.ServiceName
+	for _, name := range names {
+		if path, ok := fileSet.LocalEnvPaths[name.String()]; ok {
+			ui.Infof("%s runs on the host; restart it to pick up %s", name, path)
+			continue
+		}
+		containers = append(containers, name)
+	}
+	if len(containers) == 0 {
+		return nil
+	}
+
+	for _, svc := range services {
+		name := service.ServiceName(svc.Name)
+		if _, ok := previous[name]; !ok || fileSet.LocalEnvPaths[svc.Name] != "" {
+			continue
+		}
+		current, err := h.composeGenerator.GeneratedEnv(name)
+		if err != nil {
+			return err
+		}
+		reportEnvChanges(svc, previous[name], current)
+	}
+
+	// Rebuild where the build context changed, so the manifest never records a stale image as current
+	ui.Step("Recreating containers...")
+	var rebuild, recreate []service.ServiceName
+	for _, name := range containers {
+		change, known := fileSet.Changes[name.String()]
+		if cmd.Build || !known || change.Build {
+			rebuild = append(rebuild, name)
+		} else {
+			recreate = append(recreate, name)
+		}
+	}
+	if len(rebuild) > 0 {
+		if err := h.orchestrator.RecreateServices(ctx, rebuild, true); err != nil {
+			return err
+		}
+	}
+	if len(recreate) > 0 {
+		if err := h.orchestrator.RecreateServices(ctx, recreate, false); err != nil {
+			return err
+		}
+	}
+	if err := h.composeGenerator.RecordStarted(containers); err != nil {
+		ui.Warnf("Failed to record restarted services, the next grund up will start them again: %v", err)
+	}
+
+	if !cmd.NoWait {
+		waiter := healthWaiter{orchestrator: h.orchestrator, healthChecker: h.healthChecker}
+		if err := waiter.waitForServices(ctx, services, containers); err != nil {
 			return err
 		}
 	}
+	ui.Successf("Restarted %s", joinServiceNames(containers))
 	return nil
 }
+
+// usesTunnel reports whether a service runs a tunnel or its env_refs read a tunnel's URL
+func usesTunnel(svc *service.Service) bool {
+	if svc.Dependencies.Infrastructure.Tunnel != nil {
+		return true
+	}
+	for _, ref := range svc.Environment.References {
+		if strings.Contains(ref, "${tunnel.") {
+			return true
+		}
+	}
+	return false
+}
+
+// loadServices loads the named services and their transitive dependencies
+func (h *RestartCommandHandler) loadServices(names []service.ServiceName) ([]*service.Service, error) {
+	var services []*service.Service
+	loaded := make(map[service.ServiceName]bool)
+	queue := append([]service.ServiceName{}, names...)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if loaded[name] {
+			continue
+		}
+		loaded[name] = true
+
+		svc, err := h.serviceRepo.FindByName(name)
+		if err != nil {
+			return nil, fmt.Errorf("service %s: %w", name, err)
+		}
+		services = append(services, svc)
+		queue = append(queue, svc.Dependencies.Services...)
+	}
+	return services, nil
+}
+
+// envChange is one environment variable that differs between two resolved environments
+type envChange struct {
+	Key      string
+	Old, New string
+	Added    bool
+	Removed  bool
+}
+
+// diffEnv lists the variables added, removed or changed from old to new, sorted by key
+func diffEnv(old, new map[string]string) []envChange {
+	var changes []envChange
+	for key, value := range new {
+		previous, ok := old[key]
+		switch {
+		case !ok:
+			changes = append(changes, envChange{Key: key, New: value, Added: true})
+		case previous != value:
+			changes = append(changes, envChange{Key: key, Old: previous, New: value})
+		}
+	}
+	for key, value := range old {
+		if _, ok := new[key]; !ok {
+			changes = append(changes, envChange{Key: key, Old: value, Removed: true})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// reportEnvChanges prints how a service's environment changed; secret values are never shown
+func reportEnvChanges(svc *service.Service, old, new map[string]string) {
+	if old == nil {
+		ui.Infof("%s had no compose file, generated a new one", svc.Name)
+		return
+	}
+	changes := diffEnv(old, new)
+	if len(changes) == 0 {
+		ui.Infof("%s: no environment changes", svc.Name)
+		return
+	}
+
+	secrets := secretEnvKeys(svc)
+	ui.Infof("%s: %d environment variable(s) changed", svc.Name, len(changes))
+	for _, c := range changes {
+		secret := secrets[c.Key]
+		switch {
+		case c.Added && secret:
+			ui.SubStep("+ %s (secret)", c.Key)
+		case c.Added:
+			ui.SubStep("+ %s=%s", c.Key, c.New)
+		case c.Removed:
+			ui.SubStep("- %s", c.Key)
+		case secret:
+			ui.SubStep("~ %s (secret changed)", c.Key)
+		default:
+			ui.SubStep("~ %s: %s -> %s", c.Key, c.Old, c.New)
+		}
+	}
+}
+
+// secretEnvKeys returns the variables that hold a secret: the secrets themselves and
+// env_refs that interpolate one
+func secretEnvKeys(svc *service.Service) map[string]bool {
+	keys := make(map[string]bool, len(svc.Environment.Secrets))
+	for key := range svc.Environment.Secrets {
+		keys[key] = true
+	}
+	for key, ref := range svc.Environment.References {
+		if strings.Contains(ref, "${secret.") {
+			keys[key] = true
+		}
+	}
+	return keys
+}
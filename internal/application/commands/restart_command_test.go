@@ -3,133 +3,171 @@ package commands
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
 	"github.com/vivekkundariya/grund/internal/domain/service"
 )
 
-type mockRestartOrchestrator struct {
-	restartErr   error
-	restartCalls []service.ServiceName
-}
-
-func (m *mockRestartOrchestrator) StartInfrastructure(ctx context.Context) error {
-	return nil
-}
-
-func (m *mockRestartOrchestrator) StartServices(ctx context.Context, services []service.ServiceName) error {
-	return nil
-}
-
-func (m *mockRestartOrchestrator) StopServices(ctx context.Context) error {
-	return nil
-}
-
-func (m *mockRestartOrchestrator) RestartService(ctx context.Context, name service.ServiceName) error {
-	m.restartCalls = append(m.restartCalls, name)
-	return m.restartErr
-}
-
-func (m *mockRestartOrchestrator) GetServiceStatus(ctx context.Context, name service.ServiceName) (ports.ServiceStatus, error) {
-	return ports.ServiceStatus{}, nil
-}
-
-func (m *mockRestartOrchestrator) GetLogs(ctx context.Context, name service.ServiceName, follow bool, tail int) (ports.LogStream, error) {
-	return nil, nil
-}
-
-func (m *mockRestartOrchestrator) GetAllServiceStatuses(ctx context.Context) ([]ports.ServiceStatus, error) {
-	return nil, nil
-}
+func TestRestartCommandHandler_Handle(t *testing.T) {
+	tests := []struct {
+		name        string
+		build       bool
+		changes     map[string]ports.ServiceChange
+		wantRebuilt bool
+	}{
+		{"config only", false, map[string]ports.ServiceChange{"service-a": {Config: true}}, false},
+		{"build context changed", false, map[string]ports.ServiceChange{"service-a": {Build: true}}, true},
+		{"forced rebuild", true, map[string]ports.ServiceChange{"service-a": {}}, true},
+	}
 
-func (m *mockRestartOrchestrator) SetComposeFiles(files []string) {
-	// no-op for tests
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockServiceRepository{services: map[service.ServiceName]*service.Service{
+				"service-a": createTestService("service-a", []string{"service-b"}),
+				"service-b": createTestService("service-b", nil),
+			}}
+			orchestrator := &mockOrchestrator{}
+			generator := &mockComposeGenerator{changes: tt.changes}
+			healthChecker := &mockHealthChecker{}
+
+			handler := NewRestartCommandHandler(repo, orchestrator, generator, healthChecker)
+			if err := handler.Handle(context.Background(), RestartCommand{ServiceNames: []string{"service-a"}, Build: tt.build}); err != nil {
+				t.Fatalf("Handle() returned error: %v", err)
+			}
+
+			if fmt.Sprint(generator.regenerated) != "[service-a]" {
+				t.Errorf("regenerated = %v, want only service-a", generator.regenerated)
+			}
+			if rebuilt, ok := orchestrator.recreated["service-a"]; !ok || rebuilt != tt.wantRebuilt || len(orchestrator.recreated) != 1 {
+				t.Errorf("recreated = %v, want service-a with build=%v", orchestrator.recreated, tt.wantRebuilt)
+			}
+			if fmt.Sprint(generator.recorded) != "[service-a]" {
+				t.Errorf("recorded = %v, want service-a", generator.recorded)
+			}
+			if fmt.Sprint(healthChecker.checked) != "[http://service-a.test/health]" {
+				t.Errorf("checked endpoints = %v, want service-a", healthChecker.checked)
+			}
+		})
+	}
 }
 
-func TestRestartCommandHandler_Handle_SingleService(t *testing.T) {
-	orchestrator := &mockRestartOrchestrator{}
-	handler := NewRestartCommandHandler(orchestrator)
+func TestRestartCommandHandler_Handle_LocalService(t *testing.T) {
+	repo := &mockServiceRepository{services: map[service.ServiceName]*service.Service{
+		"service-a": createTestService("service-a", nil),
+		"service-b": createTestService("service-b", nil),
+	}}
+	orchestrator := &mockOrchestrator{}
+	generator := &mockComposeGenerator{localEnvPaths: map[string]string{"service-b": "/tmp/service-b/local.env"}}
 
-	cmd := RestartCommand{
-		ServiceNames: []string{"service-a"},
-	}
-
-	err := handler.Handle(context.Background(), cmd)
-	if err != nil {
+	handler := NewRestartCommandHandler(repo, orchestrator, generator, &mockHealthChecker{})
+	if err := handler.Handle(context.Background(), RestartCommand{ServiceNames: []string{"service-a", "service-b"}}); err != nil {
 		t.Fatalf("Handle() returned error: %v", err)
 	}
 
-	if len(orchestrator.restartCalls) != 1 {
-		t.Fatalf("Expected 1 RestartService call, got %d", len(orchestrator.restartCalls))
+	if _, ok := orchestrator.recreated["service-b"]; ok || len(orchestrator.recreated) != 1 {
+		t.Errorf("recreated = %v, want only service-a; service-b runs on the host", orchestrator.recreated)
 	}
-
-	if orchestrator.restartCalls[0] != "service-a" {
-		t.Errorf("Expected restart of 'service-a', got %q", orchestrator.restartCalls[0])
+	if fmt.Sprint(generator.recorded) != "[service-a]" {
+		t.Errorf("recorded = %v, want service-a", generator.recorded)
 	}
 }
 
-func TestRestartCommandHandler_Handle_MultipleServices(t *testing.T) {
-	orchestrator := &mockRestartOrchestrator{}
-	handler := NewRestartCommandHandler(orchestrator)
+func TestRestartCommandHandler_Handle_ServiceNotFound(t *testing.T) {
+	orchestrator := &mockOrchestrator{}
+	handler := NewRestartCommandHandler(&mockServiceRepository{}, orchestrator, &mockComposeGenerator{}, &mockHealthChecker{})
 
-	cmd := RestartCommand{
-		ServiceNames: []string{"service-a", "service-b", "service-c"},
+	if err := handler.Handle(context.Background(), RestartCommand{ServiceNames: []string{"missing"}}); err == nil {
+		t.Fatal("Expected error for an unknown service")
 	}
-
-	err := handler.Handle(context.Background(), cmd)
-	if err != nil {
-		t.Fatalf("Handle() returned error: %v", err)
+	if len(orchestrator.recreated) != 0 {
+		t.Errorf("Expected nothing recreated, got %v", orchestrator.recreated)
 	}
+}
 
-	if len(orchestrator.restartCalls) != 3 {
-		t.Fatalf("Expected 3 RestartService calls, got %d", len(orchestrator.restartCalls))
+func TestRestartCommandHandler_Handle_Tunnels(t *testing.T) {
+	tunnel := &infrastructure.TunnelRequirement{Provider: "cloudflared"}
+	tests := []struct {
+		name    string
+		setup   func(a, b *service.Service)
+		refused bool
+	}{
+		{"declares a tunnel", func(a, b *service.Service) { a.Dependencies.Infrastructure.Tunnel = tunnel }, true},
+		{"reads a tunnel URL", func(a, b *service.Service) {
+			a.Environment.References = map[string]string{"WEBHOOK_URL": "${tunnel.webhooks.url}/hooks"}
+		}, true},
+		{"dependency declares a tunnel", func(a, b *service.Service) { b.Dependencies.Infrastructure.Tunnel = tunnel }, false},
 	}
 
-	// Verify order
-	expected := []service.ServiceName{"service-a", "service-b", "service-c"}
-	for i, name := range orchestrator.restartCalls {
-		if name != expected[i] {
-			t.Errorf("Call %d: expected %q, got %q", i, expected[i], name)
-		}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := createTestService("service-a", []string{"service-b"})
+			b := createTestService("service-b", nil)
+			tt.setup(a, b)
+			repo := &mockServiceRepository{services: map[service.ServiceName]*service.Service{"service-a": a, "service-b": b}}
+			orchestrator := &mockOrchestrator{}
+			generator := &mockComposeGenerator{}
+
+			handler := NewRestartCommandHandler(repo, orchestrator, generator, &mockHealthChecker{})
+			err := handler.Handle(context.Background(), RestartCommand{ServiceNames: []string{"service-a"}})
+
+			if !tt.refused {
+				if err != nil || fmt.Sprint(generator.regenerated) != "[service-a]" {
+					t.Errorf("Handle() = %v, regenerated = %v; want service-a restarted", err, generator.regenerated)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "grund up service-a") {
+				t.Fatalf("Handle() error = %v, want it to point at grund up", err)
+			}
+			if len(generator.regenerated) != 0 || len(orchestrator.recreated) != 0 || len(orchestrator.restartCalls) != 0 {
+				t.Errorf("regenerated = %v, recreated = %v, restarts = %v; want nothing touched",
+					generator.regenerated, orchestrator.recreated, orchestrator.restartCalls)
+			}
+		})
 	}
 }
 
-func TestRestartCommandHandler_Handle_ErrorStopsProcessing(t *testing.T) {
-	orchestrator := &mockRestartOrchestrator{
-		restartErr: fmt.Errorf("restart failed"),
-	}
-	handler := NewRestartCommandHandler(orchestrator)
-
-	cmd := RestartCommand{
-		ServiceNames: []string{"service-a", "service-b"},
-	}
+func TestRestartCommandHandler_Handle_EmptyServices(t *testing.T) {
+	orchestrator := &mockOrchestrator{}
+	handler := NewRestartCommandHandler(&mockServiceRepository{}, orchestrator, &mockComposeGenerator{}, &mockHealthChecker{})
 
-	err := handler.Handle(context.Background(), cmd)
-	if err == nil {
-		t.Fatal("Expected error when restart fails, got nil")
+	if err := handler.Handle(context.Background(), RestartCommand{}); err != nil {
+		t.Fatalf("Handle() returned error for empty services: %v", err)
 	}
-
-	// Should have only tried to restart the first service
-	if len(orchestrator.restartCalls) != 1 {
-		t.Errorf("Expected 1 RestartService call (stopped on error), got %d", len(orchestrator.restartCalls))
+	if len(orchestrator.recreated) != 0 || len(orchestrator.restartCalls) != 0 {
+		t.Error("Expected no containers touched for an empty list")
 	}
 }
 
-func TestRestartCommandHandler_Handle_EmptyServices(t *testing.T) {
-	orchestrator := &mockRestartOrchestrator{}
-	handler := NewRestartCommandHandler(orchestrator)
+func TestDiffEnv(t *testing.T) {
+	old := map[string]string{"LOG_LEVEL": "info", "PORT": "8080", "LEGACY": "1"}
+	new := map[string]string{"LOG_LEVEL": "debug", "PORT": "8080", "FEATURE_X": "on"}
 
-	cmd := RestartCommand{
-		ServiceNames: []string{},
+	got := diffEnv(old, new)
+	want := []envChange{
+		{Key: "FEATURE_X", New: "on", Added: true},
+		{Key: "LEGACY", Old: "1", Removed: true},
+		{Key: "LOG_LEVEL", Old: "info", New: "debug"},
 	}
-
-	err := handler.Handle(context.Background(), cmd)
-	if err != nil {
-		t.Fatalf("Handle() returned error for empty services: %v", err)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("diffEnv() = %+v, want %+v", got, want)
 	}
+}
 
-	if len(orchestrator.restartCalls) != 0 {
-		t.Errorf("Expected 0 RestartService calls for empty list, got %d", len(orchestrator.restartCalls))
+func TestSecretEnvKeys(t *testing.T) {
+	svc := &service.Service{Environment: service.Environment{
+		References: map[string]string{
+			"DATABASE_URL": "postgres://app:${secret.DB_PASSWORD}@${postgres.host}/app",
+			"REDIS_HOST":   "${redis.host}",
+		},
+		Secrets: map[string]service.SecretRequirement{"API_KEY": {}},
+	}}
+
+	got := secretEnvKeys(svc)
+	if len(got) != 2 || !got["DATABASE_URL"] || !got["API_KEY"] {
+		t.Errorf("secretEnvKeys() = %v, want DATABASE_URL and API_KEY", got)
 	}
 }
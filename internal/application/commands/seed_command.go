@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+	"github.com/vivekkundariya/grund/internal/ui"
+)
+
+// SeedCommand represents the command to load a service's seed data
+type SeedCommand struct {
+	ServiceName string
+	Force       bool // reapply even if the database was already seeded
+}
+
+// SeedCommandHandler handles the seed command
+type SeedCommandHandler struct {
+	serviceRepo ports.ServiceRepository
+	seeder      ports.DatabaseSeeder
+}
+
+// NewSeedCommandHandler creates a new seed command handler
+func NewSeedCommandHandler(serviceRepo ports.ServiceRepository, seeder ports.DatabaseSeeder) *SeedCommandHandler {
+	return &SeedCommandHandler{
+		serviceRepo: serviceRepo,
+		seeder:      seeder,
+	}
+}
+
+// Handle executes the seed command
+func (h *SeedCommandHandler) Handle(ctx context.Context, cmd SeedCommand) error {
+	svc, err := h.serviceRepo.FindByName(service.ServiceName(cmd.ServiceName))
+	if err != nil {
+		return fmt.Errorf("service %s: %w", cmd.ServiceName, err)
+	}
+
+	infra := svc.Dependencies.Infrastructure
+	hasPostgresSeed := infra.Postgres != nil && infra.Postgres.Seed != ""
+	hasMongoSeed := infra.MongoDB != nil && infra.MongoDB.Seed != ""
+	if !hasPostgresSeed && !hasMongoSeed {
+		return fmt.Errorf("service %s has no postgres or mongodb seed configured", svc.Name)
+	}
+
+	if hasPostgresSeed {
+		db := infra.Postgres.AsDatabase()
+		ui.Step("Seeding Postgres database %s...", db.Database)
+		seeded, err := h.seeder.SeedPostgres(ctx, db, cmd.Force)
+		if err != nil {
+			return fmt.Errorf("postgres: %w", err)
+		}
+		reportSeed(db.Database, seeded)
+	}
+
+	if hasMongoSeed {
+		db := infra.MongoDB.AsDatabase()
+		ui.Step("Seeding MongoDB database %s...", db.Database)
+		seeded, err := h.seeder.SeedMongoDB(ctx, db, cmd.Force)
+		if err != nil {
+			return fmt.Errorf("mongodb: %w", err)
+		}
+		reportSeed(db.Database, seeded)
+	}
+
+	return nil
+}
+
+func reportSeed(database string, seeded bool) {
+	if seeded {
+		ui.Successf("Seeded %s", database)
+	} else {
+		ui.Infof("%s was already seeded (use --force to reapply)", database)
+	}
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+type mockSeeder struct {
+	postgres []string
+	mongodb  []string
+	forced   bool
+}
+
+func (m *mockSeeder) SeedPostgres(ctx context.Context, db infrastructure.PostgresDatabase, force bool) (bool, error) {
+	m.postgres = append(m.postgres, db.Database)
+	m.forced = force
+	return true, nil
+}
+
+func (m *mockSeeder) SeedMongoDB(ctx context.Context, db infrastructure.MongoDBDatabase, force bool) (bool, error) {
+	m.mongodb = append(m.mongodb, db.Database)
+	m.forced = force
+	return true, nil
+}
+
+func newSeedTestRepo() *mockServiceRepository {
+	return &mockServiceRepository{
+		services: map[service.ServiceName]*service.Service{
+			"catalog": {
+				Name: "catalog",
+				Dependencies: service.ServiceDependencies{
+					Infrastructure: infrastructure.InfrastructureRequirements{
+						Postgres: &infrastructure.PostgresConfig{Database: "catalog_db", Seed: "/seed.sql"},
+						MongoDB:  &infrastructure.MongoDBConfig{Database: "catalog", Seed: "/seed"},
+					},
+				},
+			},
+			"web": {
+				Name: "web",
+				Dependencies: service.ServiceDependencies{
+					Infrastructure: infrastructure.InfrastructureRequirements{
+						Postgres: &infrastructure.PostgresConfig{Database: "web_db"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestSeedCommandHandler_Handle_SeedsBothDatabases(t *testing.T) {
+	seeder := &mockSeeder{}
+	handler := NewSeedCommandHandler(newSeedTestRepo(), seeder)
+
+	if err := handler.Handle(context.Background(), SeedCommand{ServiceName: "catalog", Force: true}); err != nil {
+		t.Fatalf("Handle() returned error: %v", err)
+	}
+
+	if len(seeder.postgres) != 1 || seeder.postgres[0] != "catalog_db" {
+		t.Errorf("Expected postgres seed of catalog_db, got %v", seeder.postgres)
+	}
+	if len(seeder.mongodb) != 1 || seeder.mongodb[0] != "catalog" {
+		t.Errorf("Expected mongodb seed of catalog, got %v", seeder.mongodb)
+	}
+	if !seeder.forced {
+		t.Error("Expected force to be passed to the seeder")
+	}
+}
+
+func TestSeedCommandHandler_Handle_NoSeed(t *testing.T) {
+	handler := NewSeedCommandHandler(newSeedTestRepo(), &mockSeeder{})
+
+	if err := handler.Handle(context.Background(), SeedCommand{ServiceName: "web"}); err == nil {
+		t.Error("Expected error for service without seed")
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/vivekkundariya/grund/internal/application/ports"
 	"github.com/vivekkundariya/grund/internal/config"
+	"github.com/vivekkundariya/grund/internal/domain/dependency"
 	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
 	"github.com/vivekkundariya/grund/internal/domain/service"
 	"github.com/vivekkundariya/grund/internal/ui"
@@ -18,7 +20,9 @@ type UpCommand struct {
 	NoDeps       bool
 	InfraOnly    bool
 	Build        bool
-	Local        bool
+	Local        []string // services to run on the host instead of in a container
+	Watch        bool     // keep running and restart hot_reload services when their files change
+	NoWait       bool     // return once containers are started, without waiting for health checks
 }
 
 // UpCommandHandler handles the up command
@@ -30,9 +34,16 @@ type UpCommandHandler struct {
 	provisioner      ports.InfrastructureProvisioner
 	composeGenerator ports.ComposeGenerator
 	healthChecker    ports.HealthChecker
-	tunnelManager    ports.TunnelManager // optional, can be nil
+	tunnelManager    ports.TunnelManager       // optional, can be nil
+	fileWatcher      ports.FileWatcher         // optional, can be nil
+	prerequisites    ports.PrerequisiteChecker // optional, can be nil
+
+	statusPollInterval time.Duration // how often container status is checked while waiting on a dependency
 }
 
+// minDependencyWaitTimeout is the least time a wait_for dependency is given to become ready
+const minDependencyWaitTimeout = time.Minute
+
 // NewUpCommandHandler creates a new up command handler
 func NewUpCommandHandler(
 	serviceRepo ports.ServiceRepository,
@@ -42,6 +53,8 @@ func NewUpCommandHandler(
 	composeGenerator ports.ComposeGenerator,
 	healthChecker ports.HealthChecker,
 	tunnelManager ports.TunnelManager,
+	fileWatcher ports.FileWatcher,
+	prerequisites ports.PrerequisiteChecker,
 ) *UpCommandHandler {
 	return &UpCommandHandler{
 		serviceRepo:      serviceRepo,
@@ -51,6 +64,10 @@ func NewUpCommandHandler(
 		composeGenerator: composeGenerator,
 		healthChecker:    healthChecker,
 		tunnelManager:    tunnelManager,
+		fileWatcher:      fileWatcher,
+		prerequisites:    prerequisites,
+
+		statusPollInterval: time.Second,
 	}
 }
 
@@ -73,20 +90,31 @@ func (h *UpCommandHandler) Handle(ctx context.Context, cmd UpCommand) error {
 	ui.Debug("Loaded %d service(s)", len(services))
 
 	// 2. Build service names list from all loaded services
-	// Note: We no longer enforce startup order between services.
-	// Services start in parallel after infrastructure is ready.
-	// They should handle reconnection to other services themselves.
+	// Services start in parallel after infrastructure is ready unless a dependency
+	// sets wait_for, in which case they start in dependency-ordered waves (step 9).
 	serviceNames := make([]service.ServiceName, len(services))
 	for i, svc := range services {
 		serviceNames[i] = service.ServiceName(svc.Name)
 	}
 	ui.Debug("Services to start: %v", serviceNames)
 
+	// 3. Split off services the developer runs on the host (--local)
+	localNames, containerNames, err := splitLocalServices(serviceNames, cmd.Local)
+	if err != nil {
+		return err
+	}
+	h.composeGenerator.SetLocalServices(localNames)
+
 	// 5. Aggregate infrastructure requirements
 	infraReqs := h.aggregateInfrastructure(services)
 	ui.Debug("Infrastructure requirements: postgres=%v, mongodb=%v, redis=%v, sqs=%v",
 		infraReqs.Postgres != nil, infraReqs.MongoDB != nil, infraReqs.Redis != nil, infraReqs.SQS != nil)
 
+	// 5.2. Fail before starting anything when a host port is taken by another environment or process
+	if err := h.checkHostPorts(ctx, services, infraReqs, localNames); err != nil {
+		return err
+	}
+
 	// 5.5. Start tunnels FIRST if configured (so tunnel URLs are available for env_refs)
 	// Tunnels point to localhost ports that will be bound by infrastructure containers
 	var tunnelContext map[string]ports.TunnelContext
@@ -102,7 +130,8 @@ func (h *UpCommandHandler) Handle(ctx context.Context, cmd UpCommand) error {
 
 	// 6. Generate docker-compose (now with tunnel context for env_refs resolution)
 	ui.Step("Generating docker-compose configuration...")
-	if err := h.generateCompose(services, infraReqs, tunnelContext); err != nil {
+	fileSet, err := h.generateCompose(services, infraReqs, tunnelContext)
+	if err != nil {
 		return fmt.Errorf("failed to generate compose file: %w", err)
 	}
 	ui.Successf("Docker compose file generated")
@@ -126,20 +155,182 @@ func (h *UpCommandHandler) Handle(ctx context.Context, cmd UpCommand) error {
 		ui.Successf("AWS resources provisioned")
 	}
 
-	// 9. Start services in parallel (no ordering enforced)
-	if !cmd.InfraOnly {
+	// 9. Start services, in waves when dependencies set wait_for
+	if cmd.InfraOnly {
+		ui.Infof("Infrastructure only mode - skipping service startup")
+	} else if len(containerNames) > 0 {
 		ui.Step("Starting application services...")
-		if err := h.startServices(ctx, serviceNames); err != nil {
-			return fmt.Errorf("failed to start services: %w", err)
+		started, rebuild := h.planStart(ctx, containerNames, fileSet.Changes, cmd.Build)
+		if len(started) == 0 {
+			ui.Successf("All services up to date")
+		} else {
+			if err := h.startServices(ctx, services, started, rebuild); err != nil {
+				return fmt.Errorf("failed to start services: %w", err)
+			}
+			if err := h.composeGenerator.RecordStarted(started); err != nil {
+				ui.Warnf("Failed to record started services, the next grund up will start them again: %v", err)
+			}
+
+			if cmd.NoWait {
+				ui.Successf("All services started")
+			} else {
+				if err := h.healthWaiter().waitForServices(ctx, services, started); err != nil {
+					return err
+				}
+				ui.Successf("All services started successfully")
+			}
+		}
+	}
+
+	// 10. Tell the developer how to run the host services
+	h.reportLocalServices(ctx, localNames, fileSet)
+
+	// 11. Restart hot_reload containers on source changes until interrupted
+	if cmd.Watch && !cmd.InfraOnly {
+		return h.watchServices(ctx, services, containerNames)
+	}
+
+	return nil
+}
+
+// watchServices restarts each hot_reload container when files in its mounted source change
+// Blocks until ctx is cancelled. The source is bind-mounted, so a restart picks up the
+// new files without rebuilding the image.
+func (h *UpCommandHandler) watchServices(ctx context.Context, services []*service.Service, containerNames []service.ServiceName) error {
+	if h.fileWatcher == nil {
+		return fmt.Errorf("file watching is not available")
+	}
+
+	inContainer := make(map[string]bool, len(containerNames))
+	for _, name := range containerNames {
+		inContainer[name.String()] = true
+	}
+
+	var watched []*service.Service
+	for _, svc := range services {
+		if inContainer[svc.Name] && svc.Run != nil && svc.Run.HotReload && svc.Run.Source != "" {
+			watched = append(watched, svc)
+		}
+	}
+	if len(watched) == 0 {
+		ui.Warnf("No running services have run.hot_reload enabled; nothing to watch")
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, len(watched))
+	for _, svc := range watched {
+		svc := svc
+		ui.Infof("Watching %s for changes in %s", svc.Name, svc.Run.Source)
+		go func() {
+			errs <- h.fileWatcher.Watch(ctx, svc.Run.Source, svc.Run.Ignore, func(changed []string) {
+				ui.Infof("%s: %d file(s) changed (%s)", svc.Name, len(changed), summarizeChanges(changed))
+				if err := h.orchestrator.RestartService(ctx, service.ServiceName(svc.Name)); err != nil && ctx.Err() == nil {
+					ui.Errorf("Failed to restart %s: %v", svc.Name, err)
+				}
+			})
+		}()
+	}
+	ui.Infof("Press Ctrl+C to stop watching")
+
+	for range watched {
+		if err := <-errs; err != nil {
+			return fmt.Errorf("failed to watch files: %w", err)
+		}
+	}
+	return nil
+}
+
+// summarizeChanges lists the first few changed paths for the reload message
+func summarizeChanges(changed []string) string {
+	const maxShown = 3
+	if len(changed) <= maxShown {
+		return strings.Join(changed, ", ")
+	}
+	return fmt.Sprintf("%s, ...", strings.Join(changed[:maxShown], ", "))
+}
+
+// checkHostPorts fails when a port the stack publishes is recorded by another environment
+// or held by a process other than this environment's own containers
+// Host-run services are left out; the developer starts those.
+func (h *UpCommandHandler) checkHostPorts(ctx context.Context, services []*service.Service, infra infrastructure.InfrastructureRequirements, local []service.ServiceName) error {
+	if h.prerequisites == nil {
+		return nil
+	}
+
+	isLocal := make(map[string]bool, len(local))
+	for _, name := range local {
+		isLocal[name.String()] = true
+	}
+	var published []ports.HostPortAssignment
+	for _, a := range h.composeGenerator.HostPorts(services, infra) {
+		if !isLocal[a.Owner] {
+			published = append(published, a)
+		}
+	}
+
+	// Ports published by this environment's running containers are expected to be taken
+	inUse := make(map[int]bool)
+	if statuses, err := h.orchestrator.GetAllServiceStatuses(ctx); err == nil {
+		for _, s := range statuses {
+			for _, p := range s.Ports {
+				inUse[p.HostPort] = true
+			}
 		}
-		ui.Successf("All services started successfully")
-	} else {
-		ui.Infof("Infrastructure only mode - skipping service startup")
 	}
 
+	var taken []string
+	for _, check := range h.prerequisites.CheckHostPorts(ctx, published, inUse) {
+		if check.Status == ports.CheckFail {
+			taken = append(taken, fmt.Sprintf("%s (%s)", check.Message, check.Hint))
+		}
+	}
+	if len(taken) > 0 {
+		return fmt.Errorf("host ports are not available:\n  %s", strings.Join(taken, "\n  "))
+	}
 	return nil
 }
 
+// splitLocalServices separates host-run services from those started in containers
+// Every --local service must be part of this run (requested or a dependency).
+func splitLocalServices(all []service.ServiceName, local []string) (localNames, containerNames []service.ServiceName, err error) {
+	isLocal := make(map[service.ServiceName]bool, len(local))
+	for _, name := range local {
+		isLocal[service.ServiceName(name)] = true
+	}
+
+	for _, name := range all {
+		if isLocal[name] {
+			localNames = append(localNames, name)
+			delete(isLocal, name)
+		} else {
+			containerNames = append(containerNames, name)
+		}
+	}
+
+	for name := range isLocal {
+		return nil, nil, fmt.Errorf("--local %s: service is not being started", name)
+	}
+	return localNames, containerNames, nil
+}
+
+// reportLocalServices prints where each host-run service's env was written
+func (h *UpCommandHandler) reportLocalServices(ctx context.Context, localNames []service.ServiceName, fileSet *ports.ComposeFileSet) {
+	for _, name := range localNames {
+		// A container from an earlier run would hold the service's port
+		if status, err := h.orchestrator.GetServiceStatus(ctx, name); err == nil && status.Status == "running" {
+			ui.Warnf("%s is still running in a container; stop it before starting it on the host", name)
+		}
+
+		envPath := fileSet.LocalEnvPaths[name.String()]
+		ui.Infof("%s runs on your host. Its environment was written to:", name)
+		ui.Infof("  %s", envPath)
+		ui.Infof("  Load it in a shell with: set -a; . %s; set +a", envPath)
+	}
+}
+
 // loadServices loads only the explicitly requested services (no dependencies)
 func (h *UpCommandHandler) loadServices(names []string) ([]*service.Service, error) {
 	var services []*service.Service
@@ -247,7 +438,7 @@ func (h *UpCommandHandler) provisionInfrastructure(ctx context.Context, req infr
 	return nil
 }
 
-func (h *UpCommandHandler) generateCompose(services []*service.Service, req infrastructure.InfrastructureRequirements, tunnelCtx map[string]ports.TunnelContext) error {
+func (h *UpCommandHandler) generateCompose(services []*service.Service, req infrastructure.InfrastructureRequirements, tunnelCtx map[string]ports.TunnelContext) (*ports.ComposeFileSet, error) {
 	var fileSet *ports.ComposeFileSet
 	var err error
 
@@ -257,16 +448,167 @@ func (h *UpCommandHandler) generateCompose(services []*service.Service, req infr
 		fileSet, err = h.composeGenerator.Generate(services, req)
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Update orchestrator with the generated compose files
 	h.orchestrator.SetComposeFiles(fileSet.AllPaths())
+	return fileSet, nil
+}
+
+// healthWaiter waits on the handler's containers
+func (h *UpCommandHandler) healthWaiter() healthWaiter {
+	return healthWaiter{orchestrator: h.orchestrator, healthChecker: h.healthChecker}
+}
+
+// planStart picks the containers to start: those not running, and those whose compose
+// definition or build context changed since they last started. Images are rebuilt when
+// their build context changed, or for every service with --build.
+func (h *UpCommandHandler) planStart(ctx context.Context, names []service.ServiceName, changes map[string]ports.ServiceChange, build bool) ([]service.ServiceName, map[service.ServiceName]bool) {
+	var started []service.ServiceName
+	rebuild := make(map[service.ServiceName]bool, len(names))
+	for _, name := range names {
+		change, known := changes[name.String()]
+		if !build && known && change.UpToDate() {
+			status, err := h.orchestrator.GetServiceStatus(ctx, name)
+			if err == nil && status.Status == "running" {
+				ui.SubStep("%s up to date", name)
+				continue
+			}
+		}
+		started = append(started, name)
+		rebuild[name] = build || !known || change.Build
+	}
+	return started, rebuild
+}
+
+// startServices starts the containers in one go, or wave by wave when any dependency
+// between them sets wait_for. Each wave waits until the services a later wave waits
+// for are ready; host-run services are never waited on.
+func (h *UpCommandHandler) startServices(ctx context.Context, services []*service.Service, names []service.ServiceName, rebuild map[service.ServiceName]bool) error {
+	inContainer := make(map[service.ServiceName]bool, len(names))
+	for _, name := range names {
+		inContainer[name] = true
+	}
+
+	graph := dependency.NewGraph()
+	byName := make(map[service.ServiceName]*service.Service)
+	required := make(map[service.ServiceName]service.WaitCondition)
+	neededBy := make(map[service.ServiceName][]string)
+	for _, svc := range services {
+		name := service.ServiceName(svc.Name)
+		if !inContainer[name] {
+			continue
+		}
+		graph.AddService(svc)
+		byName[name] = svc
+		for dep, cond := range svc.Dependencies.WaitFor {
+			if !inContainer[dep] || cond == service.WaitForNone {
+				continue
+			}
+			if cond.Stronger(required[dep]) {
+				required[dep] = cond
+			}
+			neededBy[dep] = append(neededBy[dep], svc.Name)
+		}
+	}
+
+	if len(required) == 0 {
+		return h.start(ctx, names, rebuild)
+	}
+
+	waves := graph.StartupWaves(names)
+	for i, wave := range waves {
+		ui.SubStep("Wave %d/%d: %s", i+1, len(waves), joinServiceNames(wave))
+		if err := h.start(ctx, wave, rebuild); err != nil {
+			return err
+		}
+		if i == len(waves)-1 {
+			break
+		}
+
+		for _, name := range wave {
+			cond, ok := required[name]
+			if !ok {
+				continue
+			}
+			if err := h.waitForDependency(ctx, byName[name], cond); err != nil {
+				return fmt.Errorf("%s blocked startup of %s: %w", name, strings.Join(neededBy[name], ", "), err)
+			}
+			ui.SubStep("%s is %s", name, cond)
+		}
+	}
+	return nil
+}
+
+// start brings up the services whose images are rebuilt, then the ones only recreated
+func (h *UpCommandHandler) start(ctx context.Context, names []service.ServiceName, rebuild map[service.ServiceName]bool) error {
+	var build, recreate []service.ServiceName
+	for _, name := range names {
+		if rebuild[name] {
+			build = append(build, name)
+		} else {
+			recreate = append(recreate, name)
+		}
+	}
+
+	if len(build) > 0 {
+		if err := h.orchestrator.StartServices(ctx, build, true); err != nil {
+			return err
+		}
+	}
+	if len(recreate) > 0 {
+		return h.orchestrator.StartServices(ctx, recreate, false)
+	}
 	return nil
 }
 
-func (h *UpCommandHandler) startServices(ctx context.Context, order []service.ServiceName) error {
-	return h.orchestrator.StartServices(ctx, order)
+// waitForDependency polls a container until it is running (started) or passes its
+// compose healthcheck (healthy). Containers without a healthcheck count as healthy
+// once running. The timeout covers the service's health retries, with a floor of
+// minDependencyWaitTimeout for slow boots.
+func (h *UpCommandHandler) waitForDependency(ctx context.Context, svc *service.Service, cond service.WaitCondition) error {
+	name := service.ServiceName(svc.Name)
+	timeout := time.Duration(svc.Health.Retries) * (svc.Health.Interval + svc.Health.Timeout)
+	if timeout < minDependencyWaitTimeout {
+		timeout = minDependencyWaitTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		status, err := h.orchestrator.GetServiceStatus(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case status.Status == "exited" || status.Status == "dead":
+			return fmt.Errorf("container %s before becoming %s", status.Status, cond)
+		case status.Status == "running" && cond == service.WaitForStarted:
+			return nil
+		case status.Status == "running" && (status.Health == "healthy" || status.Health == "-"):
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("not %s after %s (status: %s, health: %s)", cond, timeout, status.Status, status.Health)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(h.statusPollInterval):
+		}
+	}
+}
+
+// joinServiceNames formats service names for progress output
+func joinServiceNames(names []service.ServiceName) string {
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name.String()
+	}
+	return strings.Join(parts, ", ")
 }
 
 // startTunnels starts tunnels based on the tunnel requirement and returns tunnel context
@@ -3,6 +3,10 @@ package commands
 import (
 	"context"
 	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -58,16 +62,23 @@ func (m *mockRegistryRepository) GetAllServices() (map[service.ServiceName]ports
 }
 
 type mockOrchestrator struct {
-	startErr   error
-	startCalls [][]service.ServiceName
+	startErr     error
+	startCalls   [][]service.ServiceName
+	buildCalls   []bool // build flag of each StartServices call
+	restartCalls []service.ServiceName
+	recreated    map[service.ServiceName]bool                // service -> rebuilt
+	statuses     map[service.ServiceName]ports.ServiceStatus // defaults to running and healthy
+	logs         []ports.LogEntry
+	logCalls     []service.ServiceName
 }
 
 func (m *mockOrchestrator) StartInfrastructure(ctx context.Context) error {
 	return nil
 }
 
-func (m *mockOrchestrator) StartServices(ctx context.Context, services []service.ServiceName) error {
+func (m *mockOrchestrator) StartServices(ctx context.Context, services []service.ServiceName, build bool) error {
 	m.startCalls = append(m.startCalls, services)
+	m.buildCalls = append(m.buildCalls, build)
 	return m.startErr
 }
 
@@ -75,16 +86,52 @@ func (m *mockOrchestrator) StopServices(ctx context.Context) error {
 	return nil
 }
 
+func (m *mockOrchestrator) RemoveServices(ctx context.Context, names []service.ServiceName) error {
+	return nil
+}
+
 func (m *mockOrchestrator) RestartService(ctx context.Context, name service.ServiceName) error {
+	m.restartCalls = append(m.restartCalls, name)
+	return nil
+}
+
+func (m *mockOrchestrator) RecreateServices(ctx context.Context, names []service.ServiceName, build bool) error {
+	if m.recreated == nil {
+		m.recreated = make(map[service.ServiceName]bool)
+	}
+	for _, name := range names {
+		m.recreated[name] = build
+	}
 	return nil
 }
 
 func (m *mockOrchestrator) GetServiceStatus(ctx context.Context, name service.ServiceName) (ports.ServiceStatus, error) {
-	return ports.ServiceStatus{Name: name.String(), Status: "running", Health: "healthy"}, nil
+	if status, ok := m.statuses[name]; ok {
+		return status, nil
+	}
+	return ports.ServiceStatus{Name: name.String(), Status: "running", Health: "healthy", Endpoint: "http://" + name.String() + ".test"}, nil
 }
 
-func (m *mockOrchestrator) GetLogs(ctx context.Context, name service.ServiceName, follow bool, tail int) (ports.LogStream, error) {
-	return nil, nil
+func (m *mockOrchestrator) GetLogs(ctx context.Context, names []service.ServiceName, opts ports.LogOptions) (ports.LogStream, error) {
+	m.logCalls = append(m.logCalls, names...)
+	return &mockLogStream{entries: m.logs}, nil
+}
+
+type mockLogStream struct {
+	entries []ports.LogEntry
+}
+
+func (m *mockLogStream) Next() (ports.LogEntry, error) {
+	if len(m.entries) == 0 {
+		return ports.LogEntry{}, io.EOF
+	}
+	entry := m.entries[0]
+	m.entries = m.entries[1:]
+	return entry, nil
+}
+
+func (m *mockLogStream) Close() error {
+	return nil
 }
 
 func (m *mockOrchestrator) GetAllServiceStatuses(ctx context.Context) ([]ports.ServiceStatus, error) {
@@ -124,7 +171,15 @@ func (m *mockProvisioner) ProvisionLocalStack(ctx context.Context, req infrastru
 }
 
 type mockComposeGenerator struct {
-	generateErr error
+	generateErr   error
+	localServices []service.ServiceName
+	changes       map[string]ports.ServiceChange
+	recorded      []service.ServiceName
+	envs          map[service.ServiceName]map[string]string // current compose file environments
+	newEnvs       map[service.ServiceName]map[string]string // environments GenerateServices writes
+	regenerated   []service.ServiceName
+	localEnvPaths map[string]string
+	hostPorts     []ports.HostPortAssignment
 }
 
 func (m *mockComposeGenerator) Generate(services []*service.Service, infra infrastructure.InfrastructureRequirements) (*ports.ComposeFileSet, error) {
@@ -134,6 +189,8 @@ func (m *mockComposeGenerator) Generate(services []*service.Service, infra infra
 	return &ports.ComposeFileSet{
 		InfrastructurePath: "/tmp/infrastructure/docker-compose.yaml",
 		ServicePaths:       map[string]string{},
+		LocalEnvPaths:      m.localEnvPaths,
+		Changes:            m.changes,
 	}, nil
 }
 
@@ -141,13 +198,98 @@ func (m *mockComposeGenerator) GenerateWithTunnels(services []*service.Service,
 	return m.Generate(services, infra)
 }
 
-type mockHealthChecker struct{}
+func (m *mockComposeGenerator) GenerateServices(services []*service.Service, infra infrastructure.InfrastructureRequirements, names []service.ServiceName) (*ports.ComposeFileSet, error) {
+	if m.generateErr != nil {
+		return nil, m.generateErr
+	}
+	m.regenerated = append(m.regenerated, names...)
+	for name, env := range m.newEnvs {
+		if m.envs == nil {
+			m.envs = make(map[service.ServiceName]map[string]string)
+		}
+		m.envs[name] = env
+	}
+	return m.Generate(services, infra)
+}
+
+func (m *mockComposeGenerator) GeneratedEnv(name service.ServiceName) (map[string]string, error) {
+	return m.envs[name], nil
+}
+
+func (m *mockComposeGenerator) SetLocalServices(names []service.ServiceName) {
+	m.localServices = names
+}
+
+func (m *mockComposeGenerator) ResolveEnv(services []*service.Service, infra infrastructure.InfrastructureRequirements, name service.ServiceName, host bool) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+func (m *mockComposeGenerator) HostPorts(services []*service.Service, infra infrastructure.InfrastructureRequirements) []ports.HostPortAssignment {
+	return m.hostPorts
+}
+
+// mockPrerequisites fails the host ports in taken and records the ports it was asked about
+type mockPrerequisites struct {
+	taken   map[int]bool
+	checked []int
+}
+
+func (m *mockPrerequisites) CheckRuntime(ctx context.Context) []ports.Check {
+	return nil
+}
+
+func (m *mockPrerequisites) CheckHostPorts(ctx context.Context, assignments []ports.HostPortAssignment, inUse map[int]bool) []ports.Check {
+	var checks []ports.Check
+	for _, a := range assignments {
+		m.checked = append(m.checked, a.HostPort)
+		if m.taken[a.HostPort] {
+			checks = append(checks, ports.Check{Status: ports.CheckFail, Message: fmt.Sprintf("host port %d for %s is in use", a.HostPort, a.Owner)})
+		}
+	}
+	return checks
+}
 
-func (m *mockHealthChecker) CheckHealth(ctx context.Context, endpoint string, timeout int) error {
+func (m *mockPrerequisites) CheckDiskSpace(ctx context.Context) []ports.Check {
 	return nil
 }
 
-func (m *mockHealthChecker) WaitForHealthy(ctx context.Context, endpoint string, interval, timeout int, retries int) error {
+func (m *mockPrerequisites) CheckTunnelProvider(provider string) ports.Check {
+	return ports.Check{}
+}
+
+func (m *mockComposeGenerator) RecordStarted(names []service.ServiceName) error {
+	m.recorded = append(m.recorded, names...)
+	return nil
+}
+
+type mockHealthChecker struct {
+	mu        sync.Mutex
+	unhealthy map[string]bool // endpoints that never become healthy
+	checked   []string
+}
+
+func (m *mockHealthChecker) CheckHealth(ctx context.Context, endpoint string, timeout time.Duration) error {
+	return nil
+}
+
+func (m *mockHealthChecker) WaitForHealthy(ctx context.Context, endpoint string, interval, timeout time.Duration, retries int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checked = append(m.checked, endpoint)
+	if m.unhealthy[endpoint] {
+		return fmt.Errorf("not healthy after %d checks", retries)
+	}
+	return nil
+}
+
+// mockFileWatcher reports one change per watched directory and returns
+type mockFileWatcher struct {
+	watchedDirs []string
+}
+
+func (m *mockFileWatcher) Watch(ctx context.Context, dir string, ignore []string, onChange func(changed []string)) error {
+	m.watchedDirs = append(m.watchedDirs, dir)
+	onChange([]string{"main.go"})
 	return nil
 }
 
@@ -197,7 +339,7 @@ func TestUpCommandHandler_Handle_Success(t *testing.T) {
 	composeGen := &mockComposeGenerator{}
 	healthChecker := &mockHealthChecker{}
 
-	handler := NewUpCommandHandler(repo, registry, orchestrator, provisioner, composeGen, healthChecker, nil)
+	handler := NewUpCommandHandler(repo, registry, orchestrator, provisioner, composeGen, healthChecker, nil, nil, nil)
 
 	cmd := UpCommand{
 		ServiceNames: []string{"service-a", "service-b"},
@@ -237,6 +379,88 @@ func TestUpCommandHandler_Handle_Success(t *testing.T) {
 	}
 }
 
+func TestUpCommandHandler_Handle_LocalService(t *testing.T) {
+	svcB := createTestService("service-b", []string{})
+	svcA := createTestService("service-a", []string{"service-b"})
+
+	repo := &mockServiceRepository{
+		services: map[service.ServiceName]*service.Service{
+			"service-a": svcA,
+			"service-b": svcB,
+		},
+	}
+	orchestrator := &mockOrchestrator{}
+	composeGen := &mockComposeGenerator{}
+
+	handler := NewUpCommandHandler(repo, &mockRegistryRepository{}, orchestrator, &mockProvisioner{}, composeGen, &mockHealthChecker{}, nil, nil, nil)
+
+	cmd := UpCommand{
+		ServiceNames: []string{"service-a"},
+		Local:        []string{"service-a"},
+	}
+
+	if err := handler.Handle(context.Background(), cmd); err != nil {
+		t.Fatalf("Handle() returned error: %v", err)
+	}
+
+	if len(composeGen.localServices) != 1 || composeGen.localServices[0] != "service-a" {
+		t.Errorf("Expected service-a marked local, got %v", composeGen.localServices)
+	}
+	if len(orchestrator.startCalls) != 1 {
+		t.Fatalf("Expected 1 StartServices call, got %d", len(orchestrator.startCalls))
+	}
+	started := orchestrator.startCalls[0]
+	if len(started) != 1 || started[0] != "service-b" {
+		t.Errorf("Expected only service-b to start in a container, got %v", started)
+	}
+}
+
+func TestUpCommandHandler_Handle_HostPortTaken(t *testing.T) {
+	repo := &mockServiceRepository{
+		services: map[service.ServiceName]*service.Service{
+			"service-a": createTestService("service-a", []string{"service-b"}),
+			"service-b": createTestService("service-b", []string{}),
+		},
+	}
+	orchestrator := &mockOrchestrator{}
+	composeGen := &mockComposeGenerator{hostPorts: []ports.HostPortAssignment{
+		{Owner: "postgres", HostPort: 5432},
+		{Owner: "service-a", HostPort: 8080},
+		{Owner: "service-b", HostPort: 9080},
+	}}
+	prerequisites := &mockPrerequisites{taken: map[int]bool{9080: true}}
+
+	handler := NewUpCommandHandler(repo, &mockRegistryRepository{}, orchestrator, &mockProvisioner{}, composeGen, &mockHealthChecker{}, nil, nil, prerequisites)
+	err := handler.Handle(context.Background(), UpCommand{ServiceNames: []string{"service-a"}, Local: []string{"service-a"}})
+	if err == nil || !strings.Contains(err.Error(), "host port 9080 for service-b") {
+		t.Fatalf("Handle() error = %v, want service-b's port reported", err)
+	}
+	if fmt.Sprint(prerequisites.checked) != "[5432 9080]" {
+		t.Errorf("checked ports = %v, want all but the host-run service-a's", prerequisites.checked)
+	}
+	if len(orchestrator.startCalls) != 0 {
+		t.Errorf("nothing should start when a port is taken, started %v", orchestrator.startCalls)
+	}
+}
+
+func TestUpCommandHandler_Handle_LocalServiceNotStarted(t *testing.T) {
+	repo := &mockServiceRepository{
+		services: map[service.ServiceName]*service.Service{
+			"service-a": createTestService("service-a", []string{}),
+		},
+	}
+	handler := NewUpCommandHandler(repo, &mockRegistryRepository{}, &mockOrchestrator{}, &mockProvisioner{}, &mockComposeGenerator{}, &mockHealthChecker{}, nil, nil, nil)
+
+	cmd := UpCommand{
+		ServiceNames: []string{"service-a"},
+		Local:        []string{"service-z"},
+	}
+
+	if err := handler.Handle(context.Background(), cmd); err == nil {
+		t.Fatal("Expected error for --local service that is not being started")
+	}
+}
+
 func TestUpCommandHandler_Handle_ServiceNotFound(t *testing.T) {
 	repo := &mockServiceRepository{
 		services: map[service.ServiceName]*service.Service{},
@@ -247,7 +471,7 @@ func TestUpCommandHandler_Handle_ServiceNotFound(t *testing.T) {
 	composeGen := &mockComposeGenerator{}
 	healthChecker := &mockHealthChecker{}
 
-	handler := NewUpCommandHandler(repo, registry, orchestrator, provisioner, composeGen, healthChecker, nil)
+	handler := NewUpCommandHandler(repo, registry, orchestrator, provisioner, composeGen, healthChecker, nil, nil, nil)
 
 	cmd := UpCommand{
 		ServiceNames: []string{"nonexistent-service"},
@@ -280,7 +504,7 @@ func TestUpCommandHandler_Handle_CircularDependency(t *testing.T) {
 	composeGen := &mockComposeGenerator{}
 	healthChecker := &mockHealthChecker{}
 
-	handler := NewUpCommandHandler(repo, registry, orchestrator, provisioner, composeGen, healthChecker, nil)
+	handler := NewUpCommandHandler(repo, registry, orchestrator, provisioner, composeGen, healthChecker, nil, nil, nil)
 
 	cmd := UpCommand{
 		ServiceNames: []string{"service-a", "service-b", "service-c"},
@@ -323,7 +547,7 @@ func TestUpCommandHandler_Handle_TransitiveDependencies(t *testing.T) {
 	composeGen := &mockComposeGenerator{}
 	healthChecker := &mockHealthChecker{}
 
-	handler := NewUpCommandHandler(repo, registry, orchestrator, provisioner, composeGen, healthChecker, nil)
+	handler := NewUpCommandHandler(repo, registry, orchestrator, provisioner, composeGen, healthChecker, nil, nil, nil)
 
 	// Only request service-a, but B and C should be loaded as dependencies
 	cmd := UpCommand{
@@ -363,7 +587,7 @@ func TestUpCommandHandler_Handle_InfraOnly(t *testing.T) {
 	composeGen := &mockComposeGenerator{}
 	healthChecker := &mockHealthChecker{}
 
-	handler := NewUpCommandHandler(repo, registry, orchestrator, provisioner, composeGen, healthChecker, nil)
+	handler := NewUpCommandHandler(repo, registry, orchestrator, provisioner, composeGen, healthChecker, nil, nil, nil)
 
 	cmd := UpCommand{
 		ServiceNames: []string{"service-a"},
@@ -402,7 +626,7 @@ func TestUpCommandHandler_Handle_NoDeps(t *testing.T) {
 	composeGen := &mockComposeGenerator{}
 	healthChecker := &mockHealthChecker{}
 
-	handler := NewUpCommandHandler(repo, registry, orchestrator, provisioner, composeGen, healthChecker, nil)
+	handler := NewUpCommandHandler(repo, registry, orchestrator, provisioner, composeGen, healthChecker, nil, nil, nil)
 
 	// Only start service-a, even though it depends on service-b
 	cmd := UpCommand{
@@ -446,7 +670,7 @@ func TestUpCommandHandler_Handle_ProvisioningFails(t *testing.T) {
 	composeGen := &mockComposeGenerator{}
 	healthChecker := &mockHealthChecker{}
 
-	handler := NewUpCommandHandler(repo, registry, orchestrator, provisioner, composeGen, healthChecker, nil)
+	handler := NewUpCommandHandler(repo, registry, orchestrator, provisioner, composeGen, healthChecker, nil, nil, nil)
 
 	cmd := UpCommand{
 		ServiceNames: []string{"service-a"},
@@ -483,7 +707,7 @@ func TestUpCommandHandler_Handle_WithLocalStack(t *testing.T) {
 	composeGen := &mockComposeGenerator{}
 	healthChecker := &mockHealthChecker{}
 
-	handler := NewUpCommandHandler(repo, registry, orchestrator, provisioner, composeGen, healthChecker, nil)
+	handler := NewUpCommandHandler(repo, registry, orchestrator, provisioner, composeGen, healthChecker, nil, nil, nil)
 
 	cmd := UpCommand{
 		ServiceNames: []string{"service-a"},
@@ -508,3 +732,230 @@ func TestUpCommandHandler_Handle_WithLocalStack(t *testing.T) {
 		t.Error("Expected SNS topic in LocalStack requirements")
 	}
 }
+
+func TestUpCommandHandler_Handle_WatchRestartsHotReloadServices(t *testing.T) {
+	svcB := createTestService("service-b", []string{})
+	svcA := createTestService("service-a", []string{"service-b"})
+	svcA.Run = &service.RunConfig{Command: "go run .", HotReload: true, Source: "/src/service-a"}
+
+	repo := &mockServiceRepository{
+		services: map[service.ServiceName]*service.Service{
+			"service-a": svcA,
+			"service-b": svcB,
+		},
+	}
+	orchestrator := &mockOrchestrator{}
+	fileWatcher := &mockFileWatcher{}
+
+	handler := NewUpCommandHandler(repo, &mockRegistryRepository{}, orchestrator, &mockProvisioner{}, &mockComposeGenerator{}, &mockHealthChecker{}, nil, fileWatcher, nil)
+
+	cmd := UpCommand{
+		ServiceNames: []string{"service-a"},
+		Watch:        true,
+	}
+
+	if err := handler.Handle(context.Background(), cmd); err != nil {
+		t.Fatalf("Handle() returned error: %v", err)
+	}
+
+	if len(fileWatcher.watchedDirs) != 1 || fileWatcher.watchedDirs[0] != "/src/service-a" {
+		t.Errorf("Expected only /src/service-a to be watched, got %v", fileWatcher.watchedDirs)
+	}
+	if len(orchestrator.restartCalls) != 1 || orchestrator.restartCalls[0] != "service-a" {
+		t.Errorf("Expected service-a to be restarted, got %v", orchestrator.restartCalls)
+	}
+}
+
+func TestUpCommandHandler_Handle_WaitForStartsInWaves(t *testing.T) {
+	// api waits for auth to be healthy; worker depends on api without waiting
+	auth := createTestService("auth", []string{})
+	api := createTestService("api", []string{"auth"})
+	api.Dependencies.WaitFor = map[service.ServiceName]service.WaitCondition{"auth": service.WaitForHealthy}
+	worker := createTestService("worker", []string{"api"})
+
+	repo := &mockServiceRepository{
+		services: map[service.ServiceName]*service.Service{"auth": auth, "api": api, "worker": worker},
+	}
+	orchestrator := &mockOrchestrator{}
+
+	handler := NewUpCommandHandler(repo, &mockRegistryRepository{}, orchestrator, &mockProvisioner{}, &mockComposeGenerator{}, &mockHealthChecker{}, nil, nil, nil)
+
+	if err := handler.Handle(context.Background(), UpCommand{ServiceNames: []string{"worker"}}); err != nil {
+		t.Fatalf("Handle() returned error: %v", err)
+	}
+
+	if len(orchestrator.startCalls) != 2 {
+		t.Fatalf("Expected 2 startup waves, got %v", orchestrator.startCalls)
+	}
+	if got := joinServiceNames(orchestrator.startCalls[0]); got != "auth, worker" {
+		t.Errorf("first wave = %q, want \"auth, worker\"", got)
+	}
+	if got := joinServiceNames(orchestrator.startCalls[1]); got != "api" {
+		t.Errorf("second wave = %q, want \"api\"", got)
+	}
+}
+
+func TestUpCommandHandler_Handle_WaitForReportsBlockingService(t *testing.T) {
+	auth := createTestService("auth", []string{})
+	api := createTestService("api", []string{"auth"})
+	api.Dependencies.WaitFor = map[service.ServiceName]service.WaitCondition{"auth": service.WaitForHealthy}
+
+	repo := &mockServiceRepository{
+		services: map[service.ServiceName]*service.Service{"auth": auth, "api": api},
+	}
+	orchestrator := &mockOrchestrator{
+		statuses: map[service.ServiceName]ports.ServiceStatus{
+			"auth": {Name: "auth", Status: "exited", Health: "unhealthy"},
+		},
+	}
+
+	handler := NewUpCommandHandler(repo, &mockRegistryRepository{}, orchestrator, &mockProvisioner{}, &mockComposeGenerator{}, &mockHealthChecker{}, nil, nil, nil)
+	handler.statusPollInterval = time.Millisecond
+
+	err := handler.Handle(context.Background(), UpCommand{ServiceNames: []string{"api"}})
+	if err == nil {
+		t.Fatal("Expected error when a wait_for dependency exits")
+	}
+	if !strings.Contains(err.Error(), "auth blocked startup of api") {
+		t.Errorf("error = %q, want it to name the blocking service", err)
+	}
+	if len(orchestrator.startCalls) != 1 {
+		t.Errorf("Expected only the first wave to start, got %v", orchestrator.startCalls)
+	}
+}
+
+func TestUpCommandHandler_Handle_WaitsForHealth(t *testing.T) {
+	svcB := createTestService("service-b", []string{})
+	svcA := createTestService("service-a", []string{"service-b"})
+
+	repo := &mockServiceRepository{
+		services: map[service.ServiceName]*service.Service{"service-a": svcA, "service-b": svcB},
+	}
+	healthChecker := &mockHealthChecker{}
+
+	handler := NewUpCommandHandler(repo, &mockRegistryRepository{}, &mockOrchestrator{}, &mockProvisioner{}, &mockComposeGenerator{}, healthChecker, nil, nil, nil)
+
+	if err := handler.Handle(context.Background(), UpCommand{ServiceNames: []string{"service-a"}}); err != nil {
+		t.Fatalf("Handle() returned error: %v", err)
+	}
+
+	sort.Strings(healthChecker.checked)
+	want := []string{"http://service-a.test/health", "http://service-b.test/health"}
+	if strings.Join(healthChecker.checked, ",") != strings.Join(want, ",") {
+		t.Errorf("checked endpoints = %v, want %v", healthChecker.checked, want)
+	}
+}
+
+func TestUpCommandHandler_Handle_UnhealthyServiceShowsLogs(t *testing.T) {
+	svcB := createTestService("service-b", []string{})
+	svcA := createTestService("service-a", []string{"service-b"})
+
+	repo := &mockServiceRepository{
+		services: map[service.ServiceName]*service.Service{"service-a": svcA, "service-b": svcB},
+	}
+	orchestrator := &mockOrchestrator{
+		logs: []ports.LogEntry{{Service: "service-b", Message: "panic: missing DATABASE_URL"}},
+	}
+	healthChecker := &mockHealthChecker{unhealthy: map[string]bool{"http://service-b.test/health": true}}
+
+	handler := NewUpCommandHandler(repo, &mockRegistryRepository{}, orchestrator, &mockProvisioner{}, &mockComposeGenerator{}, healthChecker, nil, nil, nil)
+
+	err := handler.Handle(context.Background(), UpCommand{ServiceNames: []string{"service-a"}})
+	if err == nil {
+		t.Fatal("Expected error when a service never becomes healthy")
+	}
+	if !strings.Contains(err.Error(), "service-b") || strings.Contains(err.Error(), "service-a") {
+		t.Errorf("error = %q, want only service-b reported", err)
+	}
+	if len(orchestrator.logCalls) != 1 || orchestrator.logCalls[0] != "service-b" {
+		t.Errorf("Expected logs of service-b to be shown, got %v", orchestrator.logCalls)
+	}
+}
+
+func TestUpCommandHandler_Handle_NoWait(t *testing.T) {
+	svc := createTestService("service-a", []string{})
+	repo := &mockServiceRepository{services: map[service.ServiceName]*service.Service{"service-a": svc}}
+	healthChecker := &mockHealthChecker{unhealthy: map[string]bool{"http://service-a.test/health": true}}
+
+	handler := NewUpCommandHandler(repo, &mockRegistryRepository{}, &mockOrchestrator{}, &mockProvisioner{}, &mockComposeGenerator{}, healthChecker, nil, nil, nil)
+
+	if err := handler.Handle(context.Background(), UpCommand{ServiceNames: []string{"service-a"}, NoWait: true}); err != nil {
+		t.Fatalf("Handle() returned error: %v", err)
+	}
+	if len(healthChecker.checked) != 0 {
+		t.Errorf("Expected no health checks with NoWait, got %v", healthChecker.checked)
+	}
+}
+
+func TestUpCommandHandler_Handle_SkipsUnchangedServices(t *testing.T) {
+	tests := []struct {
+		name       string
+		changes    map[string]ports.ServiceChange
+		statuses   map[service.ServiceName]ports.ServiceStatus
+		build      bool
+		wantStarts [][]service.ServiceName
+		wantBuilds []bool
+	}{
+		{
+			name:    "nothing changed",
+			changes: map[string]ports.ServiceChange{"service-a": {}, "service-b": {}},
+		},
+		{
+			name:       "config changed",
+			changes:    map[string]ports.ServiceChange{"service-a": {Config: true}, "service-b": {}},
+			wantStarts: [][]service.ServiceName{{"service-a"}},
+			wantBuilds: []bool{false},
+		},
+		{
+			name:       "build context changed",
+			changes:    map[string]ports.ServiceChange{"service-a": {}, "service-b": {Build: true}},
+			wantStarts: [][]service.ServiceName{{"service-b"}},
+			wantBuilds: []bool{true},
+		},
+		{
+			name:       "unchanged but stopped",
+			changes:    map[string]ports.ServiceChange{"service-a": {}, "service-b": {}},
+			statuses:   map[service.ServiceName]ports.ServiceStatus{"service-b": {Name: "service-b", Status: "created", Endpoint: "http://service-b.test"}},
+			wantStarts: [][]service.ServiceName{{"service-b"}},
+			wantBuilds: []bool{false},
+		},
+		{
+			name:       "forced rebuild",
+			changes:    map[string]ports.ServiceChange{"service-a": {}, "service-b": {Config: true}},
+			build:      true,
+			wantStarts: [][]service.ServiceName{{"service-a", "service-b"}},
+			wantBuilds: []bool{true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockServiceRepository{services: map[service.ServiceName]*service.Service{
+				"service-a": createTestService("service-a", nil),
+				"service-b": createTestService("service-b", nil),
+			}}
+			orchestrator := &mockOrchestrator{statuses: tt.statuses}
+			generator := &mockComposeGenerator{changes: tt.changes}
+			healthChecker := &mockHealthChecker{}
+
+			handler := NewUpCommandHandler(repo, &mockRegistryRepository{}, orchestrator, &mockProvisioner{}, generator, healthChecker, nil, nil, nil)
+			if err := handler.Handle(context.Background(), UpCommand{ServiceNames: []string{"service-a", "service-b"}, Build: tt.build}); err != nil {
+				t.Fatalf("Handle() returned error: %v", err)
+			}
+
+			if fmt.Sprint(orchestrator.startCalls) != fmt.Sprint(tt.wantStarts) || fmt.Sprint(orchestrator.buildCalls) != fmt.Sprint(tt.wantBuilds) {
+				t.Errorf("StartServices calls = %v (build %v), want %v (build %v)", orchestrator.startCalls, orchestrator.buildCalls, tt.wantStarts, tt.wantBuilds)
+			}
+			var wantRecorded []service.ServiceName
+			for _, call := range tt.wantStarts {
+				wantRecorded = append(wantRecorded, call...)
+			}
+			if fmt.Sprint(generator.recorded) != fmt.Sprint(wantRecorded) {
+				t.Errorf("recorded = %v, want %v", generator.recorded, wantRecorded)
+			}
+			if len(healthChecker.checked) != len(wantRecorded) {
+				t.Errorf("checked endpoints = %v, want only started services", healthChecker.checked)
+			}
+		})
+	}
+}
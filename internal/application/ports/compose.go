@@ -1,14 +1,31 @@
 package ports
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
 	"github.com/vivekkundariya/grund/internal/domain/service"
 )
 
 // ComposeFileSet represents the collection of generated compose files
 type ComposeFileSet struct {
-	InfrastructurePath string            // ~/.grund/tmp/infrastructure/docker-compose.yaml
-	ServicePaths       map[string]string // service name -> full path
+	InfrastructurePath string                   // ~/.grund/tmp/infrastructure/docker-compose.yaml
+	ServicePaths       map[string]string        // service name -> full path
+	LocalEnvPaths      map[string]string        // host-run service name -> resolved env file
+	Changes            map[string]ServiceChange // generated service name -> what changed since it last started
+}
+
+// ServiceChange says what differs from the compose definition and build context a service last started from
+type ServiceChange struct {
+	Config bool // the resolved compose definition changed, or the service never started
+	Build  bool // the build context or Dockerfile changed, or the image was never built by grund
+}
+
+// UpToDate reports whether nothing changed
+func (c ServiceChange) UpToDate() bool {
+	return !c.Config && !c.Build
 }
 
 // AllPaths returns all compose file paths in the set
@@ -28,6 +45,31 @@ type ComposeGenerator interface {
 	Generate(services []*service.Service, infra infrastructure.InfrastructureRequirements) (*ComposeFileSet, error)
 	// GenerateWithTunnels generates compose with tunnel context for env_refs resolution
 	GenerateWithTunnels(services []*service.Service, infra infrastructure.InfrastructureRequirements, tunnelCtx map[string]TunnelContext) (*ComposeFileSet, error)
+	// GenerateServices regenerates the compose files of the named services only, resolving their env against all of services
+	// Infrastructure and the other services' compose files are left as they are, and the host ports and
+	// host-run services recorded by the last Generate are kept.
+	GenerateServices(services []*service.Service, infra infrastructure.InfrastructureRequirements, names []service.ServiceName) (*ComposeFileSet, error)
+	// GeneratedEnv returns the environment in a service's current compose file; nil when it has none
+	GeneratedEnv(name service.ServiceName) (map[string]string, error)
+	// SetLocalServices marks services that run on the host instead of in a container
+	// Their env is resolved against localhost and written to an env file; peers reach them via host.docker.internal
+	SetLocalServices(names []service.ServiceName)
+	// ResolveEnv resolves a service's environment as Generate would, without writing files
+	// With host set, infrastructure and peers are reached through localhost and their published ports.
+	ResolveEnv(services []*service.Service, infra infrastructure.InfrastructureRequirements, name service.ServiceName, host bool) (map[string]string, error)
+	// HostPorts returns the host ports Generate would publish, infrastructure first
+	HostPorts(services []*service.Service, infra infrastructure.InfrastructureRequirements) []HostPortAssignment
+	// RecordStarted records that services now run from the compose files and build contexts last generated
+	// Until then, Changes keeps comparing against what they were started from before.
+	RecordStarted(names []service.ServiceName) error
+}
+
+// HostPortAssignment is a port published on the host for a service or infrastructure container
+type HostPortAssignment struct {
+	Owner         string // service or infrastructure container
+	HostPort      int
+	ContainerPort int
+	ConflictsWith string // who already had the requested port; empty when there was no conflict
 }
 
 // EnvironmentResolver defines the interface for environment variable resolution
@@ -56,6 +98,12 @@ type EnvironmentContext struct {
 
 	// Tunnel contexts (cloudflare tunnels)
 	Tunnel map[string]TunnelContext
+
+	// Env holds the service's static env vars for ${env.NAME}; the host environment is the fallback
+	Env map[string]string
+
+	// Secrets holds the service's resolved secrets for ${secret.NAME}
+	Secrets map[string]string
 }
 
 // InfrastructureContext provides infrastructure connection details
@@ -125,6 +173,152 @@ func NewDefaultEnvironmentContext() EnvironmentContext {
 			SecretAccessKey: "test",
 			AccountID:       "000000000000",
 		},
-		Tunnel: make(map[string]TunnelContext),
+		Tunnel:  make(map[string]TunnelContext),
+		Env:     make(map[string]string),
+		Secrets: make(map[string]string),
+	}
+}
+
+// NewPreviewEnvironmentContext creates a context for resolving a service's env_refs without starting anything
+// Infrastructure and peers are addressed by container name, and secrets stay as ${secret.NAME}
+// so previews never contain their values. depPorts holds the port of each dependency that is known.
+func NewPreviewEnvironmentContext(svc *service.Service, depPorts map[string]int) EnvironmentContext {
+	ctx := NewDefaultEnvironmentContext()
+
+	// Static env vars back ${env.NAME}; secrets stay as ${secret.NAME} so previews never contain their values
+	ctx.Env = svc.Environment.Variables
+	for name := range svc.Environment.Secrets {
+		ctx.Secrets[name] = "${secret." + name + "}"
+	}
+
+	// Set self context
+	ctx.Self = ServiceContext{
+		Host:   svc.Name,
+		Port:   svc.Port.Value(),
+		Config: make(map[string]interface{}),
+	}
+
+	// Add postgres if required
+	if svc.Dependencies.Infrastructure.Postgres != nil {
+		ctx.Infrastructure["postgres"] = InfrastructureContext{
+			Host:     "postgres",
+			Port:     5432,
+			Database: svc.Dependencies.Infrastructure.Postgres.Database,
+			Username: "postgres",
+			Password: "postgres",
+		}
+		username, password := svc.Dependencies.Infrastructure.Postgres.AsDatabase().Credentials()
+		ctx.Self.Config["postgres.database"] = svc.Dependencies.Infrastructure.Postgres.Database
+		ctx.Self.Config["postgres.username"] = username
+		ctx.Self.Config["postgres.password"] = password
+	}
+
+	// Add mongodb if required
+	if svc.Dependencies.Infrastructure.MongoDB != nil {
+		ctx.Infrastructure["mongodb"] = InfrastructureContext{
+			Host:     "mongodb",
+			Port:     27017,
+			Database: svc.Dependencies.Infrastructure.MongoDB.Database,
+		}
+		ctx.Self.Config["mongodb.database"] = svc.Dependencies.Infrastructure.MongoDB.Database
+	}
+
+	// Add redis if required
+	if svc.Dependencies.Infrastructure.Redis != nil {
+		ctx.Infrastructure["redis"] = InfrastructureContext{
+			Host: "redis",
+			Port: 6379,
+		}
+	}
+
+	// Add SQS queues if required
+	if svc.Dependencies.Infrastructure.SQS != nil {
+		for _, queue := range svc.Dependencies.Infrastructure.SQS.Queues {
+			ctx.SQS[queue.Name] = QueueContext{
+				Name: queue.Name,
+				URL:  ctx.LocalStack.Endpoint + "/000000000000/" + queue.Name,
+				ARN:  "arn:aws:sqs:" + ctx.LocalStack.Region + ":000000000000:" + queue.Name,
+				DLQ:  ctx.LocalStack.Endpoint + "/000000000000/" + queue.Name + "-dlq",
+			}
+		}
+	}
+
+	// Add SNS topics if required
+	if svc.Dependencies.Infrastructure.SNS != nil {
+		for _, topic := range svc.Dependencies.Infrastructure.SNS.Topics {
+			ctx.SNS[topic.Name] = TopicContext{
+				Name: topic.Name,
+				ARN:  "arn:aws:sns:" + ctx.LocalStack.Region + ":000000000000:" + topic.Name,
+			}
+		}
+	}
+
+	// Add S3 buckets if required
+	if svc.Dependencies.Infrastructure.S3 != nil {
+		for _, bucket := range svc.Dependencies.Infrastructure.S3.Buckets {
+			ctx.S3[bucket.Name] = BucketContext{
+				Name: bucket.Name,
+				URL:  ctx.LocalStack.Endpoint + "/" + bucket.Name,
+			}
+		}
+	}
+
+	// Add service dependencies
+	for _, dep := range svc.Dependencies.Services {
+		if port, ok := depPorts[dep.String()]; ok {
+			ctx.Services[dep.String()] = ServiceContext{
+				Host:   dep.String(),
+				Port:   port,
+				Config: make(map[string]interface{}),
+			}
+		}
+	}
+
+	return ctx
+}
+
+// UnresolvedReference is an env_refs placeholder that couldn't be resolved
+type UnresolvedReference struct {
+	Key         string // env_refs key
+	Placeholder string // e.g. ${redis.host}
+	Reason      string
+	Line        int // line of the key in the service's grund.yaml, 0 if unknown
+}
+
+// UnresolvedReferencesError reports every unresolved env_refs placeholder at once
+type UnresolvedReferencesError struct {
+	Source     string // grund.yaml the references come from, if known
+	References []UnresolvedReference
+}
+
+// Locate records where the references are defined, given the line of each env_refs key
+func (e *UnresolvedReferencesError) Locate(source string, lines map[string]int) {
+	e.Source = source
+	for i := range e.References {
+		e.References[i].Line = lines[e.References[i].Key]
+	}
+	sort.SliceStable(e.References, func(i, j int) bool {
+		return e.References[i].Line < e.References[j].Line
+	})
+}
+
+func (e *UnresolvedReferencesError) Error() string {
+	var b strings.Builder
+	if len(e.References) == 1 {
+		b.WriteString("unresolved env_refs placeholder:")
+	} else {
+		fmt.Fprintf(&b, "%d unresolved env_refs placeholders:", len(e.References))
+	}
+	for _, ref := range e.References {
+		b.WriteString("\n  ")
+		if e.Source != "" {
+			b.WriteString(e.Source)
+			if ref.Line > 0 {
+				fmt.Fprintf(&b, ":%d", ref.Line)
+			}
+			b.WriteString(": ")
+		}
+		fmt.Fprintf(&b, "%s: %s: %s", ref.Key, ref.Placeholder, ref.Reason)
 	}
+	return b.String()
 }
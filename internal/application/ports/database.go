@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
+)
+
+// MigrationStatus describes a single migration and whether it has been applied
+type MigrationStatus struct {
+	Version   string
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// DatabaseMigrator applies schema migrations to a service database
+type DatabaseMigrator interface {
+	// Migrate applies all pending migrations and returns how many were applied
+	Migrate(ctx context.Context, db infrastructure.PostgresDatabase) (int, error)
+	// Rollback reverts the most recently applied migrations and returns how many were reverted
+	Rollback(ctx context.Context, db infrastructure.PostgresDatabase, steps int) (int, error)
+	// Status lists every migration found in the migrations directory
+	Status(ctx context.Context, db infrastructure.PostgresDatabase) ([]MigrationStatus, error)
+}
+
+// DatabaseSeeder loads seed data into a service database once per fresh volume
+// A marker stored in the database records that the seed was applied.
+type DatabaseSeeder interface {
+	// SeedPostgres applies the database's seed SQL; returns false if it was already seeded
+	SeedPostgres(ctx context.Context, db infrastructure.PostgresDatabase, force bool) (bool, error)
+	// SeedMongoDB imports the database's seed documents; returns false if it was already seeded
+	SeedMongoDB(ctx context.Context, db infrastructure.MongoDBDatabase, force bool) (bool, error)
+}
@@ -0,0 +1,49 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+// CheckStatus is the outcome of a grund doctor check
+type CheckStatus string
+
+const (
+	CheckOK   CheckStatus = "ok"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+)
+
+// Check is the result of a single grund doctor check
+type Check struct {
+	Category string      `json:"category" yaml:"category"` // services, dependencies, infrastructure, ports, secrets, runtime, host ports, disk, tunnel
+	Name     string      `json:"name" yaml:"name"`
+	Status   CheckStatus `json:"status" yaml:"status"`
+	Message  string      `json:"message" yaml:"message"`
+	Hint     string      `json:"hint,omitempty" yaml:"hint,omitempty"` // how to fix it
+}
+
+// PrerequisiteChecker checks the local tools and resources grund up needs
+type PrerequisiteChecker interface {
+	// CheckRuntime checks the container engine is reachable and its compose version is supported
+	CheckRuntime(ctx context.Context) []Check
+	// CheckHostPorts checks the ports grund publishes are free
+	// Ports in inUse are held by grund's own running containers and are fine.
+	CheckHostPorts(ctx context.Context, assignments []HostPortAssignment, inUse map[int]bool) []Check
+	// CheckDiskSpace checks there is room for images and volumes
+	CheckDiskSpace(ctx context.Context) []Check
+	// CheckTunnelProvider checks the tunnel provider's binary is installed
+	CheckTunnelProvider(provider string) Check
+}
+
+// SecretChecker reports required secrets that no backend provides
+type SecretChecker interface {
+	MissingSecrets(services []*service.Service) ([]MissingSecret, error)
+}
+
+// MissingSecret is a required secret and the services it is missing for
+type MissingSecret struct {
+	Name     string
+	Services []string
+}
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
 	"github.com/vivekkundariya/grund/internal/domain/service"
@@ -11,26 +12,62 @@ import (
 // This abstracts away Docker Compose implementation details
 type ContainerOrchestrator interface {
 	StartInfrastructure(ctx context.Context) error
-	StartServices(ctx context.Context, services []service.ServiceName) error
+	StartServices(ctx context.Context, services []service.ServiceName, build bool) error
 	StopServices(ctx context.Context) error
+	// RemoveServices stops and removes the given containers and deletes their generated compose files
+	RemoveServices(ctx context.Context, names []service.ServiceName) error
 	RestartService(ctx context.Context, name service.ServiceName) error
+	// RecreateServices replaces containers with new ones from their current compose files, leaving dependencies alone
+	// With build, images are rebuilt first.
+	RecreateServices(ctx context.Context, names []service.ServiceName, build bool) error
 	GetServiceStatus(ctx context.Context, name service.ServiceName) (ServiceStatus, error)
 	GetAllServiceStatuses(ctx context.Context) ([]ServiceStatus, error)
-	GetLogs(ctx context.Context, name service.ServiceName, follow bool, tail int) (LogStream, error)
+	// GetLogs multiplexes the logs of the given services (all services when empty)
+	GetLogs(ctx context.Context, names []service.ServiceName, opts LogOptions) (LogStream, error)
 	SetComposeFiles(files []string)
 }
 
 // ServiceStatus represents the status of a service
 type ServiceStatus struct {
-	Name     string
-	Status   string
-	Endpoint string
-	Health   string
+	Name         string
+	Container    string // container name; empty when there is no container
+	Status       string // container state (running, exited, restarting, ...) or "not running"
+	Endpoint     string
+	Health       string
+	Ports        []PortBinding
+	StartedAt    time.Time // zero when the container never started
+	RestartCount int
+	ExitCode     int // meaningful once the container has exited
 }
 
-// LogStream represents a stream of logs
+// PortBinding is a container port published on the host
+type PortBinding struct {
+	HostPort      int
+	ContainerPort int
+	Protocol      string
+}
+
+// LogOptions selects and filters log lines
+type LogOptions struct {
+	Follow bool
+	Tail   int    // lines per service from the end; 0 means all
+	Since  string // duration ("10m") or timestamp, as accepted by docker
+	Grep   string // regular expression the line must match
+	Level  string // minimum level (debug, info, warn, error); lines without a level always pass
+}
+
+// LogEntry is a single log line from a service
+type LogEntry struct {
+	Service   string    `json:"service"`
+	Timestamp time.Time `json:"timestamp"`
+	Level     string    `json:"level,omitempty"` // parsed from JSON log lines when present
+	Message   string    `json:"message"`
+}
+
+// LogStream represents a stream of log entries from one or more services
 type LogStream interface {
-	Read() ([]byte, error)
+	// Next blocks until the next entry is available and returns io.EOF when the stream ends
+	Next() (LogEntry, error)
 	Close() error
 }
 
@@ -44,6 +81,7 @@ type InfrastructureProvisioner interface {
 
 // HealthChecker defines the interface for health checking
 type HealthChecker interface {
-	CheckHealth(ctx context.Context, endpoint string, timeout int) error
-	WaitForHealthy(ctx context.Context, endpoint string, interval, timeout int, retries int) error
+	CheckHealth(ctx context.Context, endpoint string, timeout time.Duration) error
+	// WaitForHealthy checks up to retries times, interval apart, and returns the last failure
+	WaitForHealthy(ctx context.Context, endpoint string, interval, timeout time.Duration, retries int) error
 }
@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+// OrphanKind is the kind of resource an Orphan is
+type OrphanKind string
+
+const (
+	OrphanComposeFile OrphanKind = "compose file"
+	OrphanContainer   OrphanKind = "container"
+	OrphanVolume      OrphanKind = "volume"
+)
+
+// Orphan is something grund generated or created that no registered service owns
+type Orphan struct {
+	Kind    OrphanKind
+	Name    string // compose directory, container or volume name
+	Service string // compose service it belonged to, when known
+}
+
+// OrphanCollector finds and removes what grund left behind for renamed or removed services
+type OrphanCollector interface {
+	// FindOrphans lists compose directories, containers and volumes none of the registered services own
+	FindOrphans(ctx context.Context, registered []service.ServiceName) ([]Orphan, error)
+	// RemoveOrphans deletes them: containers first, then volumes, then compose directories
+	RemoveOrphans(ctx context.Context, orphans []Orphan) error
+}
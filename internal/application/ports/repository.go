@@ -1,6 +1,9 @@
 package ports
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
 	"github.com/vivekkundariya/grund/internal/domain/service"
 )
@@ -16,6 +19,38 @@ type ServiceRepository interface {
 	ExtractTunnelConfig(name service.ServiceName) (*infrastructure.TunnelRequirement, error)
 }
 
+// ServiceConfigValidator checks a service's grund.yaml in depth
+type ServiceConfigValidator interface {
+	// ValidateFile reports every problem in a grund.yaml rather than stopping at the first
+	// When registered is non-nil, dependencies must be registered services.
+	// The service is nil when the file couldn't be loaded.
+	ValidateFile(configPath string, registered map[service.ServiceName]bool) (*service.Service, []ValidationIssue)
+}
+
+// ValidationIssue is a problem found in a grund.yaml
+type ValidationIssue struct {
+	Line    int    // 0 when unknown
+	Column  int    // 0 when unknown
+	Field   string // e.g. requires.infrastructure.sqs.queues[0].name
+	Message string
+}
+
+func (i ValidationIssue) String() string {
+	var b strings.Builder
+	if i.Line > 0 {
+		fmt.Fprintf(&b, "%d:", i.Line)
+		if i.Column > 0 {
+			fmt.Fprintf(&b, "%d:", i.Column)
+		}
+		b.WriteString(" ")
+	}
+	if i.Field != "" {
+		b.WriteString(i.Field + ": ")
+	}
+	b.WriteString(i.Message)
+	return b.String()
+}
+
 // ServiceRegistryRepository defines the interface for service registry
 type ServiceRegistryRepository interface {
 	GetServicePath(name service.ServiceName) (string, error)
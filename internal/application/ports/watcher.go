@@ -0,0 +1,11 @@
+package ports
+
+import "context"
+
+// FileWatcher watches a directory tree for changes
+type FileWatcher interface {
+	// Watch blocks until ctx is cancelled, calling onChange with the relative paths
+	// of changed files once a burst of changes settles. Paths matching an ignore
+	// glob are not watched.
+	Watch(ctx context.Context, dir string, ignore []string, onChange func(changed []string)) error
+}
@@ -0,0 +1,373 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/dependency"
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+// awsReference matches ${sqs.<name>., ${sns.<name>. and ${s3.<name>. placeholders
+var awsReference = regexp.MustCompile(`\$\{(sqs|sns|s3)\.([^.}$]+)\.`)
+
+// DoctorQuery represents a query for a workspace health check
+type DoctorQuery struct {
+	ConfigOnly bool // skip checks of this machine (engine, ports, disk, tunnel binaries)
+}
+
+// DoctorReport holds every check grund doctor ran, in order
+type DoctorReport struct {
+	Checks []ports.Check `json:"checks" yaml:"checks"`
+}
+
+// Count returns the number of checks with the given status
+func (r *DoctorReport) Count(status ports.CheckStatus) int {
+	n := 0
+	for _, c := range r.Checks {
+		if c.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *DoctorReport) add(category, name string, status ports.CheckStatus, hint, format string, args ...any) {
+	r.Checks = append(r.Checks, ports.Check{
+		Category: category,
+		Name:     name,
+		Status:   status,
+		Message:  fmt.Sprintf(format, args...),
+		Hint:     hint,
+	})
+}
+
+// DoctorQueryHandler checks the whole registry for problems that would otherwise surface mid-up
+type DoctorQueryHandler struct {
+	serviceRepo      ports.ServiceRepository
+	registryRepo     ports.ServiceRegistryRepository
+	composeGenerator ports.ComposeGenerator
+	orchestrator     ports.ContainerOrchestrator
+	secretChecker    ports.SecretChecker
+	prerequisites    ports.PrerequisiteChecker
+}
+
+// NewDoctorQueryHandler creates a new doctor query handler
+func NewDoctorQueryHandler(
+	serviceRepo ports.ServiceRepository,
+	registryRepo ports.ServiceRegistryRepository,
+	composeGenerator ports.ComposeGenerator,
+	orchestrator ports.ContainerOrchestrator,
+	secretChecker ports.SecretChecker,
+	prerequisites ports.PrerequisiteChecker,
+) *DoctorQueryHandler {
+	return &DoctorQueryHandler{
+		serviceRepo:      serviceRepo,
+		registryRepo:     registryRepo,
+		composeGenerator: composeGenerator,
+		orchestrator:     orchestrator,
+		secretChecker:    secretChecker,
+		prerequisites:    prerequisites,
+	}
+}
+
+// Handle executes the doctor query
+func (h *DoctorQueryHandler) Handle(ctx context.Context, query DoctorQuery) (*DoctorReport, error) {
+	registered, err := h.registryRepo.GetAllServices()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DoctorReport{}
+	services := h.loadServices(report, registered)
+	checkDependencies(report, services, registered)
+	checkInfrastructure(report, services)
+
+	var requirements []infrastructure.InfrastructureRequirements
+	for _, svc := range services {
+		requirements = append(requirements, svc.Dependencies.Infrastructure)
+	}
+	infra := infrastructure.Aggregate(requirements...)
+	assignments := h.composeGenerator.HostPorts(services, infra)
+	checkPortCollisions(report, assignments)
+
+	h.checkSecrets(report, services)
+
+	if !query.ConfigOnly {
+		h.checkPrerequisites(ctx, report, infra, assignments)
+	}
+	return report, nil
+}
+
+// loadServices loads every registered service, reporting each one that fails
+// FindAll stops at the first broken grund.yaml, so on failure services are loaded one by one.
+func (h *DoctorQueryHandler) loadServices(report *DoctorReport, registered map[service.ServiceName]ports.ServiceEntry) []*service.Service {
+	services, err := h.serviceRepo.FindAll()
+	if err != nil {
+		services = nil
+		for _, name := range sortedNames(registered) {
+			svc, err := h.serviceRepo.FindByName(name)
+			if err != nil {
+				report.add("services", name.String(), ports.CheckFail, "run grund service validate --all", "%v", err)
+				continue
+			}
+			services = append(services, svc)
+		}
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+
+	if len(services) == len(registered) {
+		report.add("services", "grund.yaml", ports.CheckOK, "", "%d service(s) loaded", len(services))
+	}
+	return services
+}
+
+// checkDependencies reports unregistered dependencies and dependency cycles
+func checkDependencies(report *DoctorReport, services []*service.Service, registered map[service.ServiceName]ports.ServiceEntry) {
+	graph := dependency.NewGraph()
+	dangling := 0
+	for _, svc := range services {
+		graph.AddService(svc)
+		for _, dep := range svc.Dependencies.Services {
+			if _, ok := registered[dep]; !ok {
+				dangling++
+				report.add("dependencies", svc.Name, ports.CheckFail,
+					fmt.Sprintf("add %s to services.yaml or remove it from requires.services", dep),
+					"depends on %s, which is not registered in services.yaml", dep)
+			}
+		}
+	}
+
+	cycles := graph.Cycles()
+	for _, cycle := range cycles {
+		names := make([]string, len(cycle))
+		for i, name := range cycle {
+			names[i] = name.String()
+		}
+		// grund up tolerates cycles, starting their members together, but they are rarely intended
+		report.add("dependencies", strings.Join(names, ", "), ports.CheckWarn,
+			"wait_for between these services cannot be honoured; break the cycle if it is not intended",
+			"dependency cycle between %s", strings.Join(names, " and "))
+	}
+
+	if dangling == 0 && len(cycles) == 0 {
+		report.add("dependencies", "graph", ports.CheckOK, "", "no cycles or unregistered dependencies")
+	}
+}
+
+// declaration is one service's definition of a shared resource
+type declaration struct {
+	service string
+	value   any
+}
+
+// checkInfrastructure reports shared resources that services declare differently
+// The shared instances are created from the first declaration, so later ones are silently ignored.
+func checkInfrastructure(report *DoctorReport, services []*service.Service) {
+	postgres := make(map[string][]declaration)
+	roles := make(map[string][]declaration)
+	mongo := make(map[string][]declaration)
+	queues := make(map[string][]declaration)
+	topics := make(map[string][]declaration)
+	buckets := make(map[string][]declaration)
+	tunnelTargets := make(map[string][]declaration)
+	providers := make(map[string][]declaration)
+
+	for _, svc := range services {
+		infra := svc.Dependencies.Infrastructure
+		if pg := infra.Postgres; pg != nil {
+			// Each role gets its own access to a shared database, so only migrations and seeds can clash
+			db := pg.AsDatabase()
+			db.Username, db.Password = "", ""
+			postgres[db.Database] = append(postgres[db.Database], declaration{svc.Name, db})
+			if pg.Username != "" {
+				roles[pg.Username] = append(roles[pg.Username], declaration{svc.Name, pg.Password})
+			}
+		}
+		if m := infra.MongoDB; m != nil {
+			mongo[m.Database] = append(mongo[m.Database], declaration{svc.Name, m.AsDatabase()})
+		}
+		if infra.SQS != nil {
+			for _, q := range infra.SQS.Queues {
+				queues[q.Name] = append(queues[q.Name], declaration{svc.Name, q})
+			}
+		}
+		if infra.SNS != nil {
+			for _, t := range infra.SNS.Topics {
+				topics[t.Name] = append(topics[t.Name], declaration{svc.Name, t})
+			}
+		}
+		if infra.S3 != nil {
+			for _, b := range infra.S3.Buckets {
+				buckets[b.Name] = append(buckets[b.Name], declaration{svc.Name, b})
+			}
+		}
+		if infra.Tunnel != nil {
+			providers["tunnel provider"] = append(providers["tunnel provider"], declaration{svc.Name, infra.Tunnel.Provider})
+			for _, t := range infra.Tunnel.Targets {
+				tunnelTargets[t.Name] = append(tunnelTargets[t.Name], declaration{svc.Name, t})
+			}
+		}
+	}
+
+	conflicts := 0
+	conflicts += reportConflicts(report, postgres, ports.CheckFail, "postgres database %s is declared with different migrations or seeds by %s; only %s's are used")
+	conflicts += reportConflicts(report, roles, ports.CheckFail, "postgres role %s is given different passwords by %s; it is created with %s's")
+	conflicts += reportConflicts(report, mongo, ports.CheckWarn, "mongodb database %s is declared with different seeds by %s; only %s's is used")
+	conflicts += reportConflicts(report, queues, ports.CheckFail, "sqs queue %s is declared differently by %s; it is created as %s declares it")
+	conflicts += reportConflicts(report, topics, ports.CheckFail, "sns topic %s is declared with different subscriptions by %s; only %s's are created")
+	conflicts += reportConflicts(report, buckets, ports.CheckWarn, "s3 bucket %s is declared with different seeds by %s; only %s's is used")
+	conflicts += reportConflicts(report, tunnelTargets, ports.CheckFail, "tunnel %s points at different targets in %s; only %s's is started")
+	conflicts += reportConflicts(report, providers, ports.CheckFail, "%s differs between %s; %s's is used for every tunnel")
+
+	// Resources referenced by subscriptions and env_refs must be declared by some service
+	declared := map[string]map[string][]declaration{"sqs": queues, "sns": topics, "s3": buckets}
+	for _, svc := range services {
+		if sns := svc.Dependencies.Infrastructure.SNS; sns != nil {
+			for _, topic := range sns.Topics {
+				for _, sub := range topic.Subscriptions {
+					for _, m := range awsReference.FindAllStringSubmatch(sub.Endpoint, -1) {
+						if _, ok := declared[m[1]][m[2]]; !ok {
+							conflicts++
+							report.add("infrastructure", svc.Name, ports.CheckFail,
+								fmt.Sprintf("declare %s under requires.infrastructure.%s of the service that owns it", m[2], m[1]),
+								"sns topic %s subscribes %s %s, which no service declares", topic.Name, m[1], m[2])
+						}
+					}
+				}
+			}
+		}
+
+		keys := make([]string, 0, len(svc.Environment.References))
+		for key := range svc.Environment.References {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			for _, m := range awsReference.FindAllStringSubmatch(svc.Environment.References[key], -1) {
+				if _, ok := declared[m[1]][m[2]]; !ok {
+					conflicts++
+					report.add("infrastructure", svc.Name, ports.CheckWarn,
+						fmt.Sprintf("declare %s under requires.infrastructure.%s of the service that owns it", m[2], m[1]),
+						"env_refs.%s references %s %s, which no service declares, so it is never created", key, m[1], m[2])
+				}
+			}
+		}
+	}
+
+	if conflicts == 0 {
+		report.add("infrastructure", "shared resources", ports.CheckOK, "", "no conflicting or undeclared resources")
+	}
+}
+
+// reportConflicts reports each resource whose declarations differ; format takes the
+// resource, the services declaring it and the service whose declaration wins
+func reportConflicts(report *DoctorReport, resources map[string][]declaration, status ports.CheckStatus, format string) int {
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	conflicts := 0
+	for _, name := range names {
+		decls := resources[name]
+		differs := false
+		for _, d := range decls[1:] {
+			if !reflect.DeepEqual(d.value, decls[0].value) {
+				differs = true
+			}
+		}
+		if !differs {
+			continue
+		}
+
+		conflicts++
+		owners := make([]string, len(decls))
+		for i, d := range decls {
+			owners[i] = d.service
+		}
+		message := fmt.Sprintf(format, name, strings.Join(owners, ", "), decls[0].service)
+		report.add("infrastructure", strings.Join(owners, ", "), status, "make the declarations identical, or use distinct names", "%s", message)
+	}
+	return conflicts
+}
+
+// checkPortCollisions reports services published on a different host port than they asked for,
+// and host ports claimed twice
+func checkPortCollisions(report *DoctorReport, assignments []ports.HostPortAssignment) {
+	collisions := 0
+	owners := make(map[int]string)
+	for _, a := range assignments {
+		if owner, ok := owners[a.HostPort]; ok {
+			collisions++
+			report.add("ports", a.Owner, ports.CheckFail, "change one of the service ports",
+				"host port %d is claimed by both %s and %s", a.HostPort, owner, a.Owner)
+			continue
+		}
+		owners[a.HostPort] = a.Owner
+
+		if a.ConflictsWith != "" && a.HostPort != a.ContainerPort {
+			collisions++
+			report.add("ports", a.Owner, ports.CheckWarn,
+				fmt.Sprintf("give %s a port of its own if anything reaches it by port %d", a.Owner, a.ContainerPort),
+				"port %d is also used by %s; %s is published on host port %d instead", a.ContainerPort, a.ConflictsWith, a.Owner, a.HostPort)
+		}
+	}
+	if collisions == 0 {
+		report.add("ports", "service ports", ports.CheckOK, "", "no port collisions")
+	}
+}
+
+// checkSecrets reports required secrets that no backend provides
+func (h *DoctorQueryHandler) checkSecrets(report *DoctorReport, services []*service.Service) {
+	missing, err := h.secretChecker.MissingSecrets(services)
+	if err != nil {
+		report.add("secrets", "backends", ports.CheckFail, "check secrets.backends in ~/.grund/config.yaml", "%v", err)
+		return
+	}
+	for _, m := range missing {
+		report.add("secrets", m.Name, ports.CheckFail, fmt.Sprintf("grund secrets set %s", m.Name),
+			"required by %s but not set", strings.Join(m.Services, ", "))
+	}
+	if len(missing) == 0 {
+		report.add("secrets", "required", ports.CheckOK, "", "all required secrets are set")
+	}
+}
+
+// checkPrerequisites checks the engine, host ports, disk space and tunnel binaries
+func (h *DoctorQueryHandler) checkPrerequisites(ctx context.Context, report *DoctorReport, infra infrastructure.InfrastructureRequirements, assignments []ports.HostPortAssignment) {
+	runtime := h.prerequisites.CheckRuntime(ctx)
+	report.Checks = append(report.Checks, runtime...)
+
+	// Ports published by grund's own running containers are expected to be taken
+	inUse := make(map[int]bool)
+	if statuses, err := h.orchestrator.GetAllServiceStatuses(ctx); err == nil {
+		for _, s := range statuses {
+			for _, p := range s.Ports {
+				inUse[p.HostPort] = true
+			}
+		}
+	}
+	report.Checks = append(report.Checks, h.prerequisites.CheckHostPorts(ctx, assignments, inUse)...)
+	report.Checks = append(report.Checks, h.prerequisites.CheckDiskSpace(ctx)...)
+
+	if infra.Tunnel != nil {
+		report.Checks = append(report.Checks, h.prerequisites.CheckTunnelProvider(infra.Tunnel.Provider))
+	}
+}
+
+func sortedNames(entries map[service.ServiceName]ports.ServiceEntry) []service.ServiceName {
+	names := make([]service.ServiceName, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
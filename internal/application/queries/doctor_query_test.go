@@ -0,0 +1,194 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+// mockDoctorServiceRepository serves services, failing to load the broken ones
+type mockDoctorServiceRepository struct {
+	mockGraphServiceRepository
+	services map[string]*service.Service
+	broken   map[string]bool
+}
+
+func (m *mockDoctorServiceRepository) FindByName(name service.ServiceName) (*service.Service, error) {
+	if m.broken[name.String()] {
+		return nil, fmt.Errorf("failed to read config file: grund.yaml not found")
+	}
+	return m.services[name.String()], nil
+}
+
+func (m *mockDoctorServiceRepository) FindAll() ([]*service.Service, error) {
+	if len(m.broken) > 0 {
+		return nil, fmt.Errorf("failed to load a service")
+	}
+	var all []*service.Service
+	for _, svc := range m.services {
+		all = append(all, svc)
+	}
+	return all, nil
+}
+
+// mockDoctorComposeGenerator returns fixed host port assignments
+type mockDoctorComposeGenerator struct {
+	mockEnvComposeGenerator
+	assignments []ports.HostPortAssignment
+}
+
+func (m *mockDoctorComposeGenerator) HostPorts(services []*service.Service, infra infrastructure.InfrastructureRequirements) []ports.HostPortAssignment {
+	return m.assignments
+}
+
+func (m *mockDoctorComposeGenerator) RecordStarted(names []service.ServiceName) error {
+	return nil
+}
+
+func (m *mockDoctorComposeGenerator) GenerateServices(services []*service.Service, infra infrastructure.InfrastructureRequirements, names []service.ServiceName) (*ports.ComposeFileSet, error) {
+	return nil, nil
+}
+
+func (m *mockDoctorComposeGenerator) GeneratedEnv(name service.ServiceName) (map[string]string, error) {
+	return nil, nil
+}
+
+type mockSecretChecker struct {
+	missing []ports.MissingSecret
+}
+
+func (m *mockSecretChecker) MissingSecrets(services []*service.Service) ([]ports.MissingSecret, error) {
+	return m.missing, nil
+}
+
+// mockPrerequisites records what it was asked to check
+type mockPrerequisites struct {
+	called   bool
+	inUse    map[int]bool
+	provider string
+}
+
+func (m *mockPrerequisites) CheckRuntime(ctx context.Context) []ports.Check {
+	m.called = true
+	return []ports.Check{{Category: "runtime", Name: "docker", Status: ports.CheckOK}}
+}
+
+func (m *mockPrerequisites) CheckHostPorts(ctx context.Context, assignments []ports.HostPortAssignment, inUse map[int]bool) []ports.Check {
+	m.inUse = inUse
+	return nil
+}
+
+func (m *mockPrerequisites) CheckDiskSpace(ctx context.Context) []ports.Check {
+	return []ports.Check{{Category: "disk", Name: "/", Status: ports.CheckWarn}}
+}
+
+func (m *mockPrerequisites) CheckTunnelProvider(provider string) ports.Check {
+	m.provider = provider
+	return ports.Check{Category: "tunnel", Name: provider, Status: ports.CheckFail}
+}
+
+func newDoctorService(name string, deps ...string) *service.Service {
+	svc := &service.Service{Name: name}
+	for _, dep := range deps {
+		svc.Dependencies.Services = append(svc.Dependencies.Services, service.ServiceName(dep))
+	}
+	return svc
+}
+
+// findCheck returns the first check in category whose message contains text
+func findCheck(report *DoctorReport, category, text string) *ports.Check {
+	for i, c := range report.Checks {
+		if c.Category == category && strings.Contains(c.Message, text) {
+			return &report.Checks[i]
+		}
+	}
+	return nil
+}
+
+func TestDoctorQueryHandler_Workspace(t *testing.T) {
+	orders := newDoctorService("orders", "billing", "payments")
+	orders.Dependencies.Infrastructure.Postgres = &infrastructure.PostgresConfig{Database: "shared", Migrations: "/orders/migrations"}
+	orders.Dependencies.Infrastructure.SNS = &infrastructure.SNSConfig{Topics: []infrastructure.TopicConfig{{
+		Name:          "orders",
+		Subscriptions: []infrastructure.SubscriptionConfig{{Protocol: "sqs", Endpoint: "${sqs.missing.arn}"}},
+	}}}
+	billing := newDoctorService("billing", "orders")
+	billing.Dependencies.Infrastructure.Postgres = &infrastructure.PostgresConfig{Database: "shared", Migrations: "/billing/migrations"}
+
+	repo := &mockDoctorServiceRepository{
+		services: map[string]*service.Service{"orders": orders, "billing": billing},
+		broken:   map[string]bool{"users": true},
+	}
+	registry := &mockGraphRegistryRepository{deps: map[string][]string{"orders": nil, "billing": nil, "users": nil}}
+	generator := &mockDoctorComposeGenerator{assignments: []ports.HostPortAssignment{
+		{Owner: "postgres", HostPort: 5432, ContainerPort: 5432},
+		{Owner: "billing", HostPort: 8080, ContainerPort: 8080},
+		{Owner: "orders", HostPort: 8081, ContainerPort: 8080, ConflictsWith: "billing"},
+	}}
+	secrets := &mockSecretChecker{missing: []ports.MissingSecret{{Name: "API_KEY", Services: []string{"orders"}}}}
+	prereqs := &mockPrerequisites{}
+
+	handler := NewDoctorQueryHandler(repo, registry, generator, &mockStatusOrchestrator{}, secrets, prereqs)
+	report, err := handler.Handle(context.Background(), DoctorQuery{ConfigOnly: true})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+
+	want := []struct {
+		category string
+		text     string
+		status   ports.CheckStatus
+	}{
+		{"services", "grund.yaml not found", ports.CheckFail},
+		{"dependencies", "depends on payments, which is not registered", ports.CheckFail},
+		{"dependencies", "cycle between billing and orders", ports.CheckWarn},
+		{"infrastructure", "postgres database shared is declared with different migrations", ports.CheckFail},
+		{"infrastructure", "subscribes sqs missing", ports.CheckFail},
+		{"ports", "published on host port 8081", ports.CheckWarn},
+		{"secrets", "required by orders", ports.CheckFail},
+	}
+	for _, w := range want {
+		c := findCheck(report, w.category, w.text)
+		if c == nil {
+			t.Errorf("no %s check containing %q in %+v", w.category, w.text, report.Checks)
+			continue
+		}
+		if c.Status != w.status {
+			t.Errorf("%s check %q status = %s, want %s", w.category, c.Message, c.Status, w.status)
+		}
+	}
+	if prereqs.called {
+		t.Error("ConfigOnly should skip the prerequisite checks")
+	}
+}
+
+func TestDoctorQueryHandler_Prerequisites(t *testing.T) {
+	api := newDoctorService("api")
+	api.Dependencies.Infrastructure.Tunnel = &infrastructure.TunnelRequirement{Provider: "ngrok"}
+
+	repo := &mockDoctorServiceRepository{services: map[string]*service.Service{"api": api}}
+	registry := &mockGraphRegistryRepository{deps: map[string][]string{"api": nil}}
+	orchestrator := &mockStatusOrchestrator{}
+	prereqs := &mockPrerequisites{}
+
+	handler := NewDoctorQueryHandler(repo, registry, &mockDoctorComposeGenerator{}, orchestrator, &mockSecretChecker{}, prereqs)
+	report, err := handler.Handle(context.Background(), DoctorQuery{})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+
+	if !prereqs.called || prereqs.inUse == nil {
+		t.Error("the runtime and host ports should be checked")
+	}
+	if prereqs.provider != "ngrok" {
+		t.Errorf("tunnel provider checked = %q, want ngrok", prereqs.provider)
+	}
+	if report.Count(ports.CheckFail) != 1 || report.Count(ports.CheckWarn) != 1 {
+		t.Errorf("report = %+v, want the tunnel failure and the disk warning", report.Checks)
+	}
+}
@@ -0,0 +1,70 @@
+package queries
+
+import (
+	"fmt"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+// EnvQuery represents a query for a service's resolved environment
+type EnvQuery struct {
+	ServiceName string
+	Host        bool // resolve for a process on the host instead of the service's container
+}
+
+// EnvQueryHandler handles resolved environment queries
+type EnvQueryHandler struct {
+	serviceRepo      ports.ServiceRepository
+	composeGenerator ports.ComposeGenerator
+}
+
+// NewEnvQueryHandler creates a new env query handler
+func NewEnvQueryHandler(serviceRepo ports.ServiceRepository, composeGenerator ports.ComposeGenerator) *EnvQueryHandler {
+	return &EnvQueryHandler{
+		serviceRepo:      serviceRepo,
+		composeGenerator: composeGenerator,
+	}
+}
+
+// Handle executes the env query
+// The service is resolved together with its transitive dependencies, the same
+// set `grund up <service>` starts, so peers and published ports match that stack.
+func (h *EnvQueryHandler) Handle(query EnvQuery) (map[string]string, error) {
+	var services []*service.Service
+	loaded := make(map[service.ServiceName]bool)
+
+	var load func(name service.ServiceName) error
+	load = func(name service.ServiceName) error {
+		if loaded[name] {
+			return nil
+		}
+		loaded[name] = true
+
+		svc, err := h.serviceRepo.FindByName(name)
+		if err != nil {
+			return fmt.Errorf("service %s: %w", name, err)
+		}
+		services = append(services, svc)
+
+		for _, dep := range svc.Dependencies.Services {
+			if err := load(dep); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	name := service.ServiceName(query.ServiceName)
+	if err := load(name); err != nil {
+		return nil, err
+	}
+
+	reqs := make([]infrastructure.InfrastructureRequirements, len(services))
+	for i, svc := range services {
+		reqs[i] = svc.Dependencies.Infrastructure
+	}
+
+	return h.composeGenerator.ResolveEnv(services, infrastructure.Aggregate(reqs...), name, query.Host)
+}
@@ -0,0 +1,87 @@
+package queries
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+// mockEnvComposeGenerator records the ResolveEnv call
+type mockEnvComposeGenerator struct {
+	services []string
+	name     service.ServiceName
+	host     bool
+}
+
+func (m *mockEnvComposeGenerator) Generate(services []*service.Service, infra infrastructure.InfrastructureRequirements) (*ports.ComposeFileSet, error) {
+	return nil, nil
+}
+
+func (m *mockEnvComposeGenerator) GenerateWithTunnels(services []*service.Service, infra infrastructure.InfrastructureRequirements, tunnelCtx map[string]ports.TunnelContext) (*ports.ComposeFileSet, error) {
+	return nil, nil
+}
+
+func (m *mockEnvComposeGenerator) SetLocalServices(names []service.ServiceName) {}
+
+func (m *mockEnvComposeGenerator) ResolveEnv(services []*service.Service, infra infrastructure.InfrastructureRequirements, name service.ServiceName, host bool) (map[string]string, error) {
+	for _, svc := range services {
+		m.services = append(m.services, svc.Name)
+	}
+	m.name = name
+	m.host = host
+	return map[string]string{"SERVICE": name.String()}, nil
+}
+
+func (m *mockEnvComposeGenerator) HostPorts(services []*service.Service, infra infrastructure.InfrastructureRequirements) []ports.HostPortAssignment {
+	return nil
+}
+
+func (m *mockEnvComposeGenerator) RecordStarted(names []service.ServiceName) error {
+	return nil
+}
+
+func (m *mockEnvComposeGenerator) GenerateServices(services []*service.Service, infra infrastructure.InfrastructureRequirements, names []service.ServiceName) (*ports.ComposeFileSet, error) {
+	return nil, nil
+}
+
+func (m *mockEnvComposeGenerator) GeneratedEnv(name service.ServiceName) (map[string]string, error) {
+	return nil, nil
+}
+
+func TestEnvQueryHandler_Handle(t *testing.T) {
+	repo := &mockGraphServiceRepository{deps: map[string][]string{
+		"orders": {"users", "auth"},
+		"users":  {"auth"},
+		"auth":   {},
+		"batch":  {},
+	}}
+	generator := &mockEnvComposeGenerator{}
+	handler := NewEnvQueryHandler(repo, generator)
+
+	env, err := handler.Handle(EnvQuery{ServiceName: "orders", Host: true})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+
+	if env["SERVICE"] != "orders" {
+		t.Errorf("env = %v", env)
+	}
+	if generator.name != "orders" || !generator.host {
+		t.Errorf("ResolveEnv(name=%s, host=%v), want orders on the host", generator.name, generator.host)
+	}
+	if got := strings.Join(generator.services, ","); got != "orders,users,auth" {
+		t.Errorf("resolved with services %s, want orders,users,auth", got)
+	}
+}
+
+func TestEnvQueryHandler_Handle_UnknownService(t *testing.T) {
+	repo := &mockGraphServiceRepository{deps: map[string][]string{"orders": {"missing"}}}
+	handler := NewEnvQueryHandler(repo, &mockEnvComposeGenerator{})
+
+	if _, err := handler.Handle(EnvQuery{ServiceName: "orders"}); err == nil {
+		t.Error("Handle() should fail when a dependency can't be loaded")
+	}
+}
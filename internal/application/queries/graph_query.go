@@ -0,0 +1,100 @@
+package queries
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/dependency"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+// GraphQuery represents a query for the service dependency graph
+type GraphQuery struct {
+	ServiceNames []string // empty means every registered service
+}
+
+// GraphResult is the dependency graph of the queried services and their dependencies
+type GraphResult struct {
+	Graph  *dependency.Graph
+	Roots  []service.ServiceName // where trees start: the queried services, or those nothing depends on
+	Cycles [][]service.ServiceName
+}
+
+// GraphQueryHandler handles dependency graph queries
+type GraphQueryHandler struct {
+	serviceRepo  ports.ServiceRepository
+	registryRepo ports.ServiceRegistryRepository
+}
+
+// NewGraphQueryHandler creates a new graph query handler
+func NewGraphQueryHandler(serviceRepo ports.ServiceRepository, registryRepo ports.ServiceRegistryRepository) *GraphQueryHandler {
+	return &GraphQueryHandler{
+		serviceRepo:  serviceRepo,
+		registryRepo: registryRepo,
+	}
+}
+
+// Handle executes the graph query
+func (h *GraphQueryHandler) Handle(query GraphQuery) (*GraphResult, error) {
+	names := make([]service.ServiceName, len(query.ServiceNames))
+	for i, name := range query.ServiceNames {
+		names[i] = service.ServiceName(name)
+	}
+
+	if len(names) == 0 {
+		registered, err := h.registryRepo.GetAllServices()
+		if err != nil {
+			return nil, err
+		}
+		for name := range registered {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	}
+
+	// Load the services and everything they depend on
+	graph := dependency.NewGraph()
+	loaded := make(map[service.ServiceName]bool)
+	var load func(name service.ServiceName) error
+	load = func(name service.ServiceName) error {
+		if loaded[name] {
+			return nil
+		}
+		loaded[name] = true
+
+		svc, err := h.serviceRepo.FindByName(name)
+		if err != nil {
+			return fmt.Errorf("service %s: %w", name, err)
+		}
+		graph.AddService(svc)
+
+		for _, dep := range svc.Dependencies.Services {
+			if err := load(dep); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, name := range names {
+		if err := load(name); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := graph.Build(); err != nil {
+		return nil, err
+	}
+
+	roots := names
+	if len(query.ServiceNames) == 0 {
+		roots = graph.Roots()
+	}
+
+	return &GraphResult{
+		Graph:  graph,
+		Roots:  roots,
+		Cycles: graph.Cycles(),
+	}, nil
+}
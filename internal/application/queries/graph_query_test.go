@@ -0,0 +1,117 @@
+package queries
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+// mockGraphServiceRepository serves services given as name -> dependencies
+type mockGraphServiceRepository struct {
+	deps map[string][]string
+}
+
+func (m *mockGraphServiceRepository) FindByName(name service.ServiceName) (*service.Service, error) {
+	names, ok := m.deps[name.String()]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	svc := &service.Service{Name: name.String()}
+	for _, dep := range names {
+		svc.Dependencies.Services = append(svc.Dependencies.Services, service.ServiceName(dep))
+	}
+	return svc, nil
+}
+
+func (m *mockGraphServiceRepository) FindAll() ([]*service.Service, error) {
+	return nil, nil
+}
+
+func (m *mockGraphServiceRepository) Save(svc *service.Service) error {
+	return nil
+}
+
+func (m *mockGraphServiceRepository) ExtractTunnelConfig(name service.ServiceName) (*infrastructure.TunnelRequirement, error) {
+	return nil, nil
+}
+
+// mockGraphRegistryRepository registers the same services
+type mockGraphRegistryRepository struct {
+	deps map[string][]string
+}
+
+func (m *mockGraphRegistryRepository) GetServicePath(name service.ServiceName) (string, error) {
+	return "", nil
+}
+
+func (m *mockGraphRegistryRepository) GetAllServices() (map[service.ServiceName]ports.ServiceEntry, error) {
+	all := make(map[service.ServiceName]ports.ServiceEntry)
+	for name := range m.deps {
+		all[service.ServiceName(name)] = ports.ServiceEntry{}
+	}
+	return all, nil
+}
+
+// newGraphRepos returns repositories for services given as name -> dependencies
+func newGraphRepos(deps map[string][]string) (ports.ServiceRepository, ports.ServiceRegistryRepository) {
+	return &mockGraphServiceRepository{deps: deps}, &mockGraphRegistryRepository{deps: deps}
+}
+
+func TestGraphQueryHandler_Handle_AllServices(t *testing.T) {
+	handler := NewGraphQueryHandler(newGraphRepos(map[string][]string{
+		"orders": {"users"},
+		"users":  {"auth"},
+		"auth":   {},
+		"batch":  {"auth"},
+	}))
+
+	result, err := handler.Handle(GraphQuery{})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+
+	if len(result.Graph.Names()) != 4 {
+		t.Errorf("graph has %v, want 4 services", result.Graph.Names())
+	}
+	if fmt.Sprint(result.Roots) != "[batch orders]" {
+		t.Errorf("Roots = %v, want [batch orders]", result.Roots)
+	}
+	if len(result.Cycles) != 0 {
+		t.Errorf("Cycles = %v, want none", result.Cycles)
+	}
+}
+
+func TestGraphQueryHandler_Handle_SelectedServices(t *testing.T) {
+	serviceRepo, registryRepo := newGraphRepos(map[string][]string{
+		"orders": {"users"},
+		"users":  {"orders"},
+		"batch":  {},
+	})
+	handler := NewGraphQueryHandler(serviceRepo, registryRepo)
+
+	result, err := handler.Handle(GraphQuery{ServiceNames: []string{"orders"}})
+	if err != nil {
+		t.Fatalf("Handle() error: %v", err)
+	}
+
+	if fmt.Sprint(result.Graph.Names()) != "[orders users]" {
+		t.Errorf("graph has %v, want only orders and its dependencies", result.Graph.Names())
+	}
+	if fmt.Sprint(result.Roots) != "[orders]" {
+		t.Errorf("Roots = %v, want [orders]", result.Roots)
+	}
+	if fmt.Sprint(result.Cycles) != "[[orders users]]" {
+		t.Errorf("Cycles = %v, want [[orders users]]", result.Cycles)
+	}
+}
+
+func TestGraphQueryHandler_Handle_UnknownService(t *testing.T) {
+	handler := NewGraphQueryHandler(newGraphRepos(map[string][]string{"orders": {"missing"}}))
+
+	if _, err := handler.Handle(GraphQuery{ServiceNames: []string{"orders"}}); err == nil {
+		t.Error("Handle() should fail when a dependency can't be loaded")
+	}
+}
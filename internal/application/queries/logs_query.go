@@ -0,0 +1,35 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+// LogsQuery represents a query for service logs
+type LogsQuery struct {
+	ServiceNames []string // empty means all services
+	Options      ports.LogOptions
+}
+
+// LogsQueryHandler handles logs queries
+type LogsQueryHandler struct {
+	orchestrator ports.ContainerOrchestrator
+}
+
+// NewLogsQueryHandler creates a new logs query handler
+func NewLogsQueryHandler(orchestrator ports.ContainerOrchestrator) *LogsQueryHandler {
+	return &LogsQueryHandler{
+		orchestrator: orchestrator,
+	}
+}
+
+// Handle executes the logs query; the caller must close the returned stream
+func (h *LogsQueryHandler) Handle(ctx context.Context, query LogsQuery) (ports.LogStream, error) {
+	names := make([]service.ServiceName, len(query.ServiceNames))
+	for i, name := range query.ServiceNames {
+		names[i] = service.ServiceName(name)
+	}
+	return h.orchestrator.GetLogs(ctx, names, query.Options)
+}
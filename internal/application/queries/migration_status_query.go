@@ -0,0 +1,43 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+// MigrationStatusQuery represents a query for a service's migration status
+type MigrationStatusQuery struct {
+	ServiceName string
+}
+
+// MigrationStatusQueryHandler handles migration status queries
+type MigrationStatusQueryHandler struct {
+	serviceRepo ports.ServiceRepository
+	migrator    ports.DatabaseMigrator
+}
+
+// NewMigrationStatusQueryHandler creates a new migration status query handler
+func NewMigrationStatusQueryHandler(serviceRepo ports.ServiceRepository, migrator ports.DatabaseMigrator) *MigrationStatusQueryHandler {
+	return &MigrationStatusQueryHandler{
+		serviceRepo: serviceRepo,
+		migrator:    migrator,
+	}
+}
+
+// Handle executes the migration status query
+func (h *MigrationStatusQueryHandler) Handle(ctx context.Context, query MigrationStatusQuery) ([]ports.MigrationStatus, error) {
+	svc, err := h.serviceRepo.FindByName(service.ServiceName(query.ServiceName))
+	if err != nil {
+		return nil, fmt.Errorf("service %s: %w", query.ServiceName, err)
+	}
+
+	pg := svc.Dependencies.Infrastructure.Postgres
+	if pg == nil || pg.Migrations == "" {
+		return nil, fmt.Errorf("service %s has no postgres migrations configured", svc.Name)
+	}
+
+	return h.migrator.Status(ctx, pg.AsDatabase())
+}
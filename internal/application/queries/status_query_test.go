@@ -17,7 +17,7 @@ func (m *mockStatusOrchestrator) StartInfrastructure(ctx context.Context) error
 	return nil
 }
 
-func (m *mockStatusOrchestrator) StartServices(ctx context.Context, services []service.ServiceName) error {
+func (m *mockStatusOrchestrator) StartServices(ctx context.Context, services []service.ServiceName, build bool) error {
 	return nil
 }
 
@@ -25,10 +25,18 @@ func (m *mockStatusOrchestrator) StopServices(ctx context.Context) error {
 	return nil
 }
 
+func (m *mockStatusOrchestrator) RemoveServices(ctx context.Context, names []service.ServiceName) error {
+	return nil
+}
+
 func (m *mockStatusOrchestrator) RestartService(ctx context.Context, name service.ServiceName) error {
 	return nil
 }
 
+func (m *mockStatusOrchestrator) RecreateServices(ctx context.Context, names []service.ServiceName, build bool) error {
+	return nil
+}
+
 func (m *mockStatusOrchestrator) GetServiceStatus(ctx context.Context, name service.ServiceName) (ports.ServiceStatus, error) {
 	if m.statusFunc != nil {
 		return m.statusFunc(name)
@@ -40,7 +48,7 @@ func (m *mockStatusOrchestrator) GetServiceStatus(ctx context.Context, name serv
 	}, nil
 }
 
-func (m *mockStatusOrchestrator) GetLogs(ctx context.Context, name service.ServiceName, follow bool, tail int) (ports.LogStream, error) {
+func (m *mockStatusOrchestrator) GetLogs(ctx context.Context, names []service.ServiceName, opts ports.LogOptions) (ports.LogStream, error) {
 	return nil, nil
 }
 
@@ -0,0 +1,114 @@
+package queries
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+// ValidateQuery represents a query to validate a single grund.yaml
+type ValidateQuery struct {
+	ConfigPath string
+}
+
+// ValidationResult holds the issues found in one grund.yaml
+type ValidationResult struct {
+	Service    string
+	ConfigPath string
+	Issues     []ports.ValidationIssue
+}
+
+// Valid reports whether the file has no issues
+func (r ValidationResult) Valid() bool {
+	return len(r.Issues) == 0
+}
+
+// ValidateQueryHandler handles grund.yaml validation queries
+type ValidateQueryHandler struct {
+	validator    ports.ServiceConfigValidator
+	registryRepo ports.ServiceRegistryRepository
+}
+
+// NewValidateQueryHandler creates a new validate query handler
+// registryRepo may be nil outside a project; dependencies are then not checked against services.yaml.
+func NewValidateQueryHandler(validator ports.ServiceConfigValidator, registryRepo ports.ServiceRegistryRepository) *ValidateQueryHandler {
+	return &ValidateQueryHandler{
+		validator:    validator,
+		registryRepo: registryRepo,
+	}
+}
+
+// Handle validates one grund.yaml
+func (h *ValidateQueryHandler) Handle(query ValidateQuery) (*ValidationResult, error) {
+	var registered map[service.ServiceName]bool
+	if h.registryRepo != nil {
+		entries, err := h.registryRepo.GetAllServices()
+		if err != nil {
+			return nil, err
+		}
+		registered = registeredNames(entries)
+	}
+	return h.validate(query.ConfigPath, registered), nil
+}
+
+// HandleAll validates the grund.yaml of every registered service, sorted by name
+func (h *ValidateQueryHandler) HandleAll() ([]ValidationResult, error) {
+	if h.registryRepo == nil {
+		return nil, fmt.Errorf("validating all services requires a services.yaml")
+	}
+	entries, err := h.registryRepo.GetAllServices()
+	if err != nil {
+		return nil, err
+	}
+	registered := registeredNames(entries)
+
+	names := make([]service.ServiceName, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	results := make([]ValidationResult, 0, len(names))
+	for _, name := range names {
+		path, err := h.registryRepo.GetServicePath(name)
+		if err != nil {
+			results = append(results, ValidationResult{
+				Service: name.String(),
+				Issues:  []ports.ValidationIssue{{Message: err.Error()}},
+			})
+			continue
+		}
+
+		result := h.validate(filepath.Join(path, "grund.yaml"), registered)
+		if result.Service != "" && result.Service != name.String() {
+			result.Issues = append(result.Issues, ports.ValidationIssue{
+				Field:   "service.name",
+				Message: fmt.Sprintf("%q does not match the services.yaml entry %q", result.Service, name),
+			})
+		}
+		result.Service = name.String()
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+func (h *ValidateQueryHandler) validate(configPath string, registered map[service.ServiceName]bool) *ValidationResult {
+	result := &ValidationResult{ConfigPath: configPath}
+	svc, issues := h.validator.ValidateFile(configPath, registered)
+	if svc != nil {
+		result.Service = svc.Name
+	}
+	result.Issues = issues
+	return result
+}
+
+func registeredNames(entries map[service.ServiceName]ports.ServiceEntry) map[service.ServiceName]bool {
+	names := make(map[service.ServiceName]bool, len(entries))
+	for name := range entries {
+		names[name] = true
+	}
+	return names
+}
@@ -0,0 +1,81 @@
+package queries
+
+import (
+	"testing"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+// mockConfigValidator reports one issue for every config path in issues
+type mockConfigValidator struct {
+	names      map[string]string // config path -> service name in the file
+	issues     map[string]string
+	registered []map[service.ServiceName]bool
+}
+
+func (m *mockConfigValidator) ValidateFile(configPath string, registered map[service.ServiceName]bool) (*service.Service, []ports.ValidationIssue) {
+	m.registered = append(m.registered, registered)
+	var issues []ports.ValidationIssue
+	if msg, ok := m.issues[configPath]; ok {
+		issues = append(issues, ports.ValidationIssue{Line: 1, Message: msg})
+	}
+	return &service.Service{Name: m.names[configPath]}, issues
+}
+
+func TestValidateQueryHandler_HandleAll(t *testing.T) {
+	validator := &mockConfigValidator{
+		names: map[string]string{
+			"a/grund.yaml": "api",
+			"b/grund.yaml": "renamed",
+		},
+		issues: map[string]string{"a/grund.yaml": "bad type"},
+	}
+	registry := &mockGraphRegistryRepository{deps: map[string][]string{"api": nil, "billing": nil}}
+	handler := NewValidateQueryHandler(validator, &pathRegistry{registry, map[string]string{"api": "a", "billing": "b"}})
+
+	results, err := handler.HandleAll()
+	if err != nil {
+		t.Fatalf("HandleAll() error: %v", err)
+	}
+	if len(results) != 2 || results[0].Service != "api" || results[1].Service != "billing" {
+		t.Fatalf("results = %+v, want api then billing", results)
+	}
+	if results[0].Valid() || results[0].ConfigPath != "a/grund.yaml" {
+		t.Errorf("api result = %+v, want the validator's issue", results[0])
+	}
+	if len(results[1].Issues) != 1 || results[1].Issues[0].Field != "service.name" {
+		t.Errorf("billing result = %+v, want a name mismatch", results[1])
+	}
+	for _, registered := range validator.registered {
+		if !registered["api"] || !registered["billing"] {
+			t.Errorf("registered = %v, want every registry entry", registered)
+		}
+	}
+}
+
+func TestValidateQueryHandler_WithoutRegistry(t *testing.T) {
+	validator := &mockConfigValidator{}
+	handler := NewValidateQueryHandler(validator, nil)
+
+	result, err := handler.Handle(ValidateQuery{ConfigPath: "grund.yaml"})
+	if err != nil || !result.Valid() {
+		t.Fatalf("Handle() = %+v, %v", result, err)
+	}
+	if validator.registered[0] != nil {
+		t.Error("dependencies should not be checked without a registry")
+	}
+	if _, err := handler.HandleAll(); err == nil {
+		t.Error("HandleAll() should require a registry")
+	}
+}
+
+// pathRegistry serves service paths on top of another registry
+type pathRegistry struct {
+	ports.ServiceRegistryRepository
+	paths map[string]string
+}
+
+func (r *pathRegistry) GetServicePath(name service.ServiceName) (string, error) {
+	return r.paths[name.String()], nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/vivekkundariya/grund/internal/infrastructure/docker"
 	"github.com/vivekkundariya/grund/internal/infrastructure/generator"
 	"github.com/vivekkundariya/grund/internal/infrastructure/tunnel"
+	"github.com/vivekkundariya/grund/internal/infrastructure/watcher"
 )
 
 // Container holds all dependencies (Dependency Injection Container)
@@ -20,6 +21,8 @@ import (
 type Container struct {
 	// Configuration
 	ConfigResolver    *appconfig.ConfigResolver
+	Environment       *appconfig.Environment
+	Runtime           *docker.Runtime
 	OrchestrationRoot string
 	ServicesPath      string
 
@@ -38,10 +41,19 @@ type Container struct {
 	UpCommandHandler      *commands.UpCommandHandler
 	DownCommandHandler    *commands.DownCommandHandler
 	RestartCommandHandler *commands.RestartCommandHandler
+	MigrateCommandHandler *commands.MigrateCommandHandler
+	SeedCommandHandler    *commands.SeedCommandHandler
+	PruneCommandHandler   *commands.PruneCommandHandler
 
 	// Query Handlers
-	StatusQueryHandler *queries.StatusQueryHandler
-	ConfigQueryHandler *queries.ConfigQueryHandler
+	StatusQueryHandler          *queries.StatusQueryHandler
+	ConfigQueryHandler          *queries.ConfigQueryHandler
+	MigrationStatusQueryHandler *queries.MigrationStatusQueryHandler
+	LogsQueryHandler            *queries.LogsQueryHandler
+	GraphQueryHandler           *queries.GraphQueryHandler
+	EnvQueryHandler             *queries.EnvQueryHandler
+	ValidateQueryHandler        *queries.ValidateQueryHandler
+	DoctorQueryHandler          *queries.DoctorQueryHandler
 }
 
 // NewContainer creates a new dependency injection container
@@ -52,11 +64,13 @@ func NewContainer(orchestrationRoot string) (*Container, error) {
 		return nil, fmt.Errorf("services.yaml not found at %s", servicesPath)
 	}
 
-	return NewContainerWithConfig(orchestrationRoot, servicesPath, nil)
+	runtime := docker.NewRuntime(appconfig.DefaultRuntime(), docker.NewExecRunner())
+	return NewContainerWithConfig(orchestrationRoot, servicesPath, nil, appconfig.DefaultEnvironment(), runtime)
 }
 
 // NewContainerWithConfig creates a new dependency injection container with config resolver
-func NewContainerWithConfig(orchestrationRoot, servicesPath string, configResolver *appconfig.ConfigResolver) (*Container, error) {
+// Everything grund creates is namespaced by env; all engine and compose commands run through runtime.
+func NewContainerWithConfig(orchestrationRoot, servicesPath string, configResolver *appconfig.ConfigResolver, env *appconfig.Environment, runtime *docker.Runtime) (*Container, error) {
 	// Validate services file exists
 	if _, err := os.Stat(servicesPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("services file not found at %s", servicesPath)
@@ -75,26 +89,29 @@ func NewContainerWithConfig(orchestrationRoot, servicesPath string, configResolv
 	if configResolver != nil {
 		localstackEndpoint = configResolver.GetLocalStackEndpoint()
 	}
+	localstackEndpoint = env.LocalStackEndpoint(localstackEndpoint)
 
-	// Get grund tmp directory for compose file generation
-	grundTmpDir, err := docker.GetGrundTmpDir()
+	// Get the environment's tmp directory for compose file generation
+	grundTmpDir, err := docker.GetGrundTmpDir(env)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get grund tmp directory: %w", err)
 	}
 
 	// Initialize infrastructure adapters
-	orchestrator := docker.NewDockerOrchestrator(orchestrationRoot)
+	orchestrator := docker.NewEngineAPIOrchestrator(orchestrationRoot, env, runtime)
 	healthChecker := docker.NewHTTPHealthChecker()
 
 	// Discover existing compose files and set them on the orchestrator
 	// This allows status, logs, etc. to work without running 'up' first
-	if existingFiles, err := docker.DiscoverComposeFiles(); err == nil {
+	if existingFiles, err := docker.DiscoverComposeFiles(env); err == nil {
 		orchestrator.SetComposeFiles(existingFiles.AllPaths())
 	}
 
 	// Initialize provisioners
-	postgresProvisioner := docker.NewPostgresProvisioner()
-	mongodbProvisioner := docker.NewMongoDBProvisioner()
+	postgresMigrator := docker.NewPostgresMigrator(env, runtime)
+	databaseSeeder := docker.NewDatabaseSeeder(env, runtime)
+	postgresProvisioner := docker.NewPostgresProvisioner(env, runtime, postgresMigrator, databaseSeeder)
+	mongodbProvisioner := docker.NewMongoDBProvisioner(databaseSeeder)
 	redisProvisioner := docker.NewRedisProvisioner()
 	localstackProvisioner := aws.NewLocalStackProvisioner(localstackEndpoint)
 	provisioner := docker.NewCompositeInfrastructureProvisioner(
@@ -105,12 +122,17 @@ func NewContainerWithConfig(orchestrationRoot, servicesPath string, configResolv
 	)
 
 	// Initialize generators
-	composeGenerator := generator.NewComposeGenerator(grundTmpDir)
+	composeGenerator := generator.NewComposeGenerator(grundTmpDir, env, runtime.ContainerRuntime)
 	envResolver := generator.NewEnvironmentResolver()
 
 	// Initialize tunnel manager
 	tunnelManager := tunnel.NewManager()
 
+	// Initialize file watcher for hot reload
+	fileWatcher := watcher.NewPollingWatcher()
+
+	prerequisites := docker.NewPrerequisiteChecker(runtime, env)
+
 	// Initialize command handlers
 	upHandler := commands.NewUpCommandHandler(
 		serviceRepo,
@@ -120,10 +142,15 @@ func NewContainerWithConfig(orchestrationRoot, servicesPath string, configResolv
 		composeGenerator,
 		healthChecker,
 		tunnelManager,
+		fileWatcher,
+		prerequisites,
 	)
 
-	downHandler := commands.NewDownCommandHandler(orchestrator)
-	restartHandler := commands.NewRestartCommandHandler(orchestrator)
+	downHandler := commands.NewDownCommandHandler(serviceRepo, orchestrator)
+	restartHandler := commands.NewRestartCommandHandler(serviceRepo, orchestrator, composeGenerator, healthChecker)
+	migrateHandler := commands.NewMigrateCommandHandler(serviceRepo, postgresMigrator)
+	seedHandler := commands.NewSeedCommandHandler(serviceRepo, databaseSeeder)
+	pruneHandler := commands.NewPruneCommandHandler(registryRepo, docker.NewOrphanCollector(grundTmpDir, env, runtime))
 
 	// Initialize query handlers
 	statusHandler := queries.NewStatusQueryHandler(orchestrator)
@@ -132,22 +159,53 @@ func NewContainerWithConfig(orchestrationRoot, servicesPath string, configResolv
 		registryRepo,
 		envResolver,
 	)
+	migrationStatusHandler := queries.NewMigrationStatusQueryHandler(serviceRepo, postgresMigrator)
+	logsHandler := queries.NewLogsQueryHandler(orchestrator)
+	graphHandler := queries.NewGraphQueryHandler(serviceRepo, registryRepo)
+	envHandler := queries.NewEnvQueryHandler(serviceRepo, composeGenerator)
+	validateHandler := queries.NewValidateQueryHandler(config.NewServiceConfigValidator(envResolver, serviceRepo), registryRepo)
+	doctorHandler := queries.NewDoctorQueryHandler(
+		serviceRepo,
+		registryRepo,
+		composeGenerator,
+		orchestrator,
+		generator.NewSecretsLoader(),
+		prerequisites,
+	)
 
 	return &Container{
-		ConfigResolver:        configResolver,
-		OrchestrationRoot:     orchestrationRoot,
-		ServicesPath:          servicesPath,
-		ServiceRepo:           serviceRepo,
-		RegistryRepo:          registryRepo,
-		Orchestrator:          orchestrator,
-		Provisioner:           provisioner,
-		ComposeGenerator:      composeGenerator,
-		EnvResolver:           envResolver,
-		HealthChecker:         healthChecker,
-		UpCommandHandler:      upHandler,
-		DownCommandHandler:    downHandler,
-		RestartCommandHandler: restartHandler,
-		StatusQueryHandler:    statusHandler,
-		ConfigQueryHandler:    configHandler,
+		ConfigResolver:              configResolver,
+		Environment:                 env,
+		Runtime:                     runtime,
+		OrchestrationRoot:           orchestrationRoot,
+		ServicesPath:                servicesPath,
+		ServiceRepo:                 serviceRepo,
+		RegistryRepo:                registryRepo,
+		Orchestrator:                orchestrator,
+		Provisioner:                 provisioner,
+		ComposeGenerator:            composeGenerator,
+		EnvResolver:                 envResolver,
+		HealthChecker:               healthChecker,
+		UpCommandHandler:            upHandler,
+		DownCommandHandler:          downHandler,
+		RestartCommandHandler:       restartHandler,
+		MigrateCommandHandler:       migrateHandler,
+		SeedCommandHandler:          seedHandler,
+		PruneCommandHandler:         pruneHandler,
+		StatusQueryHandler:          statusHandler,
+		ConfigQueryHandler:          configHandler,
+		MigrationStatusQueryHandler: migrationStatusHandler,
+		LogsQueryHandler:            logsHandler,
+		GraphQueryHandler:           graphHandler,
+		EnvQueryHandler:             envHandler,
+		ValidateQueryHandler:        validateHandler,
+		DoctorQueryHandler:          doctorHandler,
 	}, nil
 }
+
+// NewValidateQueryHandler creates a grund.yaml validator for use outside a project
+// Without services.yaml, dependencies aren't checked against registered services.
+func NewValidateQueryHandler() *queries.ValidateQueryHandler {
+	validator := config.NewServiceConfigValidator(generator.NewEnvironmentResolver(), nil)
+	return queries.NewValidateQueryHandler(validator, nil)
+}
@@ -13,6 +13,12 @@ import (
 	"github.com/vivekkundariya/grund/internal/cli/shared"
 	"github.com/vivekkundariya/grund/internal/config"
 	"github.com/vivekkundariya/grund/internal/domain/service"
+	"github.com/vivekkundariya/grund/internal/infrastructure/generator"
+)
+
+var (
+	showOutput      string
+	showShowSecrets bool
 )
 
 var showCmd = &cobra.Command{
@@ -23,23 +29,71 @@ var showCmd = &cobra.Command{
 Without arguments: Shows overall setup (config file, registered services)
 With service name: Shows resolved configuration for that service
 
+Secret values are redacted unless --show-secrets is given.
+
 Examples:
-  grund config show              Show setup overview
-  grund config show user-service Show service configuration`,
+  grund config show                        Show setup overview
+  grund config show user-service           Show service configuration
+  grund config show user-service -o json   Print service configuration as JSON`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := shared.ParseOutputFormat(showOutput)
+		if err != nil {
+			return err
+		}
 		if len(args) == 0 {
-			return showSetupInfo()
+			return showSetupInfo(format)
 		}
-		return showServiceConfig(args[0])
+		return showServiceConfig(args[0], format)
 	},
 }
 
 func init() {
 	Cmd.AddCommand(showCmd)
+	showCmd.Flags().StringVarP(&showOutput, "output", "o", "table", "Output format: table, json or yaml")
+	showCmd.Flags().BoolVar(&showShowSecrets, "show-secrets", false, "Show secret values instead of redacting them")
+}
+
+// setupReport is the --output schema of grund config show
+type setupReport struct {
+	ServicesFile      string                    `json:"services_file" yaml:"services_file"`
+	OrchestrationRoot string                    `json:"orchestration_root" yaml:"orchestration_root"`
+	GlobalConfig      string                    `json:"global_config" yaml:"global_config"`
+	Settings          []settingReport           `json:"settings" yaml:"settings"`
+	Services          []registeredServiceReport `json:"services" yaml:"services"`
+}
+
+type settingReport struct {
+	Key    string `json:"key" yaml:"key"`
+	Value  string `json:"value" yaml:"value"`
+	Source string `json:"source" yaml:"source"` // "config" or "default"
+}
+
+type registeredServiceReport struct {
+	Name string `json:"name" yaml:"name"`
+	Path string `json:"path" yaml:"path"`
 }
 
-func showSetupInfo() error {
+// serviceConfigReport is the --output schema of grund config show <service>
+type serviceConfigReport struct {
+	Name           string            `json:"name" yaml:"name"`
+	Type           string            `json:"type" yaml:"type"`
+	Port           int               `json:"port" yaml:"port"`
+	Dependencies   []string          `json:"dependencies" yaml:"dependencies"`
+	Infrastructure []string          `json:"infrastructure" yaml:"infrastructure"`
+	Environment    map[string]string `json:"environment" yaml:"environment"`
+	Secrets        []string          `json:"secrets" yaml:"secrets"` // declared secret names; found ones are in environment
+}
+
+// settingSource reports whether a setting came from the config file
+func settingSource(configured bool) string {
+	if configured {
+		return "config"
+	}
+	return "default"
+}
+
+func showSetupInfo(format shared.OutputFormat) error {
 	configResolver := shared.ConfigResolver
 	if configResolver == nil {
 		return fmt.Errorf("config not initialized")
@@ -54,83 +108,82 @@ func showSetupInfo() error {
 	// Get global config path
 	globalConfigPath, _ := config.GetGlobalConfigPath()
 
+	gc := configResolver.GlobalConfig
+	report := setupReport{
+		ServicesFile:      servicesPath,
+		OrchestrationRoot: orchestrationRoot,
+		GlobalConfig:      globalConfigPath,
+		Settings: []settingReport{
+			{"docker.compose_command", gc.GetDockerComposeCommand(), settingSource(gc.Docker != nil && gc.Docker.ComposeCommand != "")},
+			{"localstack.endpoint", gc.GetLocalStackEndpoint(), settingSource(gc.LocalStack != nil && gc.LocalStack.Endpoint != "")},
+			{"localstack.region", gc.GetLocalStackRegion(), settingSource(gc.LocalStack != nil && gc.LocalStack.Region != "")},
+		},
+		Services: []registeredServiceReport{},
+	}
+
+	// Get registered services
+	container := shared.Container
+	if container != nil && container.RegistryRepo != nil {
+		if registryRepo, ok := container.RegistryRepo.(ports.ServiceRegistryRepository); ok {
+			if services, err := registryRepo.GetAllServices(); err == nil {
+				// Sort service names
+				names := make([]string, 0, len(services))
+				for name := range services {
+					names = append(names, string(name))
+				}
+				sort.Strings(names)
+
+				for _, name := range names {
+					report.Services = append(report.Services, registeredServiceReport{
+						Name: name,
+						Path: services[service.ServiceName(name)].Path,
+					})
+				}
+			}
+		}
+	}
+
+	if format.Structured() {
+		return shared.WriteStructured(os.Stdout, format, report)
+	}
+
 	fmt.Println()
 	fmt.Println("  Grund Configuration")
 	fmt.Println("  " + strings.Repeat("─", 40))
 	fmt.Println()
-	fmt.Printf("  Services file:      %s\n", servicesPath)
-	fmt.Printf("  Orchestration root: %s\n", orchestrationRoot)
-	fmt.Printf("  Global config:      %s\n", globalConfigPath)
+	fmt.Printf("  Services file:      %s\n", report.ServicesFile)
+	fmt.Printf("  Orchestration root: %s\n", report.OrchestrationRoot)
+	fmt.Printf("  Global config:      %s\n", report.GlobalConfig)
 	fmt.Println()
 
 	// Show global config values
 	fmt.Println("  Global Settings")
 	fmt.Println("  " + strings.Repeat("─", 40))
 
-	gc := configResolver.GlobalConfig
-
 	t := table.NewWriter()
 	t.SetOutputMirror(os.Stdout)
 	t.SetStyle(table.StyleRounded)
 	t.AppendHeader(table.Row{"Setting", "Value", "Source"})
-
-	// Docker compose command
-	dockerCmd := gc.GetDockerComposeCommand()
-	dockerSource := "default"
-	if gc.Docker != nil && gc.Docker.ComposeCommand != "" {
-		dockerSource = "config"
-	}
-	t.AppendRow(table.Row{"docker.compose_command", dockerCmd, dockerSource})
-
-	// LocalStack endpoint
-	lsEndpoint := gc.GetLocalStackEndpoint()
-	lsEndpointSource := "default"
-	if gc.LocalStack != nil && gc.LocalStack.Endpoint != "" {
-		lsEndpointSource = "config"
-	}
-	t.AppendRow(table.Row{"localstack.endpoint", lsEndpoint, lsEndpointSource})
-
-	// LocalStack region
-	lsRegion := gc.GetLocalStackRegion()
-	lsRegionSource := "default"
-	if gc.LocalStack != nil && gc.LocalStack.Region != "" {
-		lsRegionSource = "config"
+	for _, setting := range report.Settings {
+		t.AppendRow(table.Row{setting.Key, setting.Value, setting.Source})
 	}
-	t.AppendRow(table.Row{"localstack.region", lsRegion, lsRegionSource})
-
 	t.Render()
 	fmt.Println()
 
-	// Get registered services
-	container := shared.Container
-	if container != nil && container.RegistryRepo != nil {
-		if registryRepo, ok := container.RegistryRepo.(ports.ServiceRegistryRepository); ok {
-			services, err := registryRepo.GetAllServices()
-			if err == nil && len(services) > 0 {
-				fmt.Println("  Registered Services")
-				fmt.Println("  " + strings.Repeat("─", 40))
-
-				t := table.NewWriter()
-				t.SetOutputMirror(os.Stdout)
-				t.SetStyle(table.StyleRounded)
-				t.AppendHeader(table.Row{"Service", "Path"})
-
-				// Sort service names
-				names := make([]string, 0, len(services))
-				for name := range services {
-					names = append(names, string(name))
-				}
-				sort.Strings(names)
-
-				for _, name := range names {
-					svc := services[service.ServiceName(name)]
-					t.AppendRow(table.Row{name, svc.Path})
-				}
+	if len(report.Services) > 0 {
+		fmt.Println("  Registered Services")
+		fmt.Println("  " + strings.Repeat("─", 40))
 
-				t.Render()
-				fmt.Println()
-			}
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(table.StyleRounded)
+		t.AppendHeader(table.Row{"Service", "Path"})
+		for _, svc := range report.Services {
+			t.AppendRow(table.Row{svc.Name, svc.Path})
 		}
+
+		t.Render()
+		fmt.Println()
 	}
 
 	fmt.Println("  Use 'grund config show <service>' to see service details")
@@ -139,7 +192,7 @@ func showSetupInfo() error {
 	return nil
 }
 
-func showServiceConfig(serviceName string) error {
+func showServiceConfig(serviceName string, format shared.OutputFormat) error {
 	container := shared.Container
 	if container == nil {
 		return fmt.Errorf("container not initialized")
@@ -151,43 +204,76 @@ func showServiceConfig(serviceName string) error {
 		return err
 	}
 
+	report := serviceConfigReport{
+		Name:           cfg.Service.Name,
+		Type:           string(cfg.Service.Type),
+		Port:           cfg.Service.Port.Value(),
+		Dependencies:   append([]string{}, cfg.Dependencies...),
+		Infrastructure: append([]string{}, cfg.Infrastructure...),
+		Environment:    make(map[string]string, len(cfg.Environment)),
+		Secrets:        []string{},
+	}
+	for k, v := range cfg.Environment {
+		report.Environment[k] = v
+	}
+
+	// Secrets are injected alongside the resolved env; redact them unless asked not to
+	secrets, err := generator.NewSecretsLoader().ResolveSecrets(cfg.Service)
+	if err != nil {
+		return fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+	for name := range cfg.Service.Environment.Secrets {
+		report.Secrets = append(report.Secrets, name)
+		if value, ok := secrets[name]; ok {
+			if !showShowSecrets {
+				value = shared.RedactedValue
+			}
+			report.Environment[name] = value
+		}
+	}
+	sort.Strings(report.Secrets)
+
+	if format.Structured() {
+		return shared.WriteStructured(os.Stdout, format, report)
+	}
+
 	// Service info
-	fmt.Printf("\n  Service: %s\n", cfg.Service.Name)
-	fmt.Printf("  Type:    %s\n", cfg.Service.Type)
-	fmt.Printf("  Port:    %d\n", cfg.Service.Port.Value())
+	fmt.Printf("\n  Service: %s\n", report.Name)
+	fmt.Printf("  Type:    %s\n", report.Type)
+	fmt.Printf("  Port:    %d\n", report.Port)
 	fmt.Println()
 
 	// Dependencies
-	if len(cfg.Dependencies) > 0 {
-		fmt.Printf("  Dependencies: %s\n", strings.Join(cfg.Dependencies, ", "))
+	if len(report.Dependencies) > 0 {
+		fmt.Printf("  Dependencies: %s\n", strings.Join(report.Dependencies, ", "))
 	} else {
 		fmt.Println("  Dependencies: none")
 	}
 
 	// Infrastructure
-	if len(cfg.Infrastructure) > 0 {
-		fmt.Printf("  Infrastructure: %s\n", strings.Join(cfg.Infrastructure, ", "))
+	if len(report.Infrastructure) > 0 {
+		fmt.Printf("  Infrastructure: %s\n", strings.Join(report.Infrastructure, ", "))
 	} else {
 		fmt.Println("  Infrastructure: none")
 	}
 	fmt.Println()
 
 	// Environment variables table
-	if len(cfg.Environment) > 0 {
+	if len(report.Environment) > 0 {
 		t := table.NewWriter()
 		t.SetOutputMirror(os.Stdout)
 		t.SetStyle(table.StyleRounded)
 		t.AppendHeader(table.Row{"Environment Variable", "Value"})
 
 		// Sort keys for consistent output
-		keys := make([]string, 0, len(cfg.Environment))
-		for k := range cfg.Environment {
+		keys := make([]string, 0, len(report.Environment))
+		for k := range report.Environment {
 			keys = append(keys, k)
 		}
 		sort.Strings(keys)
 
 		for _, k := range keys {
-			v := cfg.Environment[k]
+			v := report.Environment[k]
 			// Truncate long values
 			if len(v) > 60 {
 				v = v[:57] + "..."
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	"github.com/vivekkundariya/grund/internal/application/commands"
+	"github.com/vivekkundariya/grund/internal/application/queries"
+	"github.com/vivekkundariya/grund/internal/cli/shared"
+	"github.com/vivekkundariya/grund/internal/ui"
+)
+
+var (
+	migrateDown   int
+	migrateStatus bool
+	seedForce     bool
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manage service databases",
+	Long:  `Database operations for services, such as running migrations and loading seed data.`,
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate <service>",
+	Short: "Run a service's Postgres migrations",
+	Long: `Apply pending Postgres migrations declared in the service's grund.yaml.
+
+Migrations are read from requires.infrastructure.postgres.migrations and may use
+golang-migrate (NNN_name.up.sql / .down.sql), goose (-- +goose Up/Down) or plain
+.sql files. Applied versions are tracked in the grund_schema_migrations table.
+
+Examples:
+  grund db migrate user-service            Apply pending migrations
+  grund db migrate user-service --down 1   Revert the last migration
+  grund db migrate user-service --status   Show applied and pending migrations`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if shared.Container == nil {
+			return fmt.Errorf("container not initialized")
+		}
+
+		if migrateStatus {
+			return showMigrationStatus(cmd, args[0])
+		}
+
+		migrateCmd := commands.MigrateCommand{
+			ServiceName: args[0],
+			Down:        migrateDown,
+		}
+		return shared.Container.MigrateCommandHandler.Handle(cmd.Context(), migrateCmd)
+	},
+}
+
+var dbSeedCmd = &cobra.Command{
+	Use:   "seed <service>",
+	Short: "Load a service's seed data",
+	Long: `Load the Postgres and MongoDB seed data declared in the service's grund.yaml.
+
+Seeds are applied automatically by 'grund up' the first time a database is
+provisioned. A marker stored in the database prevents them from running twice;
+use --force to reapply. Forced MongoDB seeds drop each collection before importing.
+
+Examples:
+  grund db seed user-service           Seed if not already seeded
+  grund db seed user-service --force   Reapply the seed data`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if shared.Container == nil {
+			return fmt.Errorf("container not initialized")
+		}
+
+		seedCmd := commands.SeedCommand{
+			ServiceName: args[0],
+			Force:       seedForce,
+		}
+		return shared.Container.SeedCommandHandler.Handle(cmd.Context(), seedCmd)
+	},
+}
+
+func showMigrationStatus(cmd *cobra.Command, serviceName string) error {
+	query := queries.MigrationStatusQuery{ServiceName: serviceName}
+	statuses, err := shared.Container.MigrationStatusQueryHandler.Handle(cmd.Context(), query)
+	if err != nil {
+		return err
+	}
+
+	if len(statuses) == 0 {
+		ui.Infof("No migrations found for %s", serviceName)
+		return nil
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(table.StyleRounded)
+
+	t.AppendHeader(table.Row{"Version", "Name", "Status", "Applied At"})
+
+	for _, s := range statuses {
+		status := text.FgYellow.Sprint("○ pending")
+		appliedAt := "-"
+		if s.Applied {
+			status = text.FgGreen.Sprint("● applied")
+			if !s.AppliedAt.IsZero() {
+				appliedAt = s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+		}
+		t.AppendRow(table.Row{s.Version, s.Name, status, appliedAt})
+	}
+
+	fmt.Println()
+	t.Render()
+	fmt.Println()
+
+	return nil
+}
+
+func init() {
+	dbMigrateCmd.Flags().IntVar(&migrateDown, "down", 0, "Revert the last N applied migrations")
+	dbMigrateCmd.Flags().BoolVar(&migrateStatus, "status", false, "Show migration status instead of migrating")
+	dbMigrateCmd.MarkFlagsMutuallyExclusive("down", "status")
+
+	dbSeedCmd.Flags().BoolVar(&seedForce, "force", false, "Reapply seed data even if already seeded")
+
+	dbCmd.AddCommand(dbMigrateCmd)
+	dbCmd.AddCommand(dbSeedCmd)
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/application/queries"
+	"github.com/vivekkundariya/grund/internal/cli/shared"
+)
+
+// Exit codes of grund doctor
+const (
+	doctorExitFailures = 1 // at least one check failed
+	doctorExitWarnings = 2 // only warnings, with --strict
+)
+
+var (
+	doctorOutput     string
+	doctorStrict     bool
+	doctorConfigOnly bool
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the workspace and this machine for problems",
+	Long: `Loads every registered service and checks the workspace as a whole:
+  - grund.yaml files that fail to load
+  - dependencies missing from services.yaml and dependency cycles
+  - shared infrastructure declared differently by different services
+  - queues, topics and buckets referenced but never declared
+  - services that collide on a port
+  - required secrets that are not set
+
+It then checks this machine: the container engine is reachable, compose is v2,
+the host ports grund publishes are free, there is disk space for images and
+volumes, and the tunnel provider is installed when tunnels are used.
+
+Exit codes: 0 when nothing failed, 1 when a check failed, and 2 when there
+were only warnings and --strict is set.
+
+Examples:
+  grund doctor                     Check the workspace and this machine
+  grund doctor --config-only       Check only the configuration (e.g. in CI)
+  grund doctor --strict -o json    Fail on warnings and print a JSON report`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := shared.ParseOutputFormat(doctorOutput)
+		if err != nil {
+			return err
+		}
+
+		if shared.Container == nil {
+			return fmt.Errorf("container not initialized")
+		}
+
+		report, err := shared.Container.DoctorQueryHandler.Handle(cmd.Context(), queries.DoctorQuery{ConfigOnly: doctorConfigOnly})
+		if err != nil {
+			return err
+		}
+
+		if format.Structured() {
+			if err := shared.WriteStructured(os.Stdout, format, report); err != nil {
+				return err
+			}
+		} else {
+			writeDoctorReport(os.Stdout, report)
+		}
+
+		var exitErr error
+		failures, warnings := report.Count(ports.CheckFail), report.Count(ports.CheckWarn)
+		switch {
+		case failures > 0:
+			exitErr = &doctorError{code: doctorExitFailures, message: fmt.Sprintf("%d check(s) failed", failures)}
+		case doctorStrict && warnings > 0:
+			exitErr = &doctorError{code: doctorExitWarnings, message: fmt.Sprintf("%d warning(s)", warnings)}
+		}
+		if exitErr != nil {
+			// The report already explains every problem; only the exit code is left to set
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+		}
+		return exitErr
+	},
+}
+
+func init() {
+	doctorCmd.Flags().StringVarP(&doctorOutput, "output", "o", "table", "Output format: table, json or yaml")
+	doctorCmd.Flags().BoolVar(&doctorStrict, "strict", false, "Exit with code 2 when there are warnings")
+	doctorCmd.Flags().BoolVar(&doctorConfigOnly, "config-only", false, "Skip checks of this machine (engine, ports, disk, tunnel)")
+}
+
+// doctorError carries grund doctor's exit code to main
+type doctorError struct {
+	code    int
+	message string
+}
+
+func (e *doctorError) Error() string { return e.message }
+
+func (e *doctorError) ExitCode() int { return e.code }
+
+// writeDoctorReport prints checks grouped by category, then a summary
+func writeDoctorReport(w io.Writer, report *queries.DoctorReport) {
+	category := ""
+	for _, c := range report.Checks {
+		if c.Category != category {
+			if category != "" {
+				fmt.Fprintln(w)
+			}
+			category = c.Category
+			fmt.Fprintln(w, text.Bold.Sprint(category))
+		}
+
+		var icon string
+		var color text.Color
+		switch c.Status {
+		case ports.CheckOK:
+			icon, color = "✓", text.FgGreen
+		case ports.CheckWarn:
+			icon, color = "!", text.FgYellow
+		default:
+			icon, color = "✗", text.FgRed
+		}
+		fmt.Fprintf(w, "  %s %s: %s\n", color.Sprint(icon), c.Name, c.Message)
+		if c.Hint != "" && c.Status != ports.CheckOK {
+			fmt.Fprintf(w, "      → %s\n", c.Hint)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d ok, %d warning(s), %d failed\n",
+		report.Count(ports.CheckOK), report.Count(ports.CheckWarn), report.Count(ports.CheckFail))
+}
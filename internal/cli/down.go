@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 	"github.com/vivekkundariya/grund/internal/application/commands"
 	"github.com/vivekkundariya/grund/internal/application/wiring"
@@ -10,14 +12,35 @@ import (
 	"github.com/vivekkundariya/grund/internal/ui"
 )
 
+var (
+	downWithDeps bool
+	downInfra    bool
+)
+
 var downCmd = &cobra.Command{
-	Use:   "down",
-	Short: "Stop all running services",
+	Use:   "down [services...]",
+	Short: "Stop running services",
 	Long: `Stop all services and infrastructure that were started by grund.
 
 If run from a project directory (with services.yaml), stops that project.
-If run without a valid project context, stops all projects in ~/.grund/tmp/.`,
+If run without a valid project context, stops everything in the environment's
+generated files (~/.grund/tmp/ for the default environment).
+
+With service names, only those services' containers are stopped and removed,
+and their generated compose files deleted. Running services that depend on them
+are left running, with a warning.
+
+Examples:
+  grund down                                  Stop everything
+  grund down payment-service                  Stop only payment-service
+  grund down order-service --with-deps        Also stop dependencies nothing else uses
+  grund down order-service --with-deps --infra
+                                              ...and infrastructure nothing else requires`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && (downWithDeps || downInfra) {
+			return fmt.Errorf("--with-deps and --infra need service names; grund down without names stops everything")
+		}
+
 		// Try to initialize with current directory context
 		resolver, err := config.NewConfigResolver(configFile)
 		if err == nil {
@@ -25,16 +48,30 @@ If run without a valid project context, stops all projects in ~/.grund/tmp/.`,
 			if err == nil {
 				// Valid project context - stop this project
 				ui.Debug("Using config: %s", servicesPath)
-				shared.Container, err = wiring.NewContainerWithConfig(orchestrationRoot, servicesPath, resolver)
+				shared.Container, err = wiring.NewContainerWithConfig(orchestrationRoot, servicesPath, resolver, shared.Environment, shared.Runtime)
 				if err == nil {
-					downCmd := commands.DownCommand{}
+					downCmd := commands.DownCommand{
+						ServiceNames: args,
+						WithDeps:     downWithDeps,
+						Infra:        downInfra,
+					}
 					return shared.Container.DownCommandHandler.Handle(cmd.Context(), downCmd)
 				}
 			}
 		}
 
+		// Selected services need the project's configs to work out dependencies
+		if len(args) > 0 {
+			return fmt.Errorf("no project context found; run grund down <service> from a project with services.yaml")
+		}
+
 		// No valid project context - stop all projects
 		ui.Infof("No project context found, stopping all projects...")
-		return docker.StopAllProjects(cmd.Context())
+		return docker.StopAllProjects(cmd.Context(), shared.Runtime, shared.Environment, false)
 	},
 }
+
+func init() {
+	downCmd.Flags().BoolVar(&downWithDeps, "with-deps", false, "Also stop dependencies no other running service needs")
+	downCmd.Flags().BoolVar(&downInfra, "infra", false, "Also stop infrastructure no remaining service requires")
+}
@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	"github.com/vivekkundariya/grund/internal/application/queries"
+	"github.com/vivekkundariya/grund/internal/cli/shared"
+	"github.com/vivekkundariya/grund/internal/config"
+	"github.com/vivekkundariya/grund/internal/infrastructure/docker"
+	"github.com/vivekkundariya/grund/internal/ui"
+)
+
+var (
+	envFormat string
+	envHost   bool
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env <service>",
+	Short: "Export a service's resolved environment, or manage isolated environments",
+	Long: `Print the environment a service gets in its container: static variables,
+resolved env_refs, LocalStack credentials and secrets. With --host, hostnames and
+ports are rewritten to localhost and the published host ports, so IDEs, direnv
+and host processes can reach the running stack.
+
+Formats:
+  dotenv    KEY=value (default)
+  json      a flat JSON object
+  shell     export KEY='value', for eval "$(grund env <service> --format shell --host)"
+  direnv    an .envrc of export lines
+
+The list and rm subcommands manage isolated grund environments.
+
+Every command runs in an environment, selected with --env <name> or GRUND_ENV.
+Each environment has its own compose project, network, container names, volumes
+and generated files, and publishes host ports shifted by its port offset, so
+several stacks (e.g. two worktrees, or dev and test) can run side by side.
+Environments are created by the first 'grund --env <name> up'.
+
+Examples:
+  grund env user-service --host > .env
+  grund env user-service --format direnv --host > .envrc
+  grund --env review up user-service
+  GRUND_ENV=test grund status
+  grund env list
+  grund env rm review`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEnvExport,
+}
+
+var envExportCmd = &cobra.Command{
+	Use:   "export <service>",
+	Short: "Print a service's resolved environment (same as grund env <service>)",
+	Long: `Print a service's resolved environment, exactly as 'grund env <service>' does.
+
+Examples:
+  grund env export user-service --host > .env`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEnvExport,
+}
+
+// runEnvExport prints the environment of the service in args[0]
+func runEnvExport(cmd *cobra.Command, args []string) error {
+	format, err := shared.ParseEnvFormat(envFormat)
+	if err != nil {
+		return err
+	}
+
+	env, err := resolveServiceEnv(args[0], envHost)
+	if err != nil {
+		return err
+	}
+
+	if format == shared.EnvDotenv || format == shared.EnvDirenv {
+		regenerate := fmt.Sprintf("grund env %s --format %s", args[0], format)
+		if envHost {
+			regenerate += " --host"
+		}
+		fmt.Printf("# AUTO-GENERATED by grund - DO NOT EDIT\n# Regenerate with: %s\n\n", regenerate)
+	}
+	return shared.WriteEnv(os.Stdout, format, env)
+}
+
+// resolveServiceEnv resolves a service's environment, for its container or for the host
+func resolveServiceEnv(name string, host bool) (map[string]string, error) {
+	if shared.Container == nil {
+		return nil, fmt.Errorf("container not initialized")
+	}
+	return shared.Container.EnvQueryHandler.Handle(queries.EnvQuery{ServiceName: name, Host: host})
+}
+
+var envListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List environments",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		envs, err := config.ListEnvironments()
+		if err != nil {
+			return err
+		}
+
+		t := table.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(table.StyleRounded)
+
+		t.AppendHeader(table.Row{"Environment", "Project", "Port Offset", "Containers"})
+
+		for _, env := range envs {
+			name := env.Name
+			if shared.Environment != nil && env.Name == shared.Environment.Name {
+				name = text.FgCyan.Sprint(name + " *")
+			}
+
+			containers := "-"
+			if running, err := docker.CountRunningContainers(cmd.Context(), shared.Runtime, env); err == nil && running > 0 {
+				containers = text.FgGreen.Sprintf("● %d running", running)
+			}
+
+			t.AppendRow(table.Row{name, env.ProjectName(), fmt.Sprintf("+%d", env.PortOffset), containers})
+		}
+
+		fmt.Println()
+		t.Render()
+		fmt.Println()
+
+		return nil
+	},
+}
+
+var envRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Remove an environment, its containers and volumes",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if name == config.DefaultEnvironmentName {
+			return fmt.Errorf("the default environment can't be removed; use 'grund reset -v' to clean it up")
+		}
+
+		envs, err := config.ListEnvironments()
+		if err != nil {
+			return err
+		}
+		var env *config.Environment
+		for _, e := range envs {
+			if e.Name == name {
+				env = e
+			}
+		}
+		if env == nil {
+			return fmt.Errorf("environment %s not found", name)
+		}
+
+		ui.Step("Removing environment %s...", name)
+		if err := docker.StopAllProjects(cmd.Context(), shared.Runtime, env, true); err != nil {
+			return err
+		}
+		if err := config.RemoveEnvironment(name); err != nil {
+			return err
+		}
+
+		ui.Successf("Environment %s removed", name)
+		return nil
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{envCmd, envExportCmd} {
+		cmd.Flags().StringVarP(&envFormat, "format", "f", "dotenv", "Output format: dotenv, json, shell or direnv")
+		cmd.Flags().BoolVar(&envHost, "host", false, "Rewrite hostnames and ports for a process on the host")
+	}
+
+	envCmd.AddCommand(envExportCmd)
+	envCmd.AddCommand(envListCmd)
+	envCmd.AddCommand(envRmCmd)
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vivekkundariya/grund/internal/application/queries"
+	"github.com/vivekkundariya/grund/internal/cli/shared"
+	"github.com/vivekkundariya/grund/internal/ui"
+)
+
+var graphOutput string
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [services...]",
+	Short: "Show the service dependency graph",
+	Long: `Show how services depend on each other and on infrastructure.
+
+Without arguments the graph covers every registered service; with service names
+it covers those services and everything they depend on. Each service is annotated
+with its infrastructure (databases, queues, topics and their subscriptions), and
+dependency cycles are highlighted.
+
+Formats:
+  tree      ASCII tree (default)
+  dot       Graphviz DOT, e.g. grund graph -o dot | dot -Tsvg > graph.svg
+  mermaid   Mermaid flowchart for Markdown design docs
+
+Examples:
+  grund graph
+  grund graph order-service
+  grund graph -o mermaid > docs/architecture.mmd`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if shared.Container == nil {
+			return fmt.Errorf("container not initialized")
+		}
+
+		result, err := shared.Container.GraphQueryHandler.Handle(queries.GraphQuery{ServiceNames: args})
+		if err != nil {
+			return err
+		}
+
+		switch graphOutput {
+		case "dot":
+			fmt.Print(result.Graph.RenderDOT())
+			return nil
+		case "mermaid":
+			fmt.Print(result.Graph.RenderMermaid())
+			return nil
+		case "tree":
+		default:
+			return fmt.Errorf("invalid output format %q (expected tree, dot or mermaid)", graphOutput)
+		}
+
+		if len(result.Graph.Names()) == 0 {
+			ui.Infof("No services registered")
+			return nil
+		}
+
+		fmt.Println()
+		fmt.Print(result.Graph.RenderTree(result.Roots))
+		fmt.Println()
+
+		for _, cycle := range result.Cycles {
+			names := make([]string, len(cycle))
+			for i, name := range cycle {
+				names[i] = name.String()
+			}
+			ui.Warnf("Dependency cycle: %s", strings.Join(names, ", "))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	graphCmd.Flags().StringVarP(&graphOutput, "output", "o", "tree", "Output format: tree, dot or mermaid")
+}
@@ -1,68 +1,146 @@
 package cli
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
-	"strconv"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/spf13/cobra"
-	"github.com/vivekkundariya/grund/internal/infrastructure/docker"
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/application/queries"
+	"github.com/vivekkundariya/grund/internal/cli/shared"
+	"github.com/vivekkundariya/grund/internal/ui"
 )
 
 var (
 	logsFollow bool
 	logsTail   int
+	logsSince  string
+	logsGrep   string
+	logsLevel  string
+	logsJSON   bool
 )
 
 var logsCmd = &cobra.Command{
 	Use:   "logs [services...]",
 	Short: "View aggregated or per-service logs",
-	Long:  `View logs from all services or specific services. Multiple services can be specified.`,
-	Args:  cobra.ArbitraryArgs,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		// Discover compose files
-		fileSet, err := docker.DiscoverComposeFiles()
-		if err != nil {
-			return fmt.Errorf("failed to discover compose files: %w", err)
-		}
+	Long: `View logs from all services or specific services. Multiple services can be specified.
 
-		allPaths := fileSet.AllPaths()
-		if len(allPaths) == 0 {
-			return fmt.Errorf("no services are running. Run 'grund up <service>' first")
-		}
+Lines are prefixed with the service name and timestamp. --level filters on the
+level field of JSON log lines; lines without one are always shown.
 
-		// Build docker compose logs command with project name and all compose files
-		dockerArgs := []string{"compose", "-p", docker.ProjectName}
-		for _, path := range allPaths {
-			dockerArgs = append(dockerArgs, "-f", path)
+Examples:
+  grund logs user-service -f
+  grund logs --since 10m --grep "timeout|refused"
+  grund logs payment-service --level warn --json`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if shared.Container == nil {
+			return fmt.Errorf("container not initialized")
 		}
-		dockerArgs = append(dockerArgs, "logs")
 
-		if logsFollow {
-			dockerArgs = append(dockerArgs, "-f")
-		}
+		// Stop following cleanly on Ctrl+C
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
 
-		if logsTail > 0 {
-			dockerArgs = append(dockerArgs, "--tail", strconv.Itoa(logsTail))
+		query := queries.LogsQuery{
+			ServiceNames: args,
+			Options: ports.LogOptions{
+				Follow: logsFollow,
+				Tail:   logsTail,
+				Since:  logsSince,
+				Grep:   logsGrep,
+				Level:  logsLevel,
+			},
 		}
 
-		// Add service names if specified
-		if len(args) > 0 {
-			dockerArgs = append(dockerArgs, args...)
+		stream, err := shared.Container.LogsQueryHandler.Handle(ctx, query)
+		if err != nil {
+			return err
 		}
+		defer stream.Close()
 
-		// Execute docker compose logs with stdout/stderr connected
-		dockerCmd := exec.CommandContext(cmd.Context(), "docker", dockerArgs...)
-		dockerCmd.Stdout = os.Stdout
-		dockerCmd.Stderr = os.Stderr
-		dockerCmd.Stdin = os.Stdin
-
-		return dockerCmd.Run()
+		printer := newLogPrinter(os.Stdout, args, logsJSON)
+		for {
+			entry, err := stream.Next()
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := printer.print(entry); err != nil {
+				return err
+			}
+		}
 	},
 }
 
 func init() {
 	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Follow log output")
-	logsCmd.Flags().IntVar(&logsTail, "tail", 100, "Number of lines to show from the end (default 100)")
+	logsCmd.Flags().IntVar(&logsTail, "tail", 100, "Number of lines to show from the end of each service's logs")
+	logsCmd.Flags().StringVar(&logsSince, "since", "", "Show logs since a timestamp or relative duration (e.g. 10m)")
+	logsCmd.Flags().StringVar(&logsGrep, "grep", "", "Only show lines matching a regular expression")
+	logsCmd.Flags().StringVar(&logsLevel, "level", "", "Minimum level of JSON log lines (debug, info, warn, error)")
+	logsCmd.Flags().BoolVar(&logsJSON, "json", false, "Print one JSON object per line")
+}
+
+// logPrefixColors are assigned to services in order of first appearance
+var logPrefixColors = []string{ui.Cyan, ui.Yellow, ui.Green, ui.Purple, ui.Blue, ui.Red}
+
+// logPrinter writes entries with a colored, aligned service prefix, or as JSON lines
+type logPrinter struct {
+	out    io.Writer
+	json   bool
+	width  int
+	colors map[string]string
+}
+
+// newLogPrinter sizes the prefix column for the requested services
+func newLogPrinter(out io.Writer, services []string, asJSON bool) *logPrinter {
+	p := &logPrinter{out: out, json: asJSON, colors: make(map[string]string)}
+	for _, name := range services {
+		p.color(name)
+	}
+	return p
+}
+
+// color returns the service's prefix color, widening the column for new services
+func (p *logPrinter) color(name string) string {
+	if c, ok := p.colors[name]; ok {
+		return c
+	}
+	c := logPrefixColors[len(p.colors)%len(logPrefixColors)]
+	p.colors[name] = c
+	if len(name) > p.width {
+		p.width = len(name)
+	}
+	return c
+}
+
+func (p *logPrinter) print(entry ports.LogEntry) error {
+	if p.json {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.out, string(data))
+		return err
+	}
+
+	c := p.color(entry.Service)
+	prefix := ui.Colorize(entry.Service+strings.Repeat(" ", p.width-len(entry.Service))+" |", c)
+
+	timestamp := ""
+	if !entry.Timestamp.IsZero() {
+		timestamp = ui.Colorize(entry.Timestamp.Local().Format("15:04:05.000"), ui.Purple) + " "
+	}
+
+	_, err := fmt.Fprintf(p.out, "%s %s%s\n", prefix, timestamp, entry.Message)
+	return err
 }
@@ -29,6 +29,19 @@ func Text(title string, defaultVal string) (string, error) {
 	return value, nil
 }
 
+// Password prompts for input without echoing it
+func Password(title string) (string, error) {
+	var value string
+
+	err := huh.NewInput().
+		Title(title).
+		EchoMode(huh.EchoModePassword).
+		Value(&value).
+		Run()
+
+	return value, err
+}
+
 // Int prompts for integer input with a default value
 func Int(title string, defaultVal int) (int, error) {
 	value := strconv.Itoa(defaultVal)
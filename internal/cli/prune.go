@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vivekkundariya/grund/internal/application/commands"
+	"github.com/vivekkundariya/grund/internal/cli/shared"
+)
+
+var pruneDryRun bool
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove leftovers of services that are no longer registered",
+	Long: `Remove what grund generated or created for services that were renamed or
+removed from services.yaml:
+  - compose directories in ~/.grund/tmp (or the environment's tmp directory)
+  - containers of the environment's compose project
+  - volumes of the project that no remaining compose file declares
+
+Infrastructure (postgres, mongodb, redis, localstack) and its volumes are kept.
+
+Examples:
+  grund prune --dry-run   List what would be removed
+  grund prune             Remove it`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if shared.Container == nil {
+			return fmt.Errorf("container not initialized")
+		}
+		return shared.Container.PruneCommandHandler.Handle(cmd.Context(), commands.PruneCommand{DryRun: pruneDryRun})
+	},
+}
+
+func init() {
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "List what would be removed without removing it")
+}
@@ -2,10 +2,11 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 
 	"github.com/spf13/cobra"
+	"github.com/vivekkundariya/grund/internal/cli/shared"
 	"github.com/vivekkundariya/grund/internal/infrastructure/docker"
 	"github.com/vivekkundariya/grund/internal/ui"
 )
@@ -26,7 +27,7 @@ Examples:
   grund reset -v --images  # Stop, remove volumes and images (full cleanup)`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Discover compose files
-		fileSet, err := docker.DiscoverComposeFiles()
+		fileSet, err := docker.DiscoverComposeFiles(shared.Environment)
 		if err != nil {
 			return fmt.Errorf("failed to discover compose files: %w", err)
 		}
@@ -37,12 +38,8 @@ Examples:
 			return nil
 		}
 
-		// Build docker compose down command with project name and all compose files
-		dockerArgs := []string{"compose", "-p", docker.ProjectName}
-		for _, path := range allPaths {
-			dockerArgs = append(dockerArgs, "-f", path)
-		}
-		dockerArgs = append(dockerArgs, "down")
+		// Build compose down command with project name and all compose files
+		dockerArgs := []string{"down"}
 
 		if resetVolumes {
 			dockerArgs = append(dockerArgs, "-v")
@@ -56,12 +53,15 @@ Examples:
 			ui.SubStep("Also removing locally built images")
 		}
 
-		// Execute docker compose down
-		dockerCmd := exec.CommandContext(cmd.Context(), "docker", dockerArgs...)
-		dockerCmd.Stdout = os.Stdout
-		dockerCmd.Stderr = os.Stderr
+		// Execute compose down, showing its progress
+		composeCmd := shared.Runtime.ComposeCommand(shared.Environment.ProjectName(), allPaths, dockerArgs...)
+		output, err := shared.Runtime.Stream(cmd.Context(), composeCmd)
+		if err != nil {
+			return fmt.Errorf("failed to reset: %w", err)
+		}
+		defer output.Close()
 
-		if err := dockerCmd.Run(); err != nil {
+		if _, err := io.Copy(os.Stdout, output); err != nil {
 			return fmt.Errorf("failed to reset: %w", err)
 		}
 
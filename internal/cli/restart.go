@@ -8,13 +8,27 @@ import (
 	"github.com/vivekkundariya/grund/internal/cli/shared"
 )
 
-var restartBuild bool
+var (
+	restartBuild  bool
+	restartNoWait bool
+)
 
 var restartCmd = &cobra.Command{
 	Use:   "restart [services...]",
 	Short: "Restart specific service(s)",
-	Long:  `Restart one or more services while keeping infrastructure running.`,
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Restart one or more services while keeping infrastructure running.
+
+Each service's grund.yaml is re-read and its compose file regenerated, so env,
+env_refs and secret changes are picked up; the environment variables that
+changed are printed (secret values are masked). The container is then recreated
+and waited on until healthy. Its image is rebuilt when the build context changed,
+or always with --build.
+
+Examples:
+  grund restart user-service
+  grund restart user-service --build
+  grund restart user-service --no-wait`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if shared.Container == nil {
 			return fmt.Errorf("container not initialized")
@@ -23,11 +37,13 @@ var restartCmd = &cobra.Command{
 		restartCmd := commands.RestartCommand{
 			ServiceNames: args,
 			Build:        restartBuild,
+			NoWait:       restartNoWait,
 		}
 		return shared.Container.RestartCommandHandler.Handle(cmd.Context(), restartCmd)
 	},
 }
 
 func init() {
-	restartCmd.Flags().BoolVar(&restartBuild, "build", false, "Rebuild containers before restarting")
+	restartCmd.Flags().BoolVar(&restartBuild, "build", false, "Rebuild images even when their build context is unchanged")
+	restartCmd.Flags().BoolVar(&restartNoWait, "no-wait", false, "Return once containers are recreated, without waiting for health checks")
 }
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/vivekkundariya/grund/internal/application/wiring"
@@ -9,6 +10,7 @@ import (
 	"github.com/vivekkundariya/grund/internal/cli/service"
 	"github.com/vivekkundariya/grund/internal/cli/shared"
 	"github.com/vivekkundariya/grund/internal/config"
+	"github.com/vivekkundariya/grund/internal/infrastructure/docker"
 	"github.com/vivekkundariya/grund/internal/ui"
 )
 
@@ -16,6 +18,7 @@ var (
 	// CLI flags
 	configFile string
 	verbose    bool
+	envName    string
 )
 
 var rootCmd = &cobra.Command{
@@ -33,6 +36,10 @@ Service Management:
   grund service init          Initialize new service
   grund service add queue X   Add SQS queue
   grund service validate      Validate configuration
+  grund graph                 Show the dependency graph
+  grund doctor                Check the workspace for problems
+  grund env <service>         Export a service's resolved environment
+  grund run <service> -- cmd  Run a host command with that environment
 
 Configuration:
   grund config init           Set up global config
@@ -54,20 +61,52 @@ Documentation: https://github.com/vivekkundariya/grund`,
 			return nil
 		}
 
+		// Resolve the environment first; even commands without a project are scoped to it
+		// Only up creates an environment, so a mistyped --env elsewhere doesn't leave one behind.
+		var err error
+		create := cmd.Name() == "up" && cmd.Parent() == cmd.Root()
+		shared.Environment, err = config.LoadEnvironment(config.EnvironmentName(envName), create)
+		if err != nil {
+			return err
+		}
+		if !shared.Environment.IsDefault() {
+			ui.Debug("Using environment: %s", shared.Environment.Name)
+		}
+
+		// Every compose and engine command runs through the configured runtime
+		containerRuntime, err := config.DetectRuntime(config.GetDockerComposeCommand())
+		if err != nil {
+			return err
+		}
+		shared.Runtime = docker.NewRuntime(containerRuntime, docker.NewExecRunner())
+		ui.Debug("Container runtime: %s (%s)", containerRuntime.Name, strings.Join(containerRuntime.Compose, " "))
+
+		// Managing environments doesn't need a project; exporting a service's env (grund env <service>) does
+		if cmd.Parent() != nil && cmd.Parent().Name() == "env" && cmd.Name() != "export" {
+			return nil
+		}
+
+		// Editing and reading individual secrets only needs the global config
+		if cmd.Parent() != nil && cmd.Parent().Name() == "secrets" && (cmd.Name() == "set" || cmd.Name() == "get" || cmd.Name() == "unset") {
+			return nil
+		}
+
 		// Skip for commands that don't need existing services.yaml
 		// - service init: creates new grund.yaml
 		// - config init: creates global config
 		// - down: can work without project context
-		// - validate: only needs local grund.yaml
+		// - validate: only needs local grund.yaml, unless validating --all
 		skipInit := []string{"init", "down", "validate"}
 		for _, skip := range skipInit {
 			if cmd.Name() == skip {
+				if all := cmd.Flags().Lookup("all"); skip == "validate" && all != nil && all.Value.String() == "true" {
+					break
+				}
 				return nil
 			}
 		}
 
 		// Initialize config resolver
-		var err error
 		shared.ConfigResolver, err = config.NewConfigResolver(configFile)
 		if err != nil {
 			return fmt.Errorf("failed to initialize config: %w", err)
@@ -83,7 +122,7 @@ Documentation: https://github.com/vivekkundariya/grund`,
 		ui.Debug("Orchestration root: %s", orchestrationRoot)
 
 		// Initialize dependency injection container
-		shared.Container, err = wiring.NewContainerWithConfig(orchestrationRoot, servicesPath, shared.ConfigResolver)
+		shared.Container, err = wiring.NewContainerWithConfig(orchestrationRoot, servicesPath, shared.ConfigResolver, shared.Environment, shared.Runtime)
 		if err != nil {
 			return fmt.Errorf("failed to initialize: %w", err)
 		}
@@ -103,6 +142,8 @@ func init() {
 		"Path to services registry file (default: auto-detect services.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false,
 		"Enable verbose output")
+	rootCmd.PersistentFlags().StringVar(&envName, "env", "",
+		"Isolated environment to use (default: $GRUND_ENV or \"default\")")
 
 	// Initialization
 	rootCmd.AddCommand(initCmd)
@@ -114,15 +155,25 @@ func init() {
 	rootCmd.AddCommand(logsCmd)
 	rootCmd.AddCommand(restartCmd)
 	rootCmd.AddCommand(resetCmd)
+	rootCmd.AddCommand(pruneCmd)
+	rootCmd.AddCommand(runCmd)
 
 	// Service management
 	rootCmd.AddCommand(service.Cmd)
+	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(doctorCmd)
+
+	// Database management
+	rootCmd.AddCommand(dbCmd)
 
 	// Configuration management
 	rootCmd.AddCommand(configcmd.Cmd)
 
 	// Secrets management
 	rootCmd.AddCommand(secretsCmd)
+
+	// Environment management
+	rootCmd.AddCommand(envCmd)
 }
 
 // GetConfigResolver returns the current config resolver (for use by subcommands)
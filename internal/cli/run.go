@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+	"github.com/vivekkundariya/grund/internal/ui"
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run <service> -- <command> [args...]",
+	Short: "Run a host command with a service's resolved environment",
+	Long: `Run a command on the host with a service's environment injected.
+
+The environment is resolved as with 'grund env <service> --host': infrastructure
+and peers are reached through localhost and their published ports, so tests and
+one-off scripts run against the stack started with 'grund up'. Variables from
+grund override those already set in the shell.
+
+The command's exit code is returned.
+
+Examples:
+  grund run order-service -- go test ./...
+  grund run order-service -- npm run migrate
+  grund run order-service -- sh -c 'psql "$DATABASE_URL"'`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if cmd.ArgsLenAtDash() != 1 || len(args) < 2 {
+			return fmt.Errorf("usage: grund run <service> -- <command> [args...]")
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		env, err := resolveServiceEnv(args[0], true)
+		if err != nil {
+			return err
+		}
+
+		child := exec.Command(args[1], args[2:]...)
+		child.Stdin = os.Stdin
+		child.Stdout = os.Stdout
+		child.Stderr = os.Stderr
+		child.Env = os.Environ()
+		for k, v := range env {
+			child.Env = append(child.Env, k+"="+v)
+		}
+
+		ui.Debug("Running %v with %d variables from %s", args[1:], len(env), args[0])
+
+		// The child shares the terminal, so it receives Ctrl+C itself; grund just waits for it
+		signal.Ignore(os.Interrupt)
+		defer signal.Reset(os.Interrupt)
+
+		if err := child.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				// The command already reported its own failure
+				cmd.SilenceErrors = true
+				cmd.SilenceUsage = true
+			}
+			return err
+		}
+		return nil
+	},
+}
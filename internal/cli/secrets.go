@@ -1,8 +1,8 @@
 package cli
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -12,9 +12,11 @@ import (
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/spf13/cobra"
 	"github.com/vivekkundariya/grund/internal/application/queries"
+	"github.com/vivekkundariya/grund/internal/cli/prompts"
 	"github.com/vivekkundariya/grund/internal/cli/shared"
 	"github.com/vivekkundariya/grund/internal/domain/service"
 	"github.com/vivekkundariya/grund/internal/infrastructure/generator"
+	"github.com/vivekkundariya/grund/internal/infrastructure/secrets"
 	"github.com/vivekkundariya/grund/internal/ui"
 )
 
@@ -23,12 +25,16 @@ var secretsCmd = &cobra.Command{
 	Short: "Manage service secrets",
 	Long: `Manage secrets required by services.
 
-Secrets are stored in ~/.grund/secrets.env and are injected into containers
-when running services with 'grund up'.
+Secrets are looked up in the backends configured under secrets.backends in
+~/.grund/config.yaml (sops or age files, pass, or any command such as 'op read'),
+tried in order. Without configuration they come from ~/.grund/secrets.env, then
+the shell environment. They are injected into containers by 'grund up'.
 
 Examples:
   grund secrets list user-service    Show secrets required by service
-  grund secrets init user-service    Generate secrets.env template`,
+  grund secrets init user-service    Generate secrets.env template
+  grund secrets set API_KEY          Set a value without an editor
+  grund secrets get API_KEY          Show where a value comes from`,
 }
 
 var secretsListCmd = &cobra.Command{
@@ -36,28 +42,122 @@ var secretsListCmd = &cobra.Command{
 	Short: "List secrets required by services",
 	Long: `Show all secrets required by the specified services and their dependencies.
 
-Indicates which secrets are found, missing, or optional.`,
+Indicates which secrets are found and which backend supplied them, and which
+are missing or optional. Values are never shown.
+Exits non-zero when a required secret is missing, in every output format.
+
+Examples:
+  grund secrets list user-service
+  grund secrets list user-service -o json`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runSecretsList,
 }
 
+var secretsListOutput string
+
+// secretsReport is the --output schema of grund secrets list
+type secretsReport struct {
+	Services []string       `json:"services" yaml:"services"`
+	Backends []string       `json:"backends" yaml:"backends"`
+	Secrets  []secretReport `json:"secrets" yaml:"secrets"`
+}
+
+type secretReport struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description" yaml:"description"`
+	Required    bool     `json:"required" yaml:"required"`
+	Found       bool     `json:"found" yaml:"found"`
+	Source      string   `json:"source" yaml:"source"` // backend name, or "" when missing
+	MissingFor  []string `json:"missing_for,omitempty" yaml:"missing_for,omitempty"`
+}
+
 var secretsInitCmd = &cobra.Command{
 	Use:   "init [service...]",
 	Short: "Generate secrets.env template",
 	Long: `Generate ~/.grund/secrets.env with placeholders for missing secrets.
 
 If the file already exists, only missing secrets are appended.
-Existing values are preserved.`,
+Existing values are preserved. Requires a plaintext file backend, which is
+configured by default.`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runSecretsInit,
 }
 
+var secretsSetCmd = &cobra.Command{
+	Use:   "set NAME",
+	Short: "Set a secret in the secrets file",
+	Long: `Set a secret's value in ~/.grund/secrets.env, or in
+~/.grund/secrets.d/<service>.env with --service.
+
+The value is read from a hidden prompt, or from stdin with --from-stdin (one
+trailing newline is dropped). The file is created with mode 0600 and edited
+atomically under a lock, so concurrent edits don't overwrite each other.
+
+Examples:
+  grund secrets set STRIPE_SECRET_KEY
+  grund secrets set STRIPE_SECRET_KEY --service order-service
+  op read op://dev/stripe/key | grund secrets set STRIPE_SECRET_KEY --from-stdin`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretsSet,
+}
+
+var secretsGetCmd = &cobra.Command{
+	Use:   "get NAME",
+	Short: "Show a secret and the backend that supplies it",
+	Long: `Look up a secret in the configured backends, as 'grund up' would.
+
+The value is masked unless --show is given, in which case only the value is
+printed, for use in scripts.
+
+Examples:
+  grund secrets get STRIPE_SECRET_KEY
+  grund secrets get STRIPE_SECRET_KEY --service order-service --show`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretsGet,
+}
+
+var secretsUnsetCmd = &cobra.Command{
+	Use:   "unset NAME",
+	Short: "Remove a secret from the secrets file",
+	Long: `Remove a secret from ~/.grund/secrets.env, or from
+~/.grund/secrets.d/<service>.env with --service.
+
+Examples:
+  grund secrets unset STRIPE_SECRET_KEY
+  grund secrets unset STRIPE_SECRET_KEY --service order-service`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretsUnset,
+}
+
+var (
+	secretsService   string
+	secretsFromStdin bool
+	secretsShow      bool
+)
+
 func init() {
 	secretsCmd.AddCommand(secretsListCmd)
 	secretsCmd.AddCommand(secretsInitCmd)
+	secretsCmd.AddCommand(secretsSetCmd)
+	secretsCmd.AddCommand(secretsGetCmd)
+	secretsCmd.AddCommand(secretsUnsetCmd)
+
+	secretsListCmd.Flags().StringVarP(&secretsListOutput, "output", "o", "table", "Output format: table, json or yaml")
+
+	for _, c := range []*cobra.Command{secretsSetCmd, secretsUnsetCmd} {
+		c.Flags().StringVar(&secretsService, "service", "", "Edit the service's own secrets file, secrets.d/<service>.env")
+	}
+	secretsGetCmd.Flags().StringVar(&secretsService, "service", "", "Look up the value this service gets, including secrets.d/<service>.env")
+	secretsSetCmd.Flags().BoolVar(&secretsFromStdin, "from-stdin", false, "Read the value from stdin instead of prompting")
+	secretsGetCmd.Flags().BoolVar(&secretsShow, "show", false, "Print the value instead of masking it")
 }
 
 func runSecretsList(cmd *cobra.Command, args []string) error {
+	format, err := shared.ParseOutputFormat(secretsListOutput)
+	if err != nil {
+		return err
+	}
+
 	if shared.Container == nil {
 		return fmt.Errorf("container not initialized")
 	}
@@ -68,7 +168,7 @@ func runSecretsList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if len(services) == 0 {
+	if len(services) == 0 && !format.Structured() {
 		ui.Infof("No services found")
 		return nil
 	}
@@ -79,10 +179,9 @@ func runSecretsList(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to validate secrets: %w", err)
 	}
-
-	if len(statuses) == 0 {
-		ui.Infof("No secrets declared for these services")
-		return nil
+	backends, err := loader.Backends()
+	if err != nil {
+		return err
 	}
 
 	// Sort statuses by name for consistent output
@@ -90,6 +189,40 @@ func runSecretsList(cmd *cobra.Command, args []string) error {
 		return statuses[i].Name < statuses[j].Name
 	})
 
+	if format.Structured() {
+		report := secretsReport{
+			Services: args,
+			Backends: backends,
+			Secrets:  make([]secretReport, 0, len(statuses)),
+		}
+		missingRequired := 0
+		for _, s := range statuses {
+			report.Secrets = append(report.Secrets, secretReport{
+				Name:        s.Name,
+				Description: s.Description,
+				Required:    s.Required,
+				Found:       s.Found,
+				Source:      s.Source,
+				MissingFor:  s.MissingFor,
+			})
+			if s.Required && !s.Found {
+				missingRequired++
+			}
+		}
+		if err := shared.WriteStructured(os.Stdout, format, report); err != nil {
+			return err
+		}
+		if missingRequired > 0 {
+			return fmt.Errorf("missing required secrets: %d", missingRequired)
+		}
+		return nil
+	}
+
+	if len(statuses) == 0 {
+		ui.Infof("No secrets declared for these services")
+		return nil
+	}
+
 	// Print service names
 	serviceNames := make([]string, len(args))
 	copy(serviceNames, args)
@@ -110,12 +243,7 @@ func runSecretsList(cmd *cobra.Command, args []string) error {
 		if s.Found {
 			statusIcon = "✓"
 			statusColor = text.FgGreen
-			statusText = "found"
-			if s.Source == "file" {
-				statusText = "found (file)"
-			} else {
-				statusText = "found (env)"
-			}
+			statusText = fmt.Sprintf("found (%s)", s.Source)
 		} else if s.Required {
 			statusIcon = "✗"
 			statusColor = text.FgRed
@@ -138,9 +266,7 @@ func runSecretsList(cmd *cobra.Command, args []string) error {
 
 	// Print summary
 	fmt.Println()
-	if err := loader.Load(); err == nil {
-		fmt.Printf("Source: %s (%d keys loaded)\n", loader.GetSecretsFilePath(), loader.GetLoadedCount())
-	}
+	fmt.Printf("Backends: %s\n", strings.Join(backends, " → "))
 
 	if missingRequired > 0 {
 		fmt.Println()
@@ -195,86 +321,186 @@ func runSecretsInit(cmd *cobra.Command, args []string) error {
 
 	// Get secrets file path
 	secretsPath := loader.GetSecretsFilePath()
-
-	// Ensure directory exists
-	dir := filepath.Dir(secretsPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	if secretsPath == "" {
+		for _, s := range missing {
+			fmt.Printf("  %s\n", s.Name)
+		}
+		return fmt.Errorf("no plaintext file backend is configured; add the secrets above to your secret backends")
 	}
 
-	// Read existing content if file exists
+	// Append placeholders under the lock; the file is created 0600
+	var added []string
 	existingContent := ""
-	if data, err := os.ReadFile(secretsPath); err == nil {
+	err = secrets.NewFileStore(secretsPath).Update(func(data []byte) ([]byte, error) {
 		existingContent = string(data)
-	}
+		existingKeys, err := secrets.ParseDotenv(data, secretsPath, nil)
+		if err != nil {
+			return nil, err
+		}
 
-	// Build new content to append
-	var newLines []string
-	existingKeys := parseExistingKeys(existingContent)
+		var newLines []string
+		for _, s := range missing {
+			// Skip if key already exists in file (even if empty)
+			if _, exists := existingKeys[s.Name]; exists {
+				continue
+			}
 
-	for _, s := range missing {
-		// Skip if key already exists in file (even if empty)
-		if _, exists := existingKeys[s.Name]; exists {
-			continue
+			comment := ""
+			if s.Description != "" {
+				comment = fmt.Sprintf("  # %s", s.Description)
+			}
+			if !s.Required {
+				comment += " (optional)"
+			}
+			newLines = append(newLines, fmt.Sprintf("%s=%s", s.Name, comment))
+			added = append(added, s.Name)
+		}
+		if len(newLines) == 0 {
+			return data, nil
 		}
 
-		comment := ""
-		if s.Description != "" {
-			comment = fmt.Sprintf("  # %s", s.Description)
+		var b strings.Builder
+		b.WriteString(existingContent)
+
+		// Add newline before appending if file has content and doesn't end with newline
+		if existingContent != "" && !strings.HasSuffix(existingContent, "\n") {
+			b.WriteString("\n")
 		}
-		if !s.Required {
-			comment += " (optional)"
+
+		// Add a comment header if this is new content being added
+		if existingContent == "" {
+			b.WriteString("# Grund secrets - Auto-generated template\n")
+			b.WriteString("# Add your secret values and remove the comments\n\n")
+		} else {
+			b.WriteString("\n# Added by grund secrets init\n")
+		}
+
+		for _, line := range newLines {
+			b.WriteString(line + "\n")
 		}
-		newLines = append(newLines, fmt.Sprintf("%s=%s", s.Name, comment))
+		return []byte(b.String()), nil
+	})
+	if err != nil {
+		return err
 	}
 
-	if len(newLines) == 0 {
+	if len(added) == 0 {
 		ui.Successf("All secrets already have placeholders in %s", secretsPath)
 		return nil
 	}
 
-	// Append to file
-	file, err := os.OpenFile(secretsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
-	if err != nil {
-		return fmt.Errorf("failed to open secrets file: %w", err)
+	// Print summary
+	if existingContent == "" {
+		fmt.Printf("\nCreated %s with %d placeholders:\n\n", secretsPath, len(added))
+	} else {
+		fmt.Printf("\nAppended %d placeholders to %s:\n\n", len(added), secretsPath)
 	}
-	defer file.Close()
 
-	// Add newline before appending if file has content and doesn't end with newline
-	if existingContent != "" && !strings.HasSuffix(existingContent, "\n") {
-		file.WriteString("\n")
+	for _, name := range added {
+		fmt.Printf("  %s\n", name)
 	}
 
-	// Add a comment header if this is new content being added
-	if existingContent == "" {
-		file.WriteString("# Grund secrets - Auto-generated template\n")
-		file.WriteString("# Add your secret values and remove the comments\n\n")
-	} else {
-		file.WriteString("\n# Added by grund secrets init\n")
+	fmt.Println("\nEdit the file or run 'grund secrets set <name>' to add your secret values.")
+	return nil
+}
+
+func runSecretsSet(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if !secrets.ValidName(name) {
+		return fmt.Errorf("invalid secret name %q: use letters, digits and underscores", name)
 	}
 
-	for _, line := range newLines {
-		file.WriteString(line + "\n")
+	store, err := secretsStore(secretsService)
+	if err != nil {
+		return err
 	}
 
-	// Print summary
-	if existingContent == "" {
-		fmt.Printf("\nCreated %s with %d placeholders:\n\n", secretsPath, len(newLines))
+	var value string
+	if secretsFromStdin {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+		value = strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
 	} else {
-		fmt.Printf("\nAppended %d placeholders to %s:\n\n", len(newLines), secretsPath)
+		if !ui.IsTerminal(os.Stdin) {
+			return fmt.Errorf("stdin is not a terminal; pipe the value with --from-stdin")
+		}
+		value, err = prompts.Password(fmt.Sprintf("Value for %s", name))
+		if err != nil {
+			return fmt.Errorf("failed to prompt for %s (pipe the value with --from-stdin instead): %w", name, err)
+		}
+	}
+	if value == "" {
+		return fmt.Errorf("empty value for %s; use 'grund secrets unset %s' to remove it", name, name)
 	}
 
-	for _, s := range missing {
-		if _, exists := existingKeys[s.Name]; exists {
-			continue
-		}
-		fmt.Printf("  %s\n", s.Name)
+	if err := store.Set(name, value); err != nil {
+		return err
+	}
+	ui.Successf("Set %s in %s", name, store.Path())
+	return nil
+}
+
+func runSecretsGet(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	loader := generator.NewSecretsLoader()
+	value, source, found, err := loader.Lookup(secretsService, name)
+	if err != nil {
+		return err
+	}
+	if !found {
+		backends, _ := loader.Backends()
+		return fmt.Errorf("secret %s not found (backends: %s)", name, strings.Join(backends, ", "))
 	}
 
-	fmt.Println("\nEdit the file and add your secret values.")
+	if secretsShow {
+		fmt.Println(value)
+		return nil
+	}
+	fmt.Printf("%s=%s (%s)\n", name, shared.RedactedValue, source)
 	return nil
 }
 
+func runSecretsUnset(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	store, err := secretsStore(secretsService)
+	if err != nil {
+		return err
+	}
+
+	removed, err := store.Unset(name)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		ui.Infof("%s is not set in %s", name, store.Path())
+		return nil
+	}
+	ui.Successf("Removed %s from %s", name, store.Path())
+	return nil
+}
+
+// secretsStore returns the file grund secrets set and unset edit:
+// the first plaintext file backend, or its secrets.d/<service>.env
+func secretsStore(serviceName string) (*secrets.FileStore, error) {
+	loader := generator.NewSecretsLoader()
+	if err := loader.Load(); err != nil {
+		return nil, err
+	}
+	path := loader.GetSecretsFilePath()
+	if path == "" {
+		return nil, fmt.Errorf("no plaintext file backend is configured; store the secret in your secret backend instead")
+	}
+	if serviceName == "" {
+		return secrets.NewFileStore(path), nil
+	}
+	if serviceName != filepath.Base(serviceName) || strings.HasPrefix(serviceName, ".") {
+		return nil, fmt.Errorf("invalid service name %q", serviceName)
+	}
+	return secrets.NewFileStore(secrets.ServiceFilePath(path, serviceName)), nil
+}
+
 // getServicesWithDependencies loads services and their dependencies
 func getServicesWithDependencies(cmd *cobra.Command, serviceNames []string) ([]*service.Service, error) {
 	var allServices []*service.Service
@@ -312,20 +538,3 @@ func getServicesWithDependencies(cmd *cobra.Command, serviceNames []string) ([]*
 
 	return allServices, nil
 }
-
-// parseExistingKeys extracts key names from existing secrets file content
-func parseExistingKeys(content string) map[string]bool {
-	keys := make(map[string]bool)
-	scanner := bufio.NewScanner(strings.NewReader(content))
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) >= 1 {
-			keys[strings.TrimSpace(parts[0])] = true
-		}
-	}
-	return keys
-}
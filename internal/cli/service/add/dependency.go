@@ -36,9 +36,16 @@ func runAddDependency(cmd *cobra.Command, args []string) error {
 		services = []any{}
 	}
 
-	// Check if already exists
+	// Check if already exists, listed by name or as a {name, wait_for} mapping
 	for _, s := range services {
-		if str, ok := s.(string); ok && str == serviceName {
+		var name any
+		switch entry := s.(type) {
+		case string:
+			name = entry
+		case map[string]any:
+			name = entry["name"]
+		}
+		if name == serviceName {
 			return fmt.Errorf("service %s already in dependencies", serviceName)
 		}
 	}
@@ -10,6 +10,8 @@ import (
 var (
 	postgresMigrations string
 	postgresSeed       string
+	postgresUsername   string
+	postgresPassword   string
 )
 
 var postgresCmd = &cobra.Command{
@@ -20,7 +22,8 @@ var postgresCmd = &cobra.Command{
 Examples:
   grund service add postgres mydb
   grund service add postgres users_db --migrations ./db/migrations
-  grund service add postgres app_db --seed ./fixtures/seed.sql`,
+  grund service add postgres app_db --seed ./fixtures/seed.sql
+  grund service add postgres payments_db --username payments --password payments`,
 	Args: cobra.ExactArgs(1),
 	RunE: runAddPostgres,
 }
@@ -28,6 +31,8 @@ Examples:
 func init() {
 	postgresCmd.Flags().StringVar(&postgresMigrations, "migrations", "", "Path to migrations directory")
 	postgresCmd.Flags().StringVar(&postgresSeed, "seed", "", "Path to seed SQL file")
+	postgresCmd.Flags().StringVar(&postgresUsername, "username", "", "Dedicated role for this database (default: postgres)")
+	postgresCmd.Flags().StringVar(&postgresPassword, "password", "", "Password for the dedicated role")
 }
 
 func runAddPostgres(cmd *cobra.Command, args []string) error {
@@ -46,6 +51,12 @@ func runAddPostgres(cmd *cobra.Command, args []string) error {
 	}
 
 	pg := map[string]any{"database": database}
+	if postgresUsername != "" {
+		pg["username"] = postgresUsername
+	}
+	if postgresPassword != "" {
+		pg["password"] = postgresPassword
+	}
 	if postgresMigrations != "" {
 		pg["migrations"] = postgresMigrations
 	}
@@ -54,7 +65,7 @@ func runAddPostgres(cmd *cobra.Command, args []string) error {
 	}
 	infra["postgres"] = pg
 
-	addEnvRef(config, "DATABASE_URL", "postgres://postgres:postgres@${postgres.host}:${postgres.port}/${self.postgres.database}")
+	addEnvRef(config, "DATABASE_URL", "postgres://${self.postgres.username}:${self.postgres.password}@${postgres.host}:${postgres.port}/${self.postgres.database}")
 
 	if err := writeConfig(config, configPath); err != nil {
 		return err
@@ -2,104 +2,96 @@ package service
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
-	"slices"
 
 	"github.com/spf13/cobra"
+	"github.com/vivekkundariya/grund/internal/application/queries"
+	"github.com/vivekkundariya/grund/internal/application/wiring"
+	"github.com/vivekkundariya/grund/internal/cli/shared"
 	"github.com/vivekkundariya/grund/internal/ui"
-	"gopkg.in/yaml.v3"
 )
 
+var validateAll bool
+
 var validateCmd = &cobra.Command{
 	Use:   "validate",
 	Short: "Validate grund.yaml configuration",
-	Long: `Validates the grund.yaml file in the current directory.
+	Long: `Validates the grund.yaml file in the current directory, reporting every
+problem with its line and column.
 
 Checks:
-  - YAML syntax
-  - Required fields (service.name, service.type, service.port)
-  - Infrastructure configuration structure
+  - YAML syntax and unknown fields
+  - Required fields, service type, port, health check durations
+  - Build context, Dockerfile, migrations and seed paths exist
+  - Dependencies and their wait_for conditions
+  - Every env_refs placeholder resolves against the declared infrastructure,
+    dependencies and tunnels
+  - SNS subscription protocols, endpoints and filter policies
+
+With --all, validates the grund.yaml of every service in services.yaml and
+also checks that dependencies are registered services.
 
 Example:
   cd my-service
-  grund service validate`,
+  grund service validate
+  grund service validate --all`,
 	RunE: runValidate,
 }
 
 func init() {
+	validateCmd.Flags().BoolVar(&validateAll, "all", false, "Validate every registered service")
 	Cmd.AddCommand(validateCmd)
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
-	configPath := filepath.Join(".", "grund.yaml")
-
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return fmt.Errorf("cannot read grund.yaml: %w", err)
+	if validateAll {
+		if shared.Container == nil {
+			return fmt.Errorf("container not initialized")
+		}
+		results, err := shared.Container.ValidateQueryHandler.HandleAll()
+		if err != nil {
+			return err
+		}
+		return reportValidation(results)
 	}
 
-	var config map[string]any
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return fmt.Errorf("invalid YAML syntax: %w", err)
+	handler := wiring.NewValidateQueryHandler()
+	if shared.Container != nil {
+		handler = shared.Container.ValidateQueryHandler
 	}
-
-	// Validate required fields
-	errors := validateConfig(config)
-
-	if len(errors) > 0 {
-		ui.Error("Validation failed:")
-		for _, e := range errors {
-			fmt.Printf("  - %s\n", e)
-		}
-		return fmt.Errorf("%d validation error(s)", len(errors))
+	result, err := handler.Handle(queries.ValidateQuery{ConfigPath: filepath.Join(".", "grund.yaml")})
+	if err != nil {
+		return err
 	}
-
-	ui.Success("grund.yaml is valid")
-	return nil
+	return reportValidation([]queries.ValidationResult{*result})
 }
 
-func validateConfig(config map[string]any) []string {
-	var errors []string
-
-	// Check service section
-	svc, ok := config["service"].(map[string]any)
-	if !ok {
-		errors = append(errors, "missing required section: service")
-		return errors
-	}
-
-	// Required service fields
-	requiredFields := []string{"name", "type", "port"}
-	for _, field := range requiredFields {
-		if _, ok := svc[field]; !ok {
-			errors = append(errors, fmt.Sprintf("missing required field: service.%s", field))
+// reportValidation prints each file's issues as path:line:col: field: message
+func reportValidation(results []queries.ValidationResult) error {
+	issues := 0
+	for _, result := range results {
+		name := result.Service
+		if name == "" {
+			name = result.ConfigPath
 		}
-	}
-
-	// Validate service type if present
-	if svcType, ok := svc["type"].(string); ok {
-		validTypes := []string{"go", "python", "node", "ruby", "java"}
-		if !slices.Contains(validTypes, svcType) {
-			errors = append(errors, fmt.Sprintf("invalid service.type: %s (valid: go, python, node, ruby, java)", svcType))
+		if result.Valid() {
+			ui.Successf("%s is valid", name)
+			continue
 		}
-	}
 
-	// Validate port if present
-	if port, ok := svc["port"]; ok {
-		switch p := port.(type) {
-		case int:
-			if p < 1 || p > 65535 {
-				errors = append(errors, fmt.Sprintf("invalid service.port: %d (must be 1-65535)", p))
-			}
-		case float64:
-			if p < 1 || p > 65535 {
-				errors = append(errors, fmt.Sprintf("invalid service.port: %.0f (must be 1-65535)", p))
+		issues += len(result.Issues)
+		ui.Errorf("%s: %d problem(s)", name, len(result.Issues))
+		for _, issue := range result.Issues {
+			separator := ": "
+			if issue.Line > 0 {
+				separator = ":"
 			}
-		default:
-			errors = append(errors, "service.port must be a number")
+			fmt.Printf("  %s%s%s\n", result.ConfigPath, separator, issue)
 		}
 	}
 
-	return errors
+	if issues > 0 {
+		return fmt.Errorf("%d validation error(s)", issues)
+	}
+	return nil
 }
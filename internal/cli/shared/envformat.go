@@ -0,0 +1,69 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/vivekkundariya/grund/internal/infrastructure/generator"
+)
+
+// EnvFormat selects how grund env prints a resolved environment
+type EnvFormat string
+
+// Supported env formats
+const (
+	EnvDotenv EnvFormat = "dotenv" // KEY=value, for IDE run configurations and dotenv loaders
+	EnvJSON   EnvFormat = "json"   // a flat object
+	EnvShell  EnvFormat = "shell"  // export KEY='value', for eval "$(grund env ...)"
+	EnvDirenv EnvFormat = "direnv" // an .envrc of export lines
+)
+
+// ParseEnvFormat validates a --format flag value; empty means dotenv
+func ParseEnvFormat(s string) (EnvFormat, error) {
+	switch EnvFormat(s) {
+	case "", EnvDotenv:
+		return EnvDotenv, nil
+	case EnvJSON, EnvShell, EnvDirenv:
+		return EnvFormat(s), nil
+	default:
+		return "", fmt.Errorf("invalid env format %q (expected dotenv, json, shell or direnv)", s)
+	}
+}
+
+// WriteEnv prints env in the given format, sorted by key
+func WriteEnv(w io.Writer, format EnvFormat, env map[string]string) error {
+	if format == EnvJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(env)
+	}
+
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		switch format {
+		case EnvDotenv:
+			fmt.Fprintf(&b, "%s=%s\n", k, generator.QuoteEnvValue(env[k]))
+		case EnvShell, EnvDirenv:
+			fmt.Fprintf(&b, "export %s=%s\n", k, shellQuote(env[k]))
+		default:
+			return fmt.Errorf("unsupported env format %q", format)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// shellQuote single-quotes a value so a POSIX shell reads it literally
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
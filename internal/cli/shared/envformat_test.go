@@ -0,0 +1,37 @@
+package shared
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteEnv(t *testing.T) {
+	env := map[string]string{
+		"DATABASE_URL": "postgres://localhost:5432/app",
+		"GREETING":     "it's $HOME",
+	}
+
+	tests := []struct {
+		format EnvFormat
+		want   string
+	}{
+		{EnvDotenv, "DATABASE_URL=postgres://localhost:5432/app\nGREETING=\"it's \\$HOME\"\n"},
+		{EnvShell, "export DATABASE_URL='postgres://localhost:5432/app'\nexport GREETING='it'\\''s $HOME'\n"},
+		{EnvDirenv, "export DATABASE_URL='postgres://localhost:5432/app'\nexport GREETING='it'\\''s $HOME'\n"},
+		{EnvJSON, "{\n  \"DATABASE_URL\": \"postgres://localhost:5432/app\",\n  \"GREETING\": \"it's $HOME\"\n}\n"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := WriteEnv(&buf, tt.format, env); err != nil {
+			t.Fatalf("WriteEnv(%s) error: %v", tt.format, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("WriteEnv(%s) = %q, want %q", tt.format, buf.String(), tt.want)
+		}
+	}
+
+	if _, err := ParseEnvFormat("toml"); err == nil {
+		t.Error("ParseEnvFormat(toml) should fail")
+	}
+}
@@ -0,0 +1,58 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OutputFormat selects how a command prints its result
+type OutputFormat string
+
+// Supported output formats
+const (
+	OutputTable OutputFormat = "table"
+	OutputJSON  OutputFormat = "json"
+	OutputYAML  OutputFormat = "yaml"
+)
+
+// RedactedValue replaces secret values unless --show-secrets is given
+const RedactedValue = "********"
+
+// ParseOutputFormat validates an --output flag value; empty means table
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	switch OutputFormat(s) {
+	case "", OutputTable:
+		return OutputTable, nil
+	case OutputJSON, OutputYAML:
+		return OutputFormat(s), nil
+	default:
+		return "", fmt.Errorf("invalid output format %q (expected table, json or yaml)", s)
+	}
+}
+
+// Structured reports whether the format is machine-readable
+func (f OutputFormat) Structured() bool {
+	return f == OutputJSON || f == OutputYAML
+}
+
+// WriteStructured encodes v as an indented JSON or YAML document
+func WriteStructured(w io.Writer, format OutputFormat, v any) error {
+	switch format {
+	case OutputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case OutputYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("output format %q is not structured", format)
+	}
+}
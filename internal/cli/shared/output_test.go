@@ -0,0 +1,60 @@
+package shared
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    OutputFormat
+		wantErr bool
+	}{
+		{"", OutputTable, false},
+		{"table", OutputTable, false},
+		{"json", OutputJSON, false},
+		{"yaml", OutputYAML, false},
+		{"xml", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseOutputFormat(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseOutputFormat(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseOutputFormat(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWriteStructured(t *testing.T) {
+	v := struct {
+		Name  string   `json:"name" yaml:"name"`
+		Ports []int    `json:"ports" yaml:"ports"`
+		Tags  []string `json:"tags" yaml:"tags"`
+	}{Name: "api", Ports: []int{8080}, Tags: []string{}}
+
+	var buf bytes.Buffer
+	if err := WriteStructured(&buf, OutputJSON, v); err != nil {
+		t.Fatalf("WriteStructured(json) error: %v", err)
+	}
+	wantJSON := "{\n  \"name\": \"api\",\n  \"ports\": [\n    8080\n  ],\n  \"tags\": []\n}\n"
+	if buf.String() != wantJSON {
+		t.Errorf("json = %q, want %q", buf.String(), wantJSON)
+	}
+
+	buf.Reset()
+	if err := WriteStructured(&buf, OutputYAML, v); err != nil {
+		t.Fatalf("WriteStructured(yaml) error: %v", err)
+	}
+	wantYAML := "name: api\nports:\n  - 8080\ntags: []\n"
+	if buf.String() != wantYAML {
+		t.Errorf("yaml = %q, want %q", buf.String(), wantYAML)
+	}
+
+	if err := WriteStructured(&buf, OutputTable, v); err == nil {
+		t.Error("WriteStructured(table) should fail")
+	}
+}
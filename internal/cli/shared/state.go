@@ -3,6 +3,7 @@ package shared
 import (
 	"github.com/vivekkundariya/grund/internal/application/wiring"
 	"github.com/vivekkundariya/grund/internal/config"
+	"github.com/vivekkundariya/grund/internal/infrastructure/docker"
 )
 
 var (
@@ -11,4 +12,10 @@ var (
 
 	// ConfigResolver is the config resolver initialized by root command
 	ConfigResolver *config.ConfigResolver
+
+	// Environment is the grund environment selected with --env or GRUND_ENV
+	Environment *config.Environment
+
+	// Runtime runs engine and compose commands for the configured docker.compose_command
+	Runtime *docker.Runtime
 )
@@ -25,6 +25,7 @@ grund up <service>              # Start service with all dependencies
 grund up svc-a svc-b            # Start multiple services
 grund up <service> --no-deps    # Start without dependencies
 grund up <service> --infra-only # Only start infrastructure
+grund up <service> --build      # Rebuild even if nothing changed
 ` + "```" + `
 
 ### Checking Status
@@ -32,20 +33,25 @@ grund up <service> --infra-only # Only start infrastructure
 grund status                    # Show all services and URLs
 grund logs <service>            # View logs
 grund logs -f                   # Follow all logs
+grund doctor                    # Check the workspace and machine for problems
 ` + "```" + `
 
 ### Managing Services
 ` + "```bash" + `
 grund down                      # Stop all services
-grund restart <service>         # Restart specific service
+grund down <service>            # Stop one service, leave the rest running
+grund restart <service>         # Reload grund.yaml and recreate a service
+grund restart <service> --build # ...rebuilding its image
 grund reset                     # Stop and clean up
 grund reset -v                  # Also remove volumes (data)
+grund prune --dry-run           # List leftovers of removed services
 ` + "```" + `
 
 ### Secrets
 ` + "```bash" + `
 grund secrets list <service>    # Check which secrets are needed
 grund secrets init <service>    # Generate ~/.grund/secrets.env template
+grund secrets set <NAME>        # Set a value (prompt, or --from-stdin)
 ` + "```" + `
 
 ### Configuration
@@ -67,6 +73,7 @@ grund service add bucket <name> # Add S3 bucket
 grund service add tunnel <name> # Add tunnel for external access
 grund service add dependency <svc> # Add service dependency
 grund service validate          # Validate grund.yaml
+grund service validate --all    # Validate every registered service
 ` + "```" + `
 
 ---
@@ -216,6 +223,14 @@ Use ` + "`${variable}`" + ` syntax in ` + "`env_refs`" + `. All variables are re
 | ` + "`${<service>.host}`" + ` | ` + "`user-service`" + ` (container name) |
 | ` + "`${<service>.port}`" + ` | ` + "`8080`" + ` (container port) |
 
+**Env, secrets and syntax:**
+| Variable | Meaning |
+|----------|---------|
+| ` + "`${env.<NAME>}`" + ` | Static ` + "`env`" + ` var, then the host environment |
+| ` + "`${secret.<NAME>}`" + ` | A secret declared under ` + "`secrets`" + ` |
+| ` + "`${path:-default}`" + ` | ` + "`default`" + ` when ` + "`path`" + ` can't be resolved or is empty |
+| ` + "`$${`" + ` | A literal ` + "`${`" + ` |
+
 ### Environment Variables Example
 ` + "```yaml" + `
 env:
@@ -291,9 +306,9 @@ env_refs:
   REDIS_URL: "redis://${redis.host}:${redis.port}"
 ` + "```" + `
 
-3. Restart to pick up changes:
+3. Re-run up to pick up changes (only changed services are recreated):
 ` + "```bash" + `
-grund down && grund up my-service
+grund up my-service
 ` + "```" + `
 
 ### Debugging a Service That Won't Start
@@ -326,8 +341,8 @@ grund up order-service --no-deps
 # View logs from all services
 grund logs -f
 
-# Restart just one service after code change
-grund restart order-service
+# Rebuild and restart just one service after a code change
+grund restart order-service --build
 ` + "```" + `
 
 ### Setting Up a New Service with Grund
@@ -429,9 +444,8 @@ grund config show my-service | grep DATABASE
 
 ### Changes to grund.yaml not taking effect
 ` + "```bash" + `
-# Must restart to pick up config changes
-grund down
-grund up my-service
+# Reload the service's config and recreate its container
+grund restart my-service
 
 # For infrastructure changes, may need full reset
 grund reset -v
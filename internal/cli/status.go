@@ -1,77 +1,295 @@
 package cli
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/spf13/cobra"
+	"github.com/vivekkundariya/grund/internal/application/ports"
 	"github.com/vivekkundariya/grund/internal/application/queries"
 	"github.com/vivekkundariya/grund/internal/cli/shared"
 	"github.com/vivekkundariya/grund/internal/ui"
 )
 
+var (
+	statusOutput   string
+	statusWatch    bool
+	statusInterval time.Duration
+)
+
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show running services and health",
-	Long:  `Display the status of all running services and infrastructure, including health checks.`,
+	Long: `Display the status of all running services and infrastructure, including health checks.
+
+Examples:
+  grund status                  Show a status table
+  grund status --watch          Refresh the table in place every 2s
+  grund status -o json          Print status as JSON for scripts
+  grund status -o json --watch  Print a JSON document on every refresh`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := shared.ParseOutputFormat(statusOutput)
+		if err != nil {
+			return err
+		}
+		if statusInterval <= 0 {
+			return fmt.Errorf("--interval must be positive, got %s", statusInterval)
+		}
+
 		if shared.Container == nil {
 			return fmt.Errorf("container not initialized")
 		}
 
-		query := queries.StatusQuery{ServiceName: nil}
-		statuses, err := shared.Container.StatusQueryHandler.Handle(cmd.Context(), query)
-		if err != nil {
+		if !statusWatch {
+			statuses, err := queryStatuses(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if len(statuses) == 0 && !format.Structured() {
+				ui.Infof("No services found. Run 'grund up <service>' to start services.")
+				return nil
+			}
+			return writeStatus(os.Stdout, format, statuses)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return watchStatus(ctx, os.Stdout, format)
+	},
+}
+
+func init() {
+	statusCmd.Flags().StringVarP(&statusOutput, "output", "o", "table", "Output format: table, json or yaml")
+	statusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, "Refresh the status until interrupted")
+	statusCmd.Flags().DurationVar(&statusInterval, "interval", 2*time.Second, "Refresh interval for --watch")
+}
+
+// statusReport is the --output schema of grund status
+type statusReport struct {
+	Environment string                `json:"environment" yaml:"environment"`
+	Services    []serviceStatusReport `json:"services" yaml:"services"`
+}
+
+type serviceStatusReport struct {
+	Name         string       `json:"name" yaml:"name"`
+	Container    string       `json:"container" yaml:"container"`
+	Status       string       `json:"status" yaml:"status"`
+	Health       string       `json:"health" yaml:"health"`
+	Endpoint     string       `json:"endpoint" yaml:"endpoint"`
+	Ports        []portReport `json:"ports" yaml:"ports"`
+	StartedAt    *time.Time   `json:"started_at" yaml:"started_at"`
+	RestartCount int          `json:"restart_count" yaml:"restart_count"`
+	ExitCode     int          `json:"exit_code" yaml:"exit_code"`
+}
+
+type portReport struct {
+	Host      int    `json:"host" yaml:"host"`
+	Container int    `json:"container" yaml:"container"`
+	Protocol  string `json:"protocol" yaml:"protocol"`
+}
+
+// newStatusReport converts statuses to the stable --output schema
+func newStatusReport(statuses []ports.ServiceStatus) statusReport {
+	report := statusReport{Services: make([]serviceStatusReport, 0, len(statuses))}
+	if shared.Environment != nil {
+		report.Environment = shared.Environment.Name
+	}
+
+	for _, s := range statuses {
+		svc := serviceStatusReport{
+			Name:         s.Name,
+			Container:    s.Container,
+			Status:       s.Status,
+			Health:       s.Health,
+			Endpoint:     s.Endpoint,
+			Ports:        make([]portReport, 0, len(s.Ports)),
+			RestartCount: s.RestartCount,
+			ExitCode:     s.ExitCode,
+		}
+		if !s.StartedAt.IsZero() {
+			startedAt := s.StartedAt
+			svc.StartedAt = &startedAt
+		}
+		for _, p := range s.Ports {
+			svc.Ports = append(svc.Ports, portReport{Host: p.HostPort, Container: p.ContainerPort, Protocol: p.Protocol})
+		}
+		report.Services = append(report.Services, svc)
+	}
+
+	return report
+}
+
+// queryStatuses returns the status of every service
+func queryStatuses(ctx context.Context) ([]ports.ServiceStatus, error) {
+	return shared.Container.StatusQueryHandler.Handle(ctx, queries.StatusQuery{ServiceName: nil})
+}
+
+// writeStatus prints statuses as a table or a structured document
+func writeStatus(w io.Writer, format shared.OutputFormat, statuses []ports.ServiceStatus) error {
+	if format.Structured() {
+		return shared.WriteStructured(w, format, newStatusReport(statuses))
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, renderStatusTable(statuses))
+	fmt.Fprintln(w)
+	return nil
+}
+
+// watchStatus re-queries status every interval until ctx is cancelled
+// On a terminal the table is redrawn in place; otherwise (or with --output)
+// each refresh is printed after the previous one.
+func watchStatus(ctx context.Context, w io.Writer, format shared.OutputFormat) error {
+	live := !format.Structured() && ui.IsTerminal(w)
+	ticker := time.NewTicker(statusInterval)
+	defer ticker.Stop()
+
+	drawnLines := 0
+	for {
+		statuses, err := queryStatuses(ctx)
+		if err != nil && ctx.Err() == nil {
 			return err
 		}
+		if ctx.Err() != nil {
+			return nil
+		}
 
-		if len(statuses) == 0 {
-			ui.Infof("No services found. Run 'grund up <service>' to start services.")
+		var buf bytes.Buffer
+		if format == shared.OutputYAML {
+			buf.WriteString("---\n")
+		}
+		if !format.Structured() {
+			fmt.Fprintf(&buf, "Every %s · %s · Ctrl+C to stop\n", statusInterval, time.Now().Format("15:04:05"))
+		}
+		if err := writeStatus(&buf, format, statuses); err != nil {
+			return err
+		}
+
+		// Move back over the previous frame and clear it before drawing the next
+		if live && drawnLines > 0 {
+			fmt.Fprintf(w, "\033[%dA\033[J", drawnLines)
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+		drawnLines = strings.Count(buf.String(), "\n")
+
+		select {
+		case <-ctx.Done():
 			return nil
+		case <-ticker.C:
 		}
+	}
+}
 
-		t := table.NewWriter()
-		t.SetOutputMirror(os.Stdout)
-		t.SetStyle(table.StyleRounded)
-
-		t.AppendHeader(table.Row{"Service", "Status", "URL"})
-
-		for _, s := range statuses {
-			var statusIcon string
-			var statusColor text.Color
-			switch s.Status {
-			case "running":
-				statusIcon = "●"
-				statusColor = text.FgGreen
-			case "exited":
-				statusIcon = "●"
-				statusColor = text.FgRed
-			default:
-				statusIcon = "○"
-				statusColor = text.FgYellow
-			}
+// renderStatusTable renders statuses as a table
+func renderStatusTable(statuses []ports.ServiceStatus) string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleRounded)
 
-			statusText := fmt.Sprintf("%s %s", statusIcon, s.Status)
+	t.AppendHeader(table.Row{"Service", "Status", "Health", "Uptime", "Restarts", "Ports", "URL"})
 
-			// Format URL - show "-" if not available or not running
-			url := "-"
-			if s.Endpoint != "" && s.Status == "running" {
-				url = text.FgCyan.Sprint(s.Endpoint)
-			}
+	for _, s := range statuses {
+		var statusIcon string
+		var statusColor text.Color
+		switch s.Status {
+		case "running":
+			statusIcon = "●"
+			statusColor = text.FgGreen
+		case "exited":
+			statusIcon = "●"
+			statusColor = text.FgRed
+		default:
+			statusIcon = "○"
+			statusColor = text.FgYellow
+		}
 
-			t.AppendRow(table.Row{
-				s.Name,
-				statusColor.Sprint(statusText),
-				url,
-			})
+		statusText := fmt.Sprintf("%s %s", statusIcon, s.Status)
+		if s.Status == "exited" {
+			statusText = fmt.Sprintf("%s exited (%d)", statusIcon, s.ExitCode)
 		}
 
-		fmt.Println()
-		t.Render()
-		fmt.Println()
+		uptime := "-"
+		if s.Status == "running" && !s.StartedAt.IsZero() {
+			uptime = formatUptime(time.Since(s.StartedAt))
+		}
 
-		return nil
-	},
+		restarts := "-"
+		if s.RestartCount > 0 {
+			restarts = text.FgYellow.Sprint(s.RestartCount)
+		}
+
+		// Format URL - show "-" if not available or not running
+		url := "-"
+		if s.Endpoint != "" && s.Status == "running" {
+			url = text.FgCyan.Sprint(s.Endpoint)
+		}
+
+		t.AppendRow(table.Row{
+			s.Name,
+			statusColor.Sprint(statusText),
+			formatHealth(s.Health),
+			uptime,
+			restarts,
+			formatPorts(s.Ports),
+			url,
+		})
+	}
+
+	return t.Render()
+}
+
+// formatHealth colours a container health state
+func formatHealth(health string) string {
+	switch health {
+	case "healthy":
+		return text.FgGreen.Sprint(health)
+	case "unhealthy":
+		return text.FgRed.Sprint(health)
+	case "starting":
+		return text.FgYellow.Sprint(health)
+	case "", "unknown":
+		return "-"
+	default:
+		return health
+	}
+}
+
+// formatUptime renders a duration at the coarsest useful unit, e.g. 45s, 12m, 3h5m, 2d4h
+func formatUptime(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// formatPorts renders published ports as host->container pairs
+func formatPorts(bindings []ports.PortBinding) string {
+	if len(bindings) == 0 {
+		return "-"
+	}
+	parts := make([]string, len(bindings))
+	for i, b := range bindings {
+		parts[i] = fmt.Sprintf("%d->%d", b.HostPort, b.ContainerPort)
+		if b.Protocol != "" && b.Protocol != "tcp" {
+			parts[i] += "/" + b.Protocol
+		}
+	}
+	return strings.Join(parts, ", ")
 }
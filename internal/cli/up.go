@@ -2,6 +2,10 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	"github.com/vivekkundariya/grund/internal/application/commands"
@@ -14,14 +18,38 @@ var (
 	upNoDeps    bool
 	upInfraOnly bool
 	upBuild     bool
-	upLocal     bool
+	upLocal     []string
+	upWatch     bool
+	upNoWait    bool
 )
 
 var upCmd = &cobra.Command{
 	Use:   "up [services...]",
 	Short: "Start services and dependencies",
 	Long: `Start one or more services along with all their dependencies.
-Infrastructure will be started first, followed by services in dependency order.`,
+Infrastructure will be started first, followed by services in dependency order.
+
+Use --local to run a service on your host (e.g. in your IDE debugger) while
+everything else runs in Docker. Grund skips its container, writes its resolved
+environment (localhost + published ports) to an env file, and points other
+containers at it through host.docker.internal.
+
+After starting, grund polls each service's health.endpoint on its published port
+(honouring health interval, timeout and retries) and fails with the recent logs of
+any service that never becomes healthy. Use --no-wait to return right away.
+
+Services whose compose definition and build context (honouring .dockerignore)
+are unchanged since they last started are left running and reported as up to
+date. Use --build to rebuild and recreate them anyway.
+
+Use --watch to keep grund running after startup and restart services that set
+run.hot_reload whenever files in their (bind-mounted) directory change.
+
+Examples:
+  grund up user-service
+  grund up user-service --local user-service
+  grund up user-service --watch
+  grund up user-service --no-wait`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if shared.Container == nil {
@@ -39,17 +67,25 @@ Infrastructure will be started first, followed by services in dependency order.`
 			InfraOnly:    upInfraOnly,
 			Build:        upBuild,
 			Local:        upLocal,
+			Watch:        upWatch,
+			NoWait:       upNoWait,
 		}
 
-		return shared.Container.UpCommandHandler.Handle(cmd.Context(), upCmd)
+		// Stop watching cleanly on Ctrl+C
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return shared.Container.UpCommandHandler.Handle(ctx, upCmd)
 	},
 }
 
 func init() {
 	upCmd.Flags().BoolVar(&upNoDeps, "no-deps", false, "Only start specified services, no dependencies")
 	upCmd.Flags().BoolVar(&upInfraOnly, "infra-only", false, "Only start infrastructure, no services")
-	upCmd.Flags().BoolVar(&upBuild, "build", false, "Force rebuild containers")
-	upCmd.Flags().BoolVar(&upLocal, "local", false, "Run service locally (not in container)")
+	upCmd.Flags().BoolVar(&upBuild, "build", false, "Rebuild and recreate every service, even unchanged ones")
+	upCmd.Flags().StringSliceVar(&upLocal, "local", nil, "Run service(s) on the host instead of in a container")
+	upCmd.Flags().BoolVar(&upWatch, "watch", false, "Restart hot_reload services when their files change")
+	upCmd.Flags().BoolVar(&upNoWait, "no-wait", false, "Don't wait for services to pass their health checks")
 }
 
 // validateSecrets checks that all required secrets are available
@@ -83,7 +119,7 @@ func validateSecrets(cmd *cobra.Command, serviceNames []string) error {
 
 	if len(missing) > 0 {
 		for _, s := range missing {
-			ui.Errorf("  ✗ %s is required but not found", s.Name)
+			ui.Errorf("  ✗ %s is required but not found for %s", s.Name, strings.Join(s.MissingFor, ", "))
 		}
 		fmt.Println()
 		ui.Errorf("Missing required secrets: %d", len(missing))
@@ -98,7 +134,7 @@ func validateSecrets(cmd *cobra.Command, serviceNames []string) error {
 		count := 0
 		for _, svc := range services {
 			for name := range svc.Environment.Secrets {
-				if _, found := loader.GetSecretValue(name); found {
+				if _, found := loader.GetSecretValue(svc.Name, name); found {
 					count++
 				}
 			}
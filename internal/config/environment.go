@@ -0,0 +1,312 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EnvEnvironment is the environment variable selecting the grund environment
+	EnvEnvironment = "GRUND_ENV"
+
+	// DefaultEnvironmentName is the environment used when none is selected
+	// It keeps the original names (project "grund", ~/.grund/tmp) so existing stacks carry on working.
+	DefaultEnvironmentName = "default"
+
+	// environmentsDir holds per-environment state under the grund home
+	environmentsDir = "envs"
+
+	// environmentFile records an environment's port offset
+	environmentFile = "environment.yaml"
+
+	// environmentPortStep separates the host ports of side-by-side environments
+	environmentPortStep = 1000
+
+	// maxEnvironmentPortOffset keeps shifted ports below 65535 for typical service ports
+	maxEnvironmentPortOffset = 30000
+
+	// MaxHostPort is the highest port a container can be published on
+	MaxHostPort = 65535
+)
+
+// environmentNamePattern restricts names to what docker accepts in project, network and container names
+var environmentNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Environment namespaces everything grund creates so several stacks can run side by side
+// The compose project, network, container names, volumes (via the project) and the
+// generated files are derived from the name; published host ports are shifted by PortOffset.
+type Environment struct {
+	Name       string `yaml:"name"`
+	PortOffset int    `yaml:"port_offset"`
+}
+
+// DefaultEnvironment returns the default environment
+func DefaultEnvironment() *Environment {
+	return &Environment{Name: DefaultEnvironmentName}
+}
+
+// EnvironmentName picks the environment name
+// Priority: --env flag > GRUND_ENV env var > default
+func EnvironmentName(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	if name := os.Getenv(EnvEnvironment); name != "" {
+		return name
+	}
+	return DefaultEnvironmentName
+}
+
+// ValidateEnvironmentName checks that a name can be used in docker resource names
+func ValidateEnvironmentName(name string) error {
+	if !environmentNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid environment name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// IsDefault reports whether this is the default environment
+func (e *Environment) IsDefault() bool {
+	return e.Name == DefaultEnvironmentName
+}
+
+// ProjectName returns the docker compose project name
+func (e *Environment) ProjectName() string {
+	if e.IsDefault() {
+		return "grund"
+	}
+	return "grund-" + e.Name
+}
+
+// NetworkName returns the name of the shared docker network
+func (e *Environment) NetworkName() string {
+	return e.ProjectName() + "-network"
+}
+
+// ContainerName returns the container name for a service or infrastructure component
+func (e *Environment) ContainerName(name string) string {
+	return e.ProjectName() + "-" + name
+}
+
+// HostPort returns the host port a container port is published on
+func (e *Environment) HostPort(port int) int {
+	return port + e.PortOffset
+}
+
+// LocalStackEndpoint shifts the default host endpoint to this environment's LocalStack port
+// Custom endpoints are returned unchanged.
+func (e *Environment) LocalStackEndpoint(endpoint string) string {
+	if endpoint != DefaultLocalStackEndpoint {
+		return endpoint
+	}
+	return fmt.Sprintf("http://localhost:%d", e.HostPort(4566))
+}
+
+// Dir returns the directory holding the environment's state
+func (e *Environment) Dir() (string, error) {
+	grundHome, err := GetGrundHome()
+	if err != nil {
+		return "", err
+	}
+	if e.IsDefault() {
+		return grundHome, nil
+	}
+	return filepath.Join(grundHome, environmentsDir, e.Name), nil
+}
+
+// TmpDir returns the directory for generated compose and env files
+// ~/.grund/tmp for the default environment, ~/.grund/envs/<name>/tmp otherwise
+func (e *Environment) TmpDir() (string, error) {
+	dir, err := e.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tmp"), nil
+}
+
+// LoadEnvironment returns the named environment
+// With create, a missing environment is created and given the lowest port offset not
+// used by another environment; otherwise it is an error.
+func LoadEnvironment(name string, create bool) (*Environment, error) {
+	if name == "" || name == DefaultEnvironmentName {
+		return DefaultEnvironment(), nil
+	}
+	if err := ValidateEnvironmentName(name); err != nil {
+		return nil, err
+	}
+
+	env, err := readEnvironment(name)
+	if err == nil {
+		return env, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+	if !create {
+		return nil, fmt.Errorf("environment %s not found; create it with 'grund --env %s up <services>'", name, name)
+	}
+
+	existing, err := ListEnvironments()
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[int]bool, len(existing))
+	for _, other := range existing {
+		used[other.PortOffset] = true
+	}
+
+	env = &Environment{Name: name}
+	for offset := environmentPortStep; ; offset += environmentPortStep {
+		if offset > maxEnvironmentPortOffset {
+			return nil, fmt.Errorf("too many environments: remove one with 'grund env rm <name>'")
+		}
+		if !used[offset] {
+			env.PortOffset = offset
+			break
+		}
+	}
+
+	if err := saveEnvironment(env); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// ListEnvironments returns the default environment followed by all named environments
+func ListEnvironments() ([]*Environment, error) {
+	envs := []*Environment{DefaultEnvironment()}
+
+	grundHome, err := GetGrundHome()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(grundHome, environmentsDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return envs, nil
+		}
+		return nil, fmt.Errorf("failed to read environments: %w", err)
+	}
+
+	var named []*Environment
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		env, err := readEnvironment(entry.Name())
+		if err != nil {
+			continue // not an environment directory
+		}
+		named = append(named, env)
+	}
+	sort.Slice(named, func(i, j int) bool { return named[i].Name < named[j].Name })
+
+	return append(envs, named...), nil
+}
+
+// PublishedPort is a host port recorded in an environment's manifest
+type PublishedPort struct {
+	Environment string
+	Owner       string // service or infrastructure container
+}
+
+// OtherEnvironmentHostPorts returns the host ports recorded by every environment but env
+// Ports stay recorded while an environment is down, so a stopped stack keeps its ports.
+func OtherEnvironmentHostPorts(env *Environment) (map[int]PublishedPort, error) {
+	envs, err := ListEnvironments()
+	if err != nil {
+		return nil, err
+	}
+
+	published := make(map[int]PublishedPort)
+	for _, other := range envs {
+		if other.Name == env.Name {
+			continue
+		}
+		tmpDir, err := other.TmpDir()
+		if err != nil {
+			return nil, err
+		}
+		state, err := LoadState(tmpDir)
+		if err != nil {
+			return nil, fmt.Errorf("environment %s: %w", other.Name, err)
+		}
+		if state == nil {
+			continue
+		}
+		for owner, port := range state.HostPorts {
+			published[port] = PublishedPort{Environment: other.Name, Owner: owner}
+		}
+	}
+	return published, nil
+}
+
+// RemoveEnvironment deletes a named environment's state and generated files
+// Containers and volumes must be removed first.
+func RemoveEnvironment(name string) error {
+	if name == DefaultEnvironmentName {
+		return fmt.Errorf("the default environment can't be removed; use 'grund reset' to clean it up")
+	}
+	env, err := readEnvironment(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("environment %s not found", name)
+		}
+		return err
+	}
+
+	dir, err := env.Dir()
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove environment %s: %w", name, err)
+	}
+	return nil
+}
+
+// readEnvironment loads a named environment's state file
+func readEnvironment(name string) (*Environment, error) {
+	dir, err := (&Environment{Name: name}).Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, environmentFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var env Environment
+	if err := yaml.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("failed to parse environment %s: %w", name, err)
+	}
+	env.Name = name
+	return &env, nil
+}
+
+// saveEnvironment writes a named environment's state file
+func saveEnvironment(env *Environment) error {
+	dir, err := env.Dir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create environment directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("failed to marshal environment: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, environmentFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write environment: %w", err)
+	}
+	return nil
+}
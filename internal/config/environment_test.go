@@ -0,0 +1,173 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestEnvironmentName(t *testing.T) {
+	t.Setenv(EnvEnvironment, "")
+	if got := EnvironmentName(""); got != DefaultEnvironmentName {
+		t.Errorf("EnvironmentName(\"\") = %q, want %q", got, DefaultEnvironmentName)
+	}
+
+	t.Setenv(EnvEnvironment, "review")
+	if got := EnvironmentName(""); got != "review" {
+		t.Errorf("EnvironmentName(\"\") = %q, want review from %s", got, EnvEnvironment)
+	}
+	if got := EnvironmentName("test"); got != "test" {
+		t.Errorf("EnvironmentName(\"test\") = %q, want flag to win", got)
+	}
+}
+
+func TestEnvironment_Names(t *testing.T) {
+	def := DefaultEnvironment()
+	if def.ProjectName() != "grund" || def.NetworkName() != "grund-network" || def.ContainerName("postgres") != "grund-postgres" {
+		t.Errorf("default environment names changed: %s %s %s", def.ProjectName(), def.NetworkName(), def.ContainerName("postgres"))
+	}
+	if def.HostPort(5432) != 5432 {
+		t.Errorf("default HostPort(5432) = %d, want 5432", def.HostPort(5432))
+	}
+
+	env := &Environment{Name: "review", PortOffset: 2000}
+	if env.ProjectName() != "grund-review" {
+		t.Errorf("ProjectName() = %q, want grund-review", env.ProjectName())
+	}
+	if env.NetworkName() != "grund-review-network" {
+		t.Errorf("NetworkName() = %q, want grund-review-network", env.NetworkName())
+	}
+	if env.ContainerName("api") != "grund-review-api" {
+		t.Errorf("ContainerName(api) = %q, want grund-review-api", env.ContainerName("api"))
+	}
+	if env.HostPort(5432) != 7432 {
+		t.Errorf("HostPort(5432) = %d, want 7432", env.HostPort(5432))
+	}
+	if got := env.LocalStackEndpoint(DefaultLocalStackEndpoint); got != "http://localhost:6566" {
+		t.Errorf("LocalStackEndpoint(default) = %q, want http://localhost:6566", got)
+	}
+	if got := env.LocalStackEndpoint("http://aws.internal:4566"); got != "http://aws.internal:4566" {
+		t.Errorf("LocalStackEndpoint(custom) = %q, want unchanged", got)
+	}
+}
+
+func TestLoadEnvironment_AllocatesPortOffsets(t *testing.T) {
+	t.Setenv(EnvGrundHome, t.TempDir())
+
+	review, err := LoadEnvironment("review", true)
+	if err != nil {
+		t.Fatalf("LoadEnvironment(review) error: %v", err)
+	}
+	test, err := LoadEnvironment("test", true)
+	if err != nil {
+		t.Fatalf("LoadEnvironment(test) error: %v", err)
+	}
+	if review.PortOffset != 1000 || test.PortOffset != 2000 {
+		t.Errorf("offsets = %d, %d, want 1000, 2000", review.PortOffset, test.PortOffset)
+	}
+
+	// Loading again returns the stored offset
+	again, err := LoadEnvironment("review", true)
+	if err != nil {
+		t.Fatalf("LoadEnvironment(review) error: %v", err)
+	}
+	if again.PortOffset != 1000 {
+		t.Errorf("reloaded offset = %d, want 1000", again.PortOffset)
+	}
+
+	// A removed environment's offset is reused
+	if err := RemoveEnvironment("review"); err != nil {
+		t.Fatalf("RemoveEnvironment(review) error: %v", err)
+	}
+	next, err := LoadEnvironment("feature", true)
+	if err != nil {
+		t.Fatalf("LoadEnvironment(feature) error: %v", err)
+	}
+	if next.PortOffset != 1000 {
+		t.Errorf("feature offset = %d, want reused 1000", next.PortOffset)
+	}
+
+	envs, err := ListEnvironments()
+	if err != nil {
+		t.Fatalf("ListEnvironments() error: %v", err)
+	}
+	var names []string
+	for _, env := range envs {
+		names = append(names, env.Name)
+	}
+	if len(names) != 3 || names[0] != "default" || names[1] != "feature" || names[2] != "test" {
+		t.Errorf("ListEnvironments() = %v, want [default feature test]", names)
+	}
+}
+
+func TestOtherEnvironmentHostPorts(t *testing.T) {
+	t.Setenv(EnvGrundHome, t.TempDir())
+	review, err := LoadEnvironment("review", true)
+	if err != nil {
+		t.Fatalf("LoadEnvironment(review) error: %v", err)
+	}
+
+	for env, ports := range map[*Environment]map[string]int{
+		DefaultEnvironment(): {"web": 9080},
+		review:               {"api": 9080, "postgres": 6432},
+	} {
+		tmpDir, err := env.TmpDir()
+		if err != nil {
+			t.Fatal(err)
+		}
+		state := NewState()
+		state.HostPorts = ports
+		if err := SaveState(tmpDir, state); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := OtherEnvironmentHostPorts(DefaultEnvironment())
+	if err != nil {
+		t.Fatalf("OtherEnvironmentHostPorts() error: %v", err)
+	}
+	if len(got) != 2 || got[9080] != (PublishedPort{Environment: "review", Owner: "api"}) || got[6432].Owner != "postgres" {
+		t.Errorf("OtherEnvironmentHostPorts(default) = %v, want review's api and postgres", got)
+	}
+}
+
+func TestLoadEnvironment_Invalid(t *testing.T) {
+	t.Setenv(EnvGrundHome, t.TempDir())
+
+	for _, name := range []string{"Review", "-x", "a/b", "with space"} {
+		if _, err := LoadEnvironment(name, true); err == nil {
+			t.Errorf("LoadEnvironment(%q) expected error", name)
+		}
+	}
+}
+
+func TestLoadEnvironment_OnlyCreatesWhenAsked(t *testing.T) {
+	t.Setenv(EnvGrundHome, t.TempDir())
+
+	if _, err := LoadEnvironment("stagng", false); err == nil {
+		t.Fatal("Expected error loading an unknown environment")
+	}
+	envs, _ := ListEnvironments()
+	if len(envs) != 1 {
+		t.Errorf("ListEnvironments() = %d environments, want only the default", len(envs))
+	}
+
+	if _, err := LoadEnvironment("staging", true); err != nil {
+		t.Fatalf("LoadEnvironment(staging, true) error: %v", err)
+	}
+	if env, err := LoadEnvironment("staging", false); err != nil || env.PortOffset != 1000 {
+		t.Errorf("LoadEnvironment(staging, false) = %+v, %v; want the created environment", env, err)
+	}
+	if env, err := LoadEnvironment(DefaultEnvironmentName, false); err != nil || !env.IsDefault() {
+		t.Errorf("LoadEnvironment(default, false) = %+v, %v; want the default environment", env, err)
+	}
+}
+
+func TestRemoveEnvironment_Errors(t *testing.T) {
+	t.Setenv(EnvGrundHome, t.TempDir())
+
+	if err := RemoveEnvironment(DefaultEnvironmentName); err == nil {
+		t.Error("Expected error removing the default environment")
+	}
+	if err := RemoveEnvironment("missing"); err == nil {
+		t.Error("Expected error removing an unknown environment")
+	}
+}
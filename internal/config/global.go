@@ -43,6 +43,9 @@ type GlobalConfig struct {
 
 	// LocalStack configuration (optional, defaults applied if not set)
 	LocalStack *LocalStackConfig `yaml:"localstack,omitempty"`
+
+	// Secrets configures secret backends (optional, defaults to secrets.env and the shell environment)
+	Secrets *SecretsConfig `yaml:"secrets,omitempty"`
 }
 
 // ServiceEntry represents a service in the registry
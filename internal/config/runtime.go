@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Container runtime names
+const (
+	RuntimeDocker  = "docker"
+	RuntimePodman  = "podman"
+	RuntimeNerdctl = "nerdctl"
+)
+
+// ContainerRuntime describes the engine behind the configured compose command
+// and the quirks generated compose files and engine commands must account for.
+type ContainerRuntime struct {
+	Name    string   // docker, podman or nerdctl
+	Engine  string   // CLI for non-compose commands (exec, ps)
+	Compose []string // compose command and its leading arguments, e.g. [podman compose]
+
+	// HostGateway is the hostname containers use to reach the host
+	HostGateway string
+	// HostGatewayMapping adds an explicit "<gateway>:host-gateway" extra_hosts entry
+	// (Docker on Linux needs it; Podman resolves host.containers.internal itself)
+	HostGatewayMapping bool
+
+	// Socket is the engine API socket on the host, mounted into LocalStack so it can
+	// start containers (DOCKER_HOST). Empty when the engine has no Docker-compatible API.
+	Socket string
+}
+
+// DetectRuntime derives the runtime from a compose command such as "docker compose",
+// "docker-compose", "podman compose", "podman-compose" or "nerdctl compose"
+func DetectRuntime(composeCommand string) (*ContainerRuntime, error) {
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		runtimeDir = fmt.Sprintf("/run/user/%d", os.Getuid())
+	}
+	return detectRuntime(composeCommand, os.Getuid(), runtimeDir)
+}
+
+// DefaultRuntime returns the runtime for the default "docker compose" command
+func DefaultRuntime() *ContainerRuntime {
+	rt, _ := detectRuntime(DefaultDockerComposeCommand, 0, "")
+	return rt
+}
+
+// detectRuntime is DetectRuntime with the user's uid and runtime directory passed in
+func detectRuntime(composeCommand string, uid int, runtimeDir string) (*ContainerRuntime, error) {
+	fields := strings.Fields(composeCommand)
+	if len(fields) == 0 {
+		fields = strings.Fields(DefaultDockerComposeCommand)
+	}
+
+	binary := filepath.Base(fields[0])
+	switch {
+	case binary == "podman" || binary == "podman-compose":
+		// Rootless Podman serves its API from the user's runtime directory
+		socket := "/run/podman/podman.sock"
+		if uid != 0 {
+			socket = filepath.Join(runtimeDir, "podman", "podman.sock")
+		}
+		return &ContainerRuntime{
+			Name:        RuntimePodman,
+			Engine:      "podman",
+			Compose:     fields,
+			HostGateway: "host.containers.internal",
+			Socket:      socket,
+		}, nil
+
+	case binary == "nerdctl":
+		// containerd doesn't speak the Docker API, so LocalStack gets no socket
+		return &ContainerRuntime{
+			Name:               RuntimeNerdctl,
+			Engine:             "nerdctl",
+			Compose:            fields,
+			HostGateway:        "host.docker.internal",
+			HostGatewayMapping: true,
+		}, nil
+
+	case binary == "docker" || binary == "docker-compose":
+		return &ContainerRuntime{
+			Name:               RuntimeDocker,
+			Engine:             "docker",
+			Compose:            fields,
+			HostGateway:        "host.docker.internal",
+			HostGatewayMapping: true,
+			Socket:             "/var/run/docker.sock",
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported docker.compose_command %q (use docker compose, docker-compose, podman compose, podman-compose or nerdctl compose)", composeCommand)
+}
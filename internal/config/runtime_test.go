@@ -0,0 +1,54 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectRuntime(t *testing.T) {
+	tests := []struct {
+		command     string
+		uid         int
+		wantName    string
+		wantEngine  string
+		wantCompose string
+		wantGateway string
+		wantMapping bool
+		wantSocket  string
+	}{
+		{"docker compose", 1000, RuntimeDocker, "docker", "docker compose", "host.docker.internal", true, "/var/run/docker.sock"},
+		{"docker-compose", 1000, RuntimeDocker, "docker", "docker-compose", "host.docker.internal", true, "/var/run/docker.sock"},
+		{"/usr/local/bin/docker compose", 1000, RuntimeDocker, "docker", "/usr/local/bin/docker compose", "host.docker.internal", true, "/var/run/docker.sock"},
+		{"podman compose", 1000, RuntimePodman, "podman", "podman compose", "host.containers.internal", false, "/run/user/1000/podman/podman.sock"},
+		{"podman-compose", 0, RuntimePodman, "podman", "podman-compose", "host.containers.internal", false, "/run/podman/podman.sock"},
+		{"nerdctl compose", 1000, RuntimeNerdctl, "nerdctl", "nerdctl compose", "host.docker.internal", true, ""},
+		{"", 1000, RuntimeDocker, "docker", "docker compose", "host.docker.internal", true, "/var/run/docker.sock"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			rt, err := detectRuntime(tt.command, tt.uid, "/run/user/1000")
+			if err != nil {
+				t.Fatalf("detectRuntime() error: %v", err)
+			}
+			if rt.Name != tt.wantName || rt.Engine != tt.wantEngine {
+				t.Errorf("runtime = %s/%s, want %s/%s", rt.Name, rt.Engine, tt.wantName, tt.wantEngine)
+			}
+			if got := strings.Join(rt.Compose, " "); got != tt.wantCompose {
+				t.Errorf("Compose = %q, want %q", got, tt.wantCompose)
+			}
+			if rt.HostGateway != tt.wantGateway || rt.HostGatewayMapping != tt.wantMapping {
+				t.Errorf("host gateway = %s (mapping %v), want %s (mapping %v)", rt.HostGateway, rt.HostGatewayMapping, tt.wantGateway, tt.wantMapping)
+			}
+			if rt.Socket != tt.wantSocket {
+				t.Errorf("Socket = %q, want %q", rt.Socket, tt.wantSocket)
+			}
+		})
+	}
+}
+
+func TestDetectRuntime_Unsupported(t *testing.T) {
+	if _, err := DetectRuntime("kubectl"); err == nil {
+		t.Error("Expected error for unsupported compose command")
+	}
+}
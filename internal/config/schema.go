@@ -41,8 +41,10 @@ type BuildConfig struct {
 }
 
 type RunConfig struct {
-	Command   string `yaml:"command"`
-	HotReload bool   `yaml:"hot_reload"`
+	Command   string   `yaml:"command"`
+	HotReload bool     `yaml:"hot_reload"`
+	Workdir   string   `yaml:"workdir,omitempty"`
+	Ignore    []string `yaml:"ignore,omitempty"`
 }
 
 type HealthConfig struct {
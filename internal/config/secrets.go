@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Secret backend types
+const (
+	SecretBackendFile = "file" // plaintext dotenv file
+	SecretBackendEnv  = "env"  // shell environment
+	SecretBackendSops = "sops" // sops-encrypted file
+	SecretBackendAge  = "age"  // age-encrypted dotenv file
+	SecretBackendPass = "pass" // the pass password store
+	SecretBackendExec = "exec" // any command printing the value, e.g. op read
+)
+
+// DefaultSecretsFile is the plaintext secrets file used when no backends are configured
+const DefaultSecretsFile = "~/.grund/secrets.env"
+
+// SecretsConfig configures where secret values come from
+type SecretsConfig struct {
+	// Backends are tried in order; the first one that has a secret supplies it
+	Backends []SecretBackendConfig `yaml:"backends,omitempty"`
+}
+
+// SecretBackendConfig configures a single secret backend
+type SecretBackendConfig struct {
+	// Type is file, env, sops, age, pass or exec
+	Type string `yaml:"type"`
+
+	// Name labels the backend in grund secrets list (default: the type)
+	Name string `yaml:"name,omitempty"`
+
+	// Path is the secrets file (file, sops, age)
+	Path string `yaml:"path,omitempty"`
+
+	// Identity is the age identity file used to decrypt (age)
+	Identity string `yaml:"identity,omitempty"`
+
+	// Prefix is prepended to secret names in the store, e.g. "grund/" (pass)
+	Prefix string `yaml:"prefix,omitempty"`
+
+	// Command prints a secret's value; {name} is replaced by the secret name (exec)
+	Command string `yaml:"command,omitempty"`
+
+	// NotFoundExitCode is the exit code Command uses for a missing secret (exec)
+	// Other non-zero exits, like an expired session, are then errors. Unset, every
+	// non-zero exit counts as not found and is reported as a warning.
+	NotFoundExitCode int `yaml:"not_found_exit_code,omitempty"`
+}
+
+// DisplayName returns the backend's name, defaulting to its type
+func (b SecretBackendConfig) DisplayName() string {
+	if b.Name != "" {
+		return b.Name
+	}
+	return b.Type
+}
+
+// Validate checks that the backend has the settings its type needs
+func (b SecretBackendConfig) Validate() error {
+	switch b.Type {
+	case SecretBackendEnv, SecretBackendPass:
+		return nil
+	case SecretBackendFile, SecretBackendSops:
+		if b.Path == "" {
+			return fmt.Errorf("%s secret backend requires path", b.Type)
+		}
+	case SecretBackendAge:
+		if b.Path == "" || b.Identity == "" {
+			return fmt.Errorf("age secret backend requires path and identity")
+		}
+	case SecretBackendExec:
+		if !strings.Contains(b.Command, "{name}") {
+			return fmt.Errorf("exec secret backend requires a command containing {name}")
+		}
+		if b.NotFoundExitCode < 0 || b.NotFoundExitCode > 255 {
+			return fmt.Errorf("exec secret backend not_found_exit_code must be between 1 and 255, got %d", b.NotFoundExitCode)
+		}
+	default:
+		return fmt.Errorf("unknown secret backend type %q (expected file, env, sops, age, pass or exec)", b.Type)
+	}
+	return nil
+}
+
+// DefaultSecretBackends reads ~/.grund/secrets.env, then the shell environment
+func DefaultSecretBackends() []SecretBackendConfig {
+	return []SecretBackendConfig{
+		{Type: SecretBackendFile, Path: DefaultSecretsFile},
+		{Type: SecretBackendEnv},
+	}
+}
+
+// GetSecretBackends returns the configured secret backends, or the defaults
+// File paths have ~ expanded.
+func (c *GlobalConfig) GetSecretBackends() ([]SecretBackendConfig, error) {
+	backends := DefaultSecretBackends()
+	if c.Secrets != nil && len(c.Secrets.Backends) > 0 {
+		backends = c.Secrets.Backends
+	}
+
+	resolved := make([]SecretBackendConfig, len(backends))
+	for i, b := range backends {
+		if err := b.Validate(); err != nil {
+			return nil, fmt.Errorf("secrets.backends[%d]: %w", i, err)
+		}
+		b.Path = expandPath(b.Path)
+		b.Identity = expandPath(b.Identity)
+		resolved[i] = b
+	}
+	return resolved, nil
+}
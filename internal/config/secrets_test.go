@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGetSecretBackends_Defaults(t *testing.T) {
+	backends, err := (&GlobalConfig{}).GetSecretBackends()
+	if err != nil {
+		t.Fatalf("GetSecretBackends() error: %v", err)
+	}
+
+	home, _ := os.UserHomeDir()
+	if len(backends) != 2 {
+		t.Fatalf("got %d backends, want 2", len(backends))
+	}
+	if backends[0].Type != SecretBackendFile || backends[0].Path != filepath.Join(home, ".grund", "secrets.env") {
+		t.Errorf("backends[0] = %+v, want the default secrets file", backends[0])
+	}
+	if backends[1].Type != SecretBackendEnv {
+		t.Errorf("backends[1] = %+v, want env", backends[1])
+	}
+}
+
+func TestGetSecretBackends_Configured(t *testing.T) {
+	data := `
+secrets:
+  backends:
+    - type: sops
+      path: ~/work/secrets.enc.yaml
+    - type: age
+      path: /secrets/dev.env.age
+      identity: ~/.config/age/key.txt
+    - type: exec
+      name: 1password
+      command: op read op://vault/{name}
+`
+	var cfg GlobalConfig
+	if err := yaml.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	backends, err := cfg.GetSecretBackends()
+	if err != nil {
+		t.Fatalf("GetSecretBackends() error: %v", err)
+	}
+
+	home, _ := os.UserHomeDir()
+	if len(backends) != 3 {
+		t.Fatalf("got %d backends, want 3", len(backends))
+	}
+	if backends[0].Path != filepath.Join(home, "work", "secrets.enc.yaml") {
+		t.Errorf("sops path = %q, want ~ expanded", backends[0].Path)
+	}
+	if backends[1].Identity != filepath.Join(home, ".config", "age", "key.txt") {
+		t.Errorf("age identity = %q, want ~ expanded", backends[1].Identity)
+	}
+	if backends[2].DisplayName() != "1password" || backends[1].DisplayName() != "age" {
+		t.Errorf("DisplayName() = %q, %q", backends[2].DisplayName(), backends[1].DisplayName())
+	}
+}
+
+func TestGetSecretBackends_Invalid(t *testing.T) {
+	cfg := &GlobalConfig{Secrets: &SecretsConfig{Backends: []SecretBackendConfig{
+		{Type: SecretBackendEnv},
+		{Type: SecretBackendExec, Command: "op read op://vault/API_KEY"},
+	}}}
+
+	_, err := cfg.GetSecretBackends()
+	if err == nil || !strings.Contains(err.Error(), "secrets.backends[1]") {
+		t.Errorf("GetSecretBackends() error = %v, want secrets.backends[1]", err)
+	}
+}
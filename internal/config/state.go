@@ -0,0 +1,117 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// stateFile is the manifest grund up keeps in an environment's tmp directory
+const stateFile = "state.yaml"
+
+// State records the services whose generated compose files are active
+// Compose directories in the tmp directory that aren't listed are left over from
+// services that were renamed or removed, and are ignored until grund prune deletes them.
+type State struct {
+	Services map[string]ServiceState `yaml:"services"`
+
+	// HostPorts and Local record the host port each service was last generated with and
+	// which ran on the host, so regenerating a few services keeps the rest of the layout
+	HostPorts map[string]int `yaml:"host_ports,omitempty"`
+	Local     []string       `yaml:"local,omitempty"`
+}
+
+// ServiceState records when a service's compose file was generated and what it last started from
+type ServiceState struct {
+	ConfigHash  string    `yaml:"config_hash,omitempty"` // sha256 of the compose file the container last started from
+	BuildHash   string    `yaml:"build_hash,omitempty"`  // sha256 of the build context its image was last built from
+	GeneratedAt time.Time `yaml:"generated_at"`
+}
+
+// NewState returns an empty manifest
+func NewState() *State {
+	return &State{Services: make(map[string]ServiceState)}
+}
+
+// Has reports whether the service's compose file is active
+func (s *State) Has(name string) bool {
+	_, ok := s.Services[name]
+	return ok
+}
+
+// IsLocal reports whether the service was last run on the host
+func (s *State) IsLocal(name string) bool {
+	return slices.Contains(s.Local, name)
+}
+
+// SetLocal records whether the service runs on the host
+func (s *State) SetLocal(name string, local bool) {
+	s.Local = slices.DeleteFunc(s.Local, func(n string) bool { return n == name })
+	if local {
+		s.Local = append(s.Local, name)
+		slices.Sort(s.Local)
+	}
+}
+
+// Forget drops everything recorded about a service
+func (s *State) Forget(name string) {
+	delete(s.Services, name)
+	delete(s.HostPorts, name)
+	s.SetLocal(name, false)
+}
+
+// HashConfig returns the hash recorded for a generated compose file
+func HashConfig(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadState reads the manifest in tmpDir
+// It returns nil, without an error, when grund up hasn't written one yet.
+func LoadState(tmpDir string) (*State, error) {
+	data, err := os.ReadFile(filepath.Join(tmpDir, stateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read state: %w", err)
+	}
+
+	state := NewState()
+	if err := yaml.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Join(tmpDir, stateFile), err)
+	}
+	if state.Services == nil {
+		state.Services = make(map[string]ServiceState)
+	}
+	return state, nil
+}
+
+// SaveState writes the manifest in tmpDir, replacing it atomically
+func SaveState(tmpDir string, state *State) error {
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return fmt.Errorf("failed to create tmp directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	path := filepath.Join(tmpDir, stateFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	return nil
+}
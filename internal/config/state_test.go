@@ -0,0 +1,51 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestState_SaveAndLoad(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	state, err := LoadState(tmpDir)
+	if err != nil || state != nil {
+		t.Fatalf("LoadState() without a manifest = %v, %v; want nil, nil", state, err)
+	}
+
+	state = NewState()
+	generated := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	state.Services["orders"] = ServiceState{ConfigHash: HashConfig([]byte("services: {}\n")), GeneratedAt: generated}
+	if err := SaveState(tmpDir, state); err != nil {
+		t.Fatalf("SaveState() error: %v", err)
+	}
+
+	loaded, err := LoadState(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadState() error: %v", err)
+	}
+	if !loaded.Has("orders") || loaded.Has("billing") {
+		t.Errorf("loaded services = %v, want only orders", loaded.Services)
+	}
+	if got := loaded.Services["orders"]; got.ConfigHash != state.Services["orders"].ConfigHash || !got.GeneratedAt.Equal(generated) {
+		t.Errorf("orders = %+v, want %+v", got, state.Services["orders"])
+	}
+}
+
+func TestState_LocalAndForget(t *testing.T) {
+	state := NewState()
+	state.Services["orders"] = ServiceState{}
+	state.HostPorts = map[string]int{"orders": 8080, "billing": 8081}
+	state.SetLocal("orders", true)
+	state.SetLocal("billing", true)
+	state.SetLocal("orders", true)
+	if len(state.Local) != 2 || !state.IsLocal("orders") || !state.IsLocal("billing") {
+		t.Fatalf("Local = %v, want billing and orders once each", state.Local)
+	}
+
+	state.SetLocal("billing", false)
+	state.Forget("orders")
+	if state.Has("orders") || state.IsLocal("orders") || state.IsLocal("billing") || len(state.HostPorts) != 1 {
+		t.Errorf("state after Forget = %+v, want only billing's host port", state)
+	}
+}
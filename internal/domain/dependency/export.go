@@ -0,0 +1,391 @@
+package dependency
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+// sqsEndpointPattern matches a subscription endpoint that refers to a queue, e.g. ${sqs.orders.arn}
+var sqsEndpointPattern = regexp.MustCompile(`^\$\{sqs\.([^.}]+)\.(arn|url)\}$`)
+
+// Annotations summarises a service's infrastructure needs, e.g. "postgres: users",
+// "sqs: jobs (dlq)" or "sns: events → jobs"
+func Annotations(svc *service.Service) []string {
+	infra := svc.Dependencies.Infrastructure
+	var notes []string
+
+	if infra.Postgres != nil {
+		notes = append(notes, "postgres: "+infra.Postgres.Database)
+	}
+	if infra.MongoDB != nil {
+		notes = append(notes, "mongodb: "+infra.MongoDB.Database)
+	}
+	if infra.Redis != nil {
+		notes = append(notes, "redis")
+	}
+	if infra.SQS != nil {
+		for _, q := range infra.SQS.Queues {
+			note := "sqs: " + q.Name
+			if q.DLQ {
+				note += " (dlq)"
+			}
+			notes = append(notes, note)
+		}
+	}
+	if infra.SNS != nil {
+		for _, topic := range infra.SNS.Topics {
+			note := "sns: " + topic.Name
+			var targets []string
+			for _, sub := range topic.Subscriptions {
+				targets = append(targets, subscriptionTarget(sub).label)
+			}
+			if len(targets) > 0 {
+				note += " → " + strings.Join(targets, ", ")
+			}
+			notes = append(notes, note)
+		}
+	}
+	if infra.S3 != nil {
+		for _, bucket := range infra.S3.Buckets {
+			notes = append(notes, "s3: "+bucket.Name)
+		}
+	}
+
+	return notes
+}
+
+// RenderTree draws roots and their dependencies as an ASCII tree
+// A service already drawn is not expanded again, and a dependency leading back
+// to a service on the current path is marked as a cycle.
+func (g *Graph) RenderTree(roots []service.ServiceName) string {
+	var b strings.Builder
+	expanded := make(map[service.ServiceName]bool)
+	onPath := make(map[service.ServiceName]bool)
+
+	var draw func(name service.ServiceName, prefix, branch, childPrefix string)
+	draw = func(name service.ServiceName, prefix, branch, childPrefix string) {
+		b.WriteString(prefix + branch + name.String())
+
+		switch {
+		case onPath[name]:
+			b.WriteString("  ↻ cycle\n")
+			return
+		case expanded[name]:
+			b.WriteString("  (see above)\n")
+			return
+		}
+
+		node, ok := g.nodes[name]
+		if !ok {
+			b.WriteString("  (not found)\n")
+			return
+		}
+		if notes := Annotations(node.Service); len(notes) > 0 {
+			b.WriteString("  [" + strings.Join(notes, " · ") + "]")
+		}
+		b.WriteString("\n")
+
+		expanded[name] = true
+		onPath[name] = true
+		for i, dep := range node.Dependencies {
+			if i == len(node.Dependencies)-1 {
+				draw(dep, prefix+childPrefix, "└── ", "    ")
+			} else {
+				draw(dep, prefix+childPrefix, "├── ", "│   ")
+			}
+		}
+		onPath[name] = false
+	}
+
+	for _, root := range roots {
+		draw(root, "", "", "")
+	}
+	return b.String()
+}
+
+// Roots returns the services nothing else in the graph depends on
+// Cycles no root reaches have no such service, so the first member of each
+// cycle not reachable from a root or an earlier cycle is added as well.
+func (g *Graph) Roots() []service.ServiceName {
+	reached := make(map[service.ServiceName]bool)
+	var reach func(name service.ServiceName)
+	reach = func(name service.ServiceName) {
+		if reached[name] {
+			return
+		}
+		reached[name] = true
+		for _, dep := range g.knownDependencies(name) {
+			reach(dep)
+		}
+	}
+
+	var roots []service.ServiceName
+	for _, name := range g.Names() {
+		if len(g.nodes[name].Dependents) == 0 {
+			roots = append(roots, name)
+			reach(name)
+		}
+	}
+
+	// Components come after those they depend on, so walking them backwards
+	// visits a cycle before the cycles it leads to.
+	components, _ := stronglyConnected(g.Names(), g.knownDependencies)
+	var unreached []service.ServiceName
+	for i := len(components) - 1; i >= 0; i-- {
+		members := components[i]
+		if reached[members[0]] {
+			continue
+		}
+		first := members[0]
+		for _, name := range members[1:] {
+			if name < first {
+				first = name
+			}
+		}
+		unreached = append(unreached, first)
+		reach(first)
+	}
+	sort.Slice(unreached, func(i, j int) bool { return unreached[i] < unreached[j] })
+
+	return append(roots, unreached...)
+}
+
+// exportNode is a service or infrastructure resource in an exported diagram
+type exportNode struct {
+	id    string
+	label string
+	kind  string // service, postgres, mongodb, redis, sqs, sns, s3, endpoint
+	cycle bool
+}
+
+// exportEdge connects two export nodes
+type exportEdge struct {
+	from, to string
+	label    string
+	kind     string // depends, uses, subscription
+	cycle    bool
+}
+
+// exportModel flattens the graph into nodes and edges, ordered by service name
+// Infrastructure is drawn as shared nodes so queues, topics and their
+// subscriptions connect the services that use them.
+func (g *Graph) exportModel() ([]exportNode, []exportEdge) {
+	inCycle := make(map[service.ServiceName]int)
+	for i, members := range g.Cycles() {
+		for _, name := range members {
+			inCycle[name] = i + 1
+		}
+	}
+
+	var nodes []exportNode
+	var edges []exportEdge
+	infraNodes := make(map[string]exportNode)
+	addInfra := func(n exportNode) {
+		if _, ok := infraNodes[n.id]; !ok {
+			infraNodes[n.id] = n
+		}
+	}
+
+	for _, name := range g.Names() {
+		node := g.nodes[name]
+		nodes = append(nodes, exportNode{id: name.String(), label: name.String(), kind: "service", cycle: inCycle[name] > 0})
+
+		for _, dep := range node.Dependencies {
+			edges = append(edges, exportEdge{
+				from:  name.String(),
+				to:    dep.String(),
+				kind:  "depends",
+				cycle: inCycle[name] > 0 && inCycle[name] == inCycle[dep],
+			})
+		}
+
+		infra := node.Service.Dependencies.Infrastructure
+		uses := func(n exportNode, label string) {
+			addInfra(n)
+			edges = append(edges, exportEdge{from: name.String(), to: n.id, label: label, kind: "uses"})
+		}
+		if infra.Postgres != nil {
+			uses(exportNode{id: "postgres", label: "postgres", kind: "postgres"}, infra.Postgres.Database)
+		}
+		if infra.MongoDB != nil {
+			uses(exportNode{id: "mongodb", label: "mongodb", kind: "mongodb"}, infra.MongoDB.Database)
+		}
+		if infra.Redis != nil {
+			uses(exportNode{id: "redis", label: "redis", kind: "redis"}, "")
+		}
+		if infra.SQS != nil {
+			for _, q := range infra.SQS.Queues {
+				uses(queueNode(q.Name), "")
+			}
+		}
+		if infra.S3 != nil {
+			for _, bucket := range infra.S3.Buckets {
+				uses(exportNode{id: "s3:" + bucket.Name, label: "s3: " + bucket.Name, kind: "s3"}, "")
+			}
+		}
+		if infra.SNS != nil {
+			for _, topic := range infra.SNS.Topics {
+				topicNode := exportNode{id: "sns:" + topic.Name, label: "sns: " + topic.Name, kind: "sns"}
+				uses(topicNode, "")
+				for _, sub := range topic.Subscriptions {
+					target := subscriptionTarget(sub)
+					addInfra(target)
+					edges = append(edges, exportEdge{from: topicNode.id, to: target.id, label: sub.Protocol, kind: "subscription"})
+				}
+			}
+		}
+	}
+
+	infraIDs := make([]string, 0, len(infraNodes))
+	for id := range infraNodes {
+		infraIDs = append(infraIDs, id)
+	}
+	sort.Strings(infraIDs)
+	for _, id := range infraIDs {
+		nodes = append(nodes, infraNodes[id])
+	}
+
+	return nodes, edges
+}
+
+func queueNode(name string) exportNode {
+	return exportNode{id: "sqs:" + name, label: "sqs: " + name, kind: "sqs"}
+}
+
+// subscriptionTarget returns the node an SNS subscription delivers to
+func subscriptionTarget(sub infrastructure.SubscriptionConfig) exportNode {
+	if m := sqsEndpointPattern.FindStringSubmatch(sub.Endpoint); m != nil {
+		return queueNode(m[1])
+	}
+	return exportNode{id: sub.Protocol + ":" + sub.Endpoint, label: sub.Protocol + ": " + sub.Endpoint, kind: "endpoint"}
+}
+
+// dotShapes maps node kinds to Graphviz shapes
+var dotShapes = map[string]string{
+	"service":  "box",
+	"postgres": "cylinder",
+	"mongodb":  "cylinder",
+	"redis":    "cylinder",
+	"sqs":      "cds",
+	"sns":      "hexagon",
+	"s3":       "folder",
+	"endpoint": "note",
+}
+
+// RenderDOT renders the graph as a Graphviz digraph
+// Services and dependencies that form a cycle are drawn in red.
+func (g *Graph) RenderDOT() string {
+	nodes, edges := g.exportModel()
+
+	var b strings.Builder
+	b.WriteString("digraph grund {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [fontname=\"Helvetica\"];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=10];\n\n")
+
+	for _, n := range nodes {
+		attrs := []string{fmt.Sprintf("label=%q", n.label), "shape=" + dotShapes[n.kind]}
+		if n.kind == "service" {
+			attrs = append(attrs, "style=rounded")
+		}
+		if n.cycle {
+			attrs = append(attrs, "color=red", "fontcolor=red")
+		}
+		fmt.Fprintf(&b, "  %q [%s];\n", n.id, strings.Join(attrs, ", "))
+	}
+	b.WriteString("\n")
+
+	for _, e := range edges {
+		var attrs []string
+		switch e.kind {
+		case "uses":
+			attrs = append(attrs, "style=dashed")
+		case "subscription":
+			attrs = append(attrs, "style=dotted")
+		}
+		if e.label != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%q", e.label))
+		}
+		if e.cycle {
+			attrs = append(attrs, "color=red")
+		}
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, "  %q -> %q [%s];\n", e.from, e.to, strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(&b, "  %q -> %q;\n", e.from, e.to)
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// mermaidID turns a node id into a Mermaid-safe identifier
+var mermaidUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+func mermaidID(id string) string {
+	return "n_" + mermaidUnsafe.ReplaceAllString(id, "_")
+}
+
+// mermaidShapes maps node kinds to Mermaid shape delimiters
+var mermaidShapes = map[string][2]string{
+	"service":  {"(", ")"},
+	"postgres": {"[(", ")]"},
+	"mongodb":  {"[(", ")]"},
+	"redis":    {"[(", ")]"},
+	"sqs":      {">", "]"},
+	"sns":      {"{{", "}}"},
+	"s3":       {"[/", "/]"},
+	"endpoint": {"[", "]"},
+}
+
+// RenderMermaid renders the graph as a Mermaid flowchart
+// Services and dependencies that form a cycle are styled in red.
+func (g *Graph) RenderMermaid() string {
+	nodes, edges := g.exportModel()
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	var cycleNodes []string
+	for _, n := range nodes {
+		shape := mermaidShapes[n.kind]
+		fmt.Fprintf(&b, "  %s%s%q%s\n", mermaidID(n.id), shape[0], n.label, shape[1])
+		if n.cycle {
+			cycleNodes = append(cycleNodes, mermaidID(n.id))
+		}
+	}
+
+	var cycleEdges []string
+	for i, e := range edges {
+		arrow := "-->"
+		switch e.kind {
+		case "uses":
+			arrow = "-.->"
+		case "subscription":
+			arrow = "-.->"
+		}
+		if e.label != "" {
+			arrow += "|" + e.label + "|"
+		}
+		fmt.Fprintf(&b, "  %s %s %s\n", mermaidID(e.from), arrow, mermaidID(e.to))
+		if e.cycle {
+			cycleEdges = append(cycleEdges, fmt.Sprint(i))
+		}
+	}
+
+	if len(cycleNodes) > 0 {
+		b.WriteString("  classDef cycle stroke:#d33,stroke-width:2px,color:#d33\n")
+		fmt.Fprintf(&b, "  class %s cycle\n", strings.Join(cycleNodes, ","))
+	}
+	if len(cycleEdges) > 0 {
+		fmt.Fprintf(&b, "  linkStyle %s stroke:#d33,stroke-width:2px\n", strings.Join(cycleEdges, ","))
+	}
+
+	return b.String()
+}
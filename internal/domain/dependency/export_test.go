@@ -0,0 +1,145 @@
+package dependency
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+// newExportGraph builds orders -> users -> auth, with orders also depending on auth
+// orders owns a queue that users' topic delivers to.
+func newExportGraph(t *testing.T, extra ...string) *Graph {
+	t.Helper()
+
+	orders := createTestService("orders", []string{"users", "auth"})
+	orders.Dependencies.Infrastructure = infrastructure.InfrastructureRequirements{
+		Postgres: &infrastructure.PostgresConfig{Database: "orders_db"},
+		SQS:      &infrastructure.SQSConfig{Queues: []infrastructure.QueueConfig{{Name: "order-jobs", DLQ: true}}},
+	}
+
+	users := createTestService("users", []string{"auth"})
+	users.Dependencies.Infrastructure = infrastructure.InfrastructureRequirements{
+		SNS: &infrastructure.SNSConfig{Topics: []infrastructure.TopicConfig{{
+			Name:          "user-events",
+			Subscriptions: []infrastructure.SubscriptionConfig{{Protocol: "sqs", Endpoint: "${sqs.order-jobs.arn}"}},
+		}}},
+	}
+
+	auth := createTestService("auth", extra)
+	auth.Dependencies.Infrastructure = infrastructure.InfrastructureRequirements{Redis: &infrastructure.RedisConfig{}}
+
+	graph := NewGraph()
+	graph.AddService(orders)
+	graph.AddService(users)
+	graph.AddService(auth)
+	if err := graph.Build(); err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	return graph
+}
+
+func TestAnnotations(t *testing.T) {
+	graph := newExportGraph(t)
+	node, _ := graph.GetNode("users")
+
+	got := strings.Join(Annotations(node.Service), " | ")
+	if got != "sns: user-events → sqs: order-jobs" {
+		t.Errorf("Annotations(users) = %q", got)
+	}
+}
+
+func TestGraph_RenderTree(t *testing.T) {
+	graph := newExportGraph(t)
+
+	want := `orders  [postgres: orders_db · sqs: order-jobs (dlq)]
+├── users  [sns: user-events → sqs: order-jobs]
+│   └── auth  [redis]
+└── auth  (see above)
+`
+	if got := graph.RenderTree(graph.Roots()); got != want {
+		t.Errorf("RenderTree() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGraph_Roots_UnreachableCycles(t *testing.T) {
+	// api stands alone; billing ⇄ ledger and queue ⇄ worker have no root, and
+	// billing's cycle leads to the worker cycle, which needs no root of its own
+	graph := NewGraph()
+	graph.AddService(createTestService("api", nil))
+	graph.AddService(createTestService("billing", []string{"ledger"}))
+	graph.AddService(createTestService("ledger", []string{"billing", "worker"}))
+	graph.AddService(createTestService("worker", []string{"queue"}))
+	graph.AddService(createTestService("queue", []string{"worker"}))
+	graph.AddService(createTestService("cron", []string{"cron"}))
+	if err := graph.Build(); err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	if got := fmt.Sprint(graph.Roots()); got != "[api billing cron]" {
+		t.Errorf("Roots() = %s, want [api billing cron]", got)
+	}
+	if tree := graph.RenderTree(graph.Roots()); !strings.Contains(tree, "queue") {
+		t.Errorf("RenderTree() should draw every service:\n%s", tree)
+	}
+}
+
+func TestGraph_RenderTree_Cycle(t *testing.T) {
+	graph := newExportGraph(t, "orders")
+
+	got := graph.RenderTree([]service.ServiceName{"orders"})
+	if !strings.Contains(got, "│   └── auth  [redis]\n│       └── orders  ↻ cycle\n") {
+		t.Errorf("RenderTree() should mark the cycle back to orders:\n%s", got)
+	}
+}
+
+func TestGraph_RenderDOT(t *testing.T) {
+	graph := newExportGraph(t, "orders")
+	dot := graph.RenderDOT()
+
+	for _, want := range []string{
+		`"orders" [label="orders", shape=box, style=rounded, color=red, fontcolor=red];`,
+		`"orders" -> "users" [color=red];`,
+		`"orders" -> "postgres" [style=dashed, label="orders_db"];`,
+		`"sqs:order-jobs" [label="sqs: order-jobs", shape=cds];`,
+		`"sns:user-events" -> "sqs:order-jobs" [style=dotted, label="sqs"];`,
+		`"auth" -> "redis" [style=dashed];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("RenderDOT() missing %s\n%s", want, dot)
+		}
+	}
+	if strings.Count(dot, `"sqs:order-jobs" [label=`) != 1 {
+		t.Errorf("shared queue should be declared once:\n%s", dot)
+	}
+}
+
+func TestGraph_RenderMermaid(t *testing.T) {
+	graph := newExportGraph(t)
+	mermaid := graph.RenderMermaid()
+
+	for _, want := range []string{
+		"flowchart LR\n",
+		`n_orders("orders")`,
+		`n_postgres[("postgres")]`,
+		`n_sqs_order_jobs>"sqs: order-jobs"]`,
+		`n_sns_user_events{{"sns: user-events"}}`,
+		"n_orders --> n_users",
+		"n_orders -.->|orders_db| n_postgres",
+		"n_sns_user_events -.->|sqs| n_sqs_order_jobs",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("RenderMermaid() missing %s\n%s", want, mermaid)
+		}
+	}
+	if strings.Contains(mermaid, "classDef cycle") {
+		t.Errorf("acyclic graph should not style cycles:\n%s", mermaid)
+	}
+
+	cyclic := newExportGraph(t, "orders").RenderMermaid()
+	if !strings.Contains(cyclic, "class n_auth,n_orders,n_users cycle") || !strings.Contains(cyclic, "linkStyle ") {
+		t.Errorf("RenderMermaid() should highlight the cycle:\n%s", cyclic)
+	}
+}
@@ -2,6 +2,7 @@ package dependency
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/vivekkundariya/grund/internal/domain/service"
@@ -222,3 +223,159 @@ func (g *Graph) TopologicalSort(startServices []service.ServiceName) ([]service.
 
 	return result, nil
 }
+
+// StartupWaves groups services into waves that can be started in parallel
+// Only dependencies with a wait_for condition order startup, and only between the
+// given services. Every service comes after the waves holding the services it waits
+// for; services waiting on each other in a cycle share a wave.
+func (g *Graph) StartupWaves(names []service.ServiceName) [][]service.ServiceName {
+	inSet := make(map[service.ServiceName]bool, len(names))
+	for _, name := range names {
+		inSet[name] = true
+	}
+
+	edges := func(name service.ServiceName) []service.ServiceName {
+		node, ok := g.nodes[name]
+		if !ok {
+			return nil
+		}
+		var deps []service.ServiceName
+		for _, dep := range node.Dependencies {
+			if inSet[dep] && node.Service.Dependencies.WaitConditionFor(dep) != service.WaitForNone {
+				deps = append(deps, dep)
+			}
+		}
+		return deps
+	}
+
+	components, component := stronglyConnected(names, edges)
+
+	// A component's wave is one past the latest wave of anything it waits for
+	waveOf := make([]int, len(components))
+	var waves [][]service.ServiceName
+	for c, members := range components {
+		for _, name := range members {
+			for _, dep := range edges(name) {
+				if d := component[dep]; d != c && waveOf[d]+1 > waveOf[c] {
+					waveOf[c] = waveOf[d] + 1
+				}
+			}
+		}
+		for len(waves) <= waveOf[c] {
+			waves = append(waves, nil)
+		}
+		waves[waveOf[c]] = append(waves[waveOf[c]], members...)
+	}
+
+	for _, wave := range waves {
+		sort.Slice(wave, func(i, j int) bool { return wave[i] < wave[j] })
+	}
+	return waves
+}
+
+// Cycles returns every group of services that depend on each other, directly or
+// transitively, including services that depend on themselves
+// Members of a cycle and the cycles themselves are sorted by name.
+func (g *Graph) Cycles() [][]service.ServiceName {
+	edges := g.knownDependencies
+	components, _ := stronglyConnected(g.Names(), edges)
+
+	var cycles [][]service.ServiceName
+	for _, members := range components {
+		if len(members) == 1 && !containsName(edges(members[0]), members[0]) {
+			continue
+		}
+		sort.Slice(members, func(i, j int) bool { return members[i] < members[j] })
+		cycles = append(cycles, members)
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+// knownDependencies returns the dependencies of name that are in the graph
+func (g *Graph) knownDependencies(name service.ServiceName) []service.ServiceName {
+	node, ok := g.nodes[name]
+	if !ok {
+		return nil
+	}
+	var deps []service.ServiceName
+	for _, dep := range node.Dependencies {
+		if _, ok := g.nodes[dep]; ok {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// Names returns the services in the graph, sorted
+func (g *Graph) Names() []service.ServiceName {
+	names := make([]service.ServiceName, 0, len(g.nodes))
+	for name := range g.nodes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// stronglyConnected groups names into strongly connected components (Tarjan's algorithm)
+// Components are returned after every component they depend on; component maps
+// each name to its index in components.
+func stronglyConnected(names []service.ServiceName, edges func(service.ServiceName) []service.ServiceName) ([][]service.ServiceName, map[service.ServiceName]int) {
+	index := make(map[service.ServiceName]int)
+	lowlink := make(map[service.ServiceName]int)
+	onStack := make(map[service.ServiceName]bool)
+	component := make(map[service.ServiceName]int)
+	var stack []service.ServiceName
+	var components [][]service.ServiceName
+
+	var strongConnect func(service.ServiceName)
+	strongConnect = func(name service.ServiceName) {
+		index[name] = len(index)
+		lowlink[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+
+		for _, dep := range edges(name) {
+			if _, visited := index[dep]; !visited {
+				strongConnect(dep)
+				lowlink[name] = min(lowlink[name], lowlink[dep])
+			} else if onStack[dep] {
+				lowlink[name] = min(lowlink[name], index[dep])
+			}
+		}
+
+		if lowlink[name] == index[name] {
+			var members []service.ServiceName
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component[top] = len(components)
+				members = append(members, top)
+				if top == name {
+					break
+				}
+			}
+			components = append(components, members)
+		}
+	}
+
+	sorted := append([]service.ServiceName(nil), names...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for _, name := range sorted {
+		if _, visited := index[name]; !visited {
+			strongConnect(name)
+		}
+	}
+
+	return components, component
+}
+
+func containsName(names []service.ServiceName, name service.ServiceName) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -312,3 +312,95 @@ func TestGraph_TopologicalSort_SingleService(t *testing.T) {
 		t.Errorf("TopologicalSort() returned %q, want service-a", order[0])
 	}
 }
+
+func TestGraph_StartupWaves(t *testing.T) {
+	waitFor := func(svc *service.Service, cond service.WaitCondition, deps ...string) *service.Service {
+		svc.Dependencies.WaitFor = make(map[service.ServiceName]service.WaitCondition)
+		for _, dep := range deps {
+			svc.Dependencies.WaitFor[service.ServiceName(dep)] = cond
+		}
+		return svc
+	}
+
+	graph := NewGraph()
+	graph.AddService(createTestService("db-api", []string{}))
+	graph.AddService(waitFor(createTestService("auth", []string{"db-api"}), service.WaitForHealthy, "db-api"))
+	// orders and payments wait on each other, so they share a wave
+	graph.AddService(waitFor(createTestService("orders", []string{"auth", "payments"}), service.WaitForStarted, "auth", "payments"))
+	graph.AddService(waitFor(createTestService("payments", []string{"orders"}), service.WaitForStarted, "orders"))
+	// web depends on orders without waiting, so it starts with the first wave
+	graph.AddService(createTestService("web", []string{"orders"}))
+
+	names := []service.ServiceName{"web", "payments", "orders", "auth", "db-api"}
+	waves := graph.StartupWaves(names)
+
+	var got []string
+	for _, wave := range waves {
+		parts := make([]string, len(wave))
+		for i, name := range wave {
+			parts[i] = name.String()
+		}
+		got = append(got, strings.Join(parts, ","))
+	}
+
+	want := []string{"db-api,web", "auth", "orders,payments"}
+	if strings.Join(got, " | ") != strings.Join(want, " | ") {
+		t.Errorf("StartupWaves() = %v, want %v", got, want)
+	}
+}
+
+func TestGraph_StartupWaves_IgnoresServicesOutsideSet(t *testing.T) {
+	graph := NewGraph()
+	api := createTestService("api", []string{"auth"})
+	api.Dependencies.WaitFor = map[service.ServiceName]service.WaitCondition{"auth": service.WaitForHealthy}
+	graph.AddService(api)
+
+	waves := graph.StartupWaves([]service.ServiceName{"api"})
+	if len(waves) != 1 || len(waves[0]) != 1 || waves[0][0] != "api" {
+		t.Errorf("StartupWaves() = %v, want [[api]]", waves)
+	}
+}
+
+func TestGraph_Cycles(t *testing.T) {
+	graph := NewGraph()
+	graph.AddService(createTestService("a", []string{"b"}))
+	graph.AddService(createTestService("b", []string{"c"}))
+	graph.AddService(createTestService("c", []string{"a"}))
+	graph.AddService(createTestService("d", []string{"a"}))
+	graph.AddService(createTestService("e", []string{"e"}))
+	if err := graph.Build(); err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	cycles := graph.Cycles()
+	if len(cycles) != 2 {
+		t.Fatalf("Cycles() = %v, want 2 cycles", cycles)
+	}
+	if got := joinNames(cycles[0]); got != "a,b,c" {
+		t.Errorf("first cycle = %s, want a,b,c", got)
+	}
+	if got := joinNames(cycles[1]); got != "e" {
+		t.Errorf("second cycle = %s, want self-dependent e", got)
+	}
+}
+
+func TestGraph_Cycles_None(t *testing.T) {
+	graph := NewGraph()
+	graph.AddService(createTestService("a", []string{"b"}))
+	graph.AddService(createTestService("b", []string{}))
+	if err := graph.Build(); err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	if cycles := graph.Cycles(); len(cycles) != 0 {
+		t.Errorf("Cycles() = %v, want none", cycles)
+	}
+}
+
+func joinNames(names []service.ServiceName) string {
+	parts := make([]string, len(names))
+	for i, n := range names {
+		parts[i] = n.String()
+	}
+	return strings.Join(parts, ",")
+}
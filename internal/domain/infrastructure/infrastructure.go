@@ -37,17 +37,97 @@ func (r *InfrastructureRequirements) Has(infraType string) bool {
 	}
 }
 
+// Default superuser credentials of the shared PostgreSQL instance
+const (
+	DefaultPostgresUsername = "postgres"
+	DefaultPostgresPassword = "postgres"
+)
+
 // PostgresConfig represents PostgreSQL configuration
 type PostgresConfig struct {
 	Database   string
+	Username   string // Optional: dedicated role for this database
+	Password   string // Optional: password for Username
 	Migrations string
 	Seed       string
+
+	// Databases lists every database on the shared instance.
+	// Only populated on aggregated requirements (see Aggregate).
+	Databases []PostgresDatabase
+}
+
+// PostgresDatabase represents a single service database on the shared instance
+type PostgresDatabase struct {
+	Database   string
+	Username   string
+	Password   string
+	Migrations string
+	Seed       string
+}
+
+// Credentials returns the role and password used to connect to this database.
+// Falls back to the instance superuser when no dedicated role is configured.
+func (d PostgresDatabase) Credentials() (username, password string) {
+	if d.Username == "" {
+		return DefaultPostgresUsername, DefaultPostgresPassword
+	}
+	if d.Password == "" {
+		return d.Username, DefaultPostgresPassword
+	}
+	return d.Username, d.Password
+}
+
+// AsDatabase returns this config as a single database entry
+func (c *PostgresConfig) AsDatabase() PostgresDatabase {
+	return PostgresDatabase{
+		Database:   c.Database,
+		Username:   c.Username,
+		Password:   c.Password,
+		Migrations: c.Migrations,
+		Seed:       c.Seed,
+	}
+}
+
+// AllDatabases returns every database that must exist on the instance.
+// For a single service's config this is just its own database.
+func (c *PostgresConfig) AllDatabases() []PostgresDatabase {
+	if len(c.Databases) > 0 {
+		return c.Databases
+	}
+	return []PostgresDatabase{c.AsDatabase()}
 }
 
 // MongoDBConfig represents MongoDB configuration
 type MongoDBConfig struct {
 	Database string
 	Seed     string
+
+	// Databases lists every database on the shared instance.
+	// Only populated on aggregated requirements (see Aggregate).
+	Databases []MongoDBDatabase
+}
+
+// MongoDBDatabase represents a single service database on the shared instance
+type MongoDBDatabase struct {
+	Database string
+	Seed     string
+}
+
+// AsDatabase returns this config as a single database entry
+func (c *MongoDBConfig) AsDatabase() MongoDBDatabase {
+	return MongoDBDatabase{
+		Database: c.Database,
+		Seed:     c.Seed,
+	}
+}
+
+// AllDatabases returns every database used on the instance.
+// For a single service's config this is just its own database.
+func (c *MongoDBConfig) AllDatabases() []MongoDBDatabase {
+	if len(c.Databases) > 0 {
+		return c.Databases
+	}
+	return []MongoDBDatabase{c.AsDatabase()}
 }
 
 // RedisConfig represents Redis configuration
@@ -110,23 +190,60 @@ type TunnelTargetRequirement struct {
 
 // Aggregate aggregates infrastructure requirements from multiple services
 // - Single-instance resources (Postgres, MongoDB, Redis): Creates one shared instance
+// - Postgres databases: Every service database is kept, once per role connecting to it
+// - MongoDB databases: Every service database is kept, deduplicated by name
 // - Multi-instance resources (SQS, SNS, S3): Deduplicates by name
 func Aggregate(requirements ...InfrastructureRequirements) InfrastructureRequirements {
 	aggregated := InfrastructureRequirements{}
 
 	// Track seen resources to avoid duplicates
+	seenDatabases := make(map[string]bool)
+	seenRoles := make(map[[2]string]bool) // database, role
+	seenMongoDatabases := make(map[string]bool)
 	seenQueues := make(map[string]bool)
 	seenTopics := make(map[string]bool)
 	seenBuckets := make(map[string]bool)
 
 	for _, req := range requirements {
-		// Single-instance: first config wins, all services share one container
-		if req.Postgres != nil && aggregated.Postgres == nil {
-			aggregated.Postgres = req.Postgres
+		// Postgres: one shared container, first config provides the default database,
+		// but every service's database is created on it, with every role that connects to it.
+		// A database's migrations and seed are only applied once, from its first entry.
+		if req.Postgres != nil {
+			if aggregated.Postgres == nil {
+				first := *req.Postgres
+				first.Databases = nil
+				aggregated.Postgres = &first
+			}
+			for _, db := range req.Postgres.AllDatabases() {
+				role, _ := db.Credentials()
+				if seenRoles[[2]string{db.Database, role}] {
+					continue
+				}
+				seenRoles[[2]string{db.Database, role}] = true
+				if seenDatabases[db.Database] {
+					db.Migrations, db.Seed = "", ""
+				}
+				seenDatabases[db.Database] = true
+				aggregated.Postgres.Databases = append(aggregated.Postgres.Databases, db)
+			}
 		}
-		if req.MongoDB != nil && aggregated.MongoDB == nil {
-			aggregated.MongoDB = req.MongoDB
+
+		// MongoDB: same as Postgres, every service's database is kept for seeding
+		if req.MongoDB != nil {
+			if aggregated.MongoDB == nil {
+				first := *req.MongoDB
+				first.Databases = nil
+				aggregated.MongoDB = &first
+			}
+			for _, db := range req.MongoDB.AllDatabases() {
+				if !seenMongoDatabases[db.Database] {
+					seenMongoDatabases[db.Database] = true
+					aggregated.MongoDB.Databases = append(aggregated.MongoDB.Databases, db)
+				}
+			}
 		}
+
+		// Single-instance: first config wins, all services share one container
 		if req.Redis != nil && aggregated.Redis == nil {
 			aggregated.Redis = req.Redis
 		}
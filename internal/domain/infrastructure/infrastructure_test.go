@@ -258,7 +258,7 @@ func TestAggregate_S3Buckets(t *testing.T) {
 }
 
 func TestAggregate_FirstPostgresWins(t *testing.T) {
-	// When multiple services have postgres, the first one's config is used
+	// When multiple services have postgres, the first one provides the default database
 	req1 := InfrastructureRequirements{
 		Postgres: &PostgresConfig{Database: "db1", Migrations: "./migrations1"},
 	}
@@ -273,6 +273,90 @@ func TestAggregate_FirstPostgresWins(t *testing.T) {
 	}
 }
 
+func TestAggregate_PostgresKeepsEveryDatabase(t *testing.T) {
+	req1 := InfrastructureRequirements{
+		Postgres: &PostgresConfig{Database: "orders_db"},
+	}
+	req2 := InfrastructureRequirements{
+		Postgres: &PostgresConfig{Database: "payments_db", Username: "payments", Password: "secret"},
+	}
+	req3 := InfrastructureRequirements{
+		Postgres: &PostgresConfig{Database: "orders_db", Username: "reporting", Password: "report", Migrations: "/reporting/migrations"},
+	}
+	req4 := InfrastructureRequirements{
+		Postgres: &PostgresConfig{Database: "orders_db", Username: "postgres"},
+	}
+
+	result := Aggregate(req1, req2, req3, req4)
+
+	dbs := result.Postgres.AllDatabases()
+	if len(dbs) != 3 {
+		t.Fatalf("Aggregate() kept %d databases, want 3: %+v", len(dbs), dbs)
+	}
+	if dbs[0].Database != "orders_db" || dbs[0].Username != "" {
+		t.Errorf("first database = %+v, want orders_db with default role", dbs[0])
+	}
+	if dbs[1].Database != "payments_db" || dbs[1].Username != "payments" || dbs[1].Password != "secret" {
+		t.Errorf("second database = %+v, want payments_db owned by payments", dbs[1])
+	}
+	// A second role on a shared database is created and granted, but migrations only run once
+	if dbs[2].Database != "orders_db" || dbs[2].Username != "reporting" || dbs[2].Password != "report" || dbs[2].Migrations != "" {
+		t.Errorf("third database = %+v, want orders_db for the reporting role without migrations", dbs[2])
+	}
+
+	// Aggregating must not mutate the inputs
+	if len(req1.Postgres.Databases) != 0 {
+		t.Error("Aggregate() mutated the first service's PostgresConfig")
+	}
+}
+
+func TestAggregate_MongoDBKeepsEveryDatabase(t *testing.T) {
+	req1 := InfrastructureRequirements{
+		MongoDB: &MongoDBConfig{Database: "catalog", Seed: "/catalog/seed"},
+	}
+	req2 := InfrastructureRequirements{
+		MongoDB: &MongoDBConfig{Database: "reviews"},
+	}
+	req3 := InfrastructureRequirements{
+		MongoDB: &MongoDBConfig{Database: "catalog", Seed: "/ignored"},
+	}
+
+	result := Aggregate(req1, req2, req3)
+
+	if result.MongoDB.Database != "catalog" {
+		t.Errorf("Aggregate() MongoDB.Database = %q, want catalog", result.MongoDB.Database)
+	}
+	dbs := result.MongoDB.AllDatabases()
+	if len(dbs) != 2 {
+		t.Fatalf("Aggregate() kept %d databases, want 2: %+v", len(dbs), dbs)
+	}
+	if dbs[0].Seed != "/catalog/seed" || dbs[1].Database != "reviews" {
+		t.Errorf("Aggregate() databases = %+v", dbs)
+	}
+}
+
+func TestPostgresDatabase_Credentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		db       PostgresDatabase
+		wantUser string
+		wantPass string
+	}{
+		{"superuser by default", PostgresDatabase{Database: "a"}, "postgres", "postgres"},
+		{"dedicated role", PostgresDatabase{Database: "a", Username: "app", Password: "pw"}, "app", "pw"},
+		{"role without password", PostgresDatabase{Database: "a", Username: "app"}, "app", "postgres"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, pass := tt.db.Credentials()
+			if user != tt.wantUser || pass != tt.wantPass {
+				t.Errorf("Credentials() = (%q, %q), want (%q, %q)", user, pass, tt.wantUser, tt.wantPass)
+			}
+		})
+	}
+}
+
 func TestAggregate_Empty(t *testing.T) {
 	result := Aggregate()
 
@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
@@ -28,6 +29,14 @@ const (
 	ServiceTypeNode   ServiceType = "node"
 )
 
+// ServiceTypes lists the supported service types
+var ServiceTypes = []ServiceType{ServiceTypeGo, ServiceTypePython, ServiceTypeNode}
+
+// IsValid reports whether t is a supported service type
+func (t ServiceType) IsValid() bool {
+	return slices.Contains(ServiceTypes, t)
+}
+
 // Port represents a network port
 type Port struct {
 	value int
@@ -52,10 +61,24 @@ type BuildConfig struct {
 	Context    string
 }
 
+// DefaultRunWorkdir is where the service directory is mounted for hot reload
+const DefaultRunWorkdir = "/app"
+
 // RunConfig represents runtime configuration
 type RunConfig struct {
 	Command   string
 	HotReload bool
+	Source    string   // Service directory on the host, mounted when HotReload is set
+	Workdir   string   // Mount point and working directory inside the container
+	Ignore    []string // Glob patterns of files that don't trigger a reload
+}
+
+// MountPath returns the container path the service directory is mounted at
+func (r *RunConfig) MountPath() string {
+	if r.Workdir == "" {
+		return DefaultRunWorkdir
+	}
+	return r.Workdir
 }
 
 // HealthConfig represents health check configuration
@@ -69,9 +92,44 @@ type HealthConfig struct {
 // ServiceDependencies represents what a service depends on
 type ServiceDependencies struct {
 	Services       []ServiceName
+	WaitFor        map[ServiceName]WaitCondition // set only for dependencies that gate startup
 	Infrastructure infrastructure.InfrastructureRequirements
 }
 
+// WaitConditionFor returns what must hold for a dependency before this service starts
+func (d ServiceDependencies) WaitConditionFor(name ServiceName) WaitCondition {
+	if cond, ok := d.WaitFor[name]; ok {
+		return cond
+	}
+	return WaitForNone
+}
+
+// WaitCondition is what a dependency must reach before its dependent is started
+type WaitCondition string
+
+const (
+	WaitForNone    WaitCondition = "none"    // start in parallel (default)
+	WaitForStarted WaitCondition = "started" // container is running
+	WaitForHealthy WaitCondition = "healthy" // health check passes
+)
+
+// ParseWaitCondition parses a wait_for value; empty means none
+func ParseWaitCondition(value string) (WaitCondition, error) {
+	switch WaitCondition(value) {
+	case "", WaitForNone:
+		return WaitForNone, nil
+	case WaitForStarted, WaitForHealthy:
+		return WaitCondition(value), nil
+	}
+	return "", fmt.Errorf("invalid wait_for %q (valid: healthy, started, none)", value)
+}
+
+// Stronger reports whether c waits for more than other (healthy > started > none)
+func (c WaitCondition) Stronger(other WaitCondition) bool {
+	rank := map[WaitCondition]int{WaitForNone: 0, WaitForStarted: 1, WaitForHealthy: 2}
+	return rank[c] > rank[other]
+}
+
 // ServiceName is a value object for service names
 type ServiceName string
 
@@ -84,6 +142,9 @@ type Environment struct {
 	Variables  map[string]string
 	References map[string]string
 	Secrets    map[string]SecretRequirement
+
+	Source         string         // grund.yaml the service was loaded from, for error messages
+	ReferenceLines map[string]int // env_refs key -> line in Source
 }
 
 // SecretRequirement defines a secret required by the service
@@ -276,3 +276,16 @@ func TestServiceType_Constants(t *testing.T) {
 		t.Errorf("ServiceTypeNode = %q, want %q", ServiceTypeNode, "node")
 	}
 }
+
+func TestServiceType_IsValid(t *testing.T) {
+	for _, st := range ServiceTypes {
+		if !st.IsValid() {
+			t.Errorf("%q should be valid", st)
+		}
+	}
+	for _, st := range []ServiceType{"ruby", "java", ""} {
+		if st.IsValid() {
+			t.Errorf("%q should not be valid", st)
+		}
+	}
+}
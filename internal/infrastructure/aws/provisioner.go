@@ -192,6 +192,15 @@ func (p *LocalStackProvisioner) ProvisionLocalStack(ctx context.Context, req inf
 				}
 				ui.Successf("Created S3 bucket: %s", bucket.Name)
 			}
+
+			if bucket.Seed != "" {
+				ui.SubStep("Seeding S3 bucket: %s", bucket.Name)
+				uploaded, skipped, err := seedBucket(ctx, s3Client, bucket.Name, bucket.Seed)
+				if err != nil {
+					return fmt.Errorf("failed to seed bucket %s: %w", bucket.Name, err)
+				}
+				ui.Successf("Seeded S3 bucket %s: %d uploaded, %d unchanged", bucket.Name, uploaded, skipped)
+			}
 		}
 	}
 
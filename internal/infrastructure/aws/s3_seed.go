@@ -0,0 +1,118 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/vivekkundariya/grund/internal/ui"
+)
+
+// defaultContentType is used for files with an unknown extension
+const defaultContentType = "application/octet-stream"
+
+// s3ObjectAPI is the subset of the S3 client used for seeding
+type s3ObjectAPI interface {
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// seedObject is a local file to be uploaded to a bucket
+type seedObject struct {
+	Path string
+	Key  string
+}
+
+// seedBucket uploads the seed directory tree into the bucket
+// Keys mirror paths relative to the seed directory. Objects whose ETag already
+// matches the local file's MD5 are skipped, so re-running `grund up` is cheap.
+// Returns the number of uploaded and skipped objects.
+func seedBucket(ctx context.Context, client s3ObjectAPI, bucket, seedPath string) (uploaded, skipped int, err error) {
+	objects, err := collectSeedObjects(seedPath)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, obj := range objects {
+		data, err := os.ReadFile(obj.Path)
+		if err != nil {
+			return uploaded, skipped, fmt.Errorf("failed to read %s: %w", obj.Path, err)
+		}
+
+		sum := md5.Sum(data)
+		etag := hex.EncodeToString(sum[:])
+
+		head, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(obj.Key),
+		})
+		if err == nil && head.ETag != nil && strings.Trim(*head.ETag, `"`) == etag {
+			ui.Debug("S3 object unchanged: s3://%s/%s", bucket, obj.Key)
+			skipped++
+			continue
+		}
+
+		ui.Debug("Uploading s3://%s/%s", bucket, obj.Key)
+		_, err = client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:      aws.String(bucket),
+			Key:         aws.String(obj.Key),
+			Body:        bytes.NewReader(data),
+			ContentType: aws.String(contentTypeFor(obj.Key)),
+		})
+		if err != nil {
+			return uploaded, skipped, fmt.Errorf("failed to upload %s: %w", obj.Key, err)
+		}
+		uploaded++
+	}
+
+	return uploaded, skipped, nil
+}
+
+// collectSeedObjects walks the seed path and maps each file to its object key
+// A single file is uploaded under its base name.
+func collectSeedObjects(seedPath string) ([]seedObject, error) {
+	info, err := os.Stat(seedPath)
+	if err != nil {
+		return nil, fmt.Errorf("seed path not found: %s", seedPath)
+	}
+	if !info.IsDir() {
+		return []seedObject{{Path: seedPath, Key: filepath.Base(seedPath)}}, nil
+	}
+
+	var objects []seedObject
+	err = filepath.WalkDir(seedPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(seedPath, path)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, seedObject{Path: path, Key: filepath.ToSlash(rel)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed directory: %w", err)
+	}
+	return objects, nil
+}
+
+// contentTypeFor guesses an object's content type from its extension
+func contentTypeFor(key string) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(key)); contentType != "" {
+		return contentType
+	}
+	return defaultContentType
+}
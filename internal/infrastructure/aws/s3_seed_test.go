@@ -0,0 +1,87 @@
+package aws
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+type fakeS3 struct {
+	etags map[string]string // key -> ETag as returned by S3 (quoted)
+	puts  map[string]string // key -> content type
+}
+
+func (f *fakeS3) HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	etag, ok := f.etags[*params.Key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &s3.HeadObjectOutput{ETag: aws.String(etag)}, nil
+}
+
+func (f *fakeS3) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	f.puts[*params.Key] = *params.ContentType
+	return &s3.PutObjectOutput{}, nil
+}
+
+func writeSeedFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSeedBucket(t *testing.T) {
+	dir := t.TempDir()
+	writeSeedFile(t, dir, "invoices/2024/01.pdf", "pdf")
+	writeSeedFile(t, dir, "config.json", "{}")
+	writeSeedFile(t, dir, "README", "unchanged")
+
+	sum := md5.Sum([]byte("unchanged"))
+	client := &fakeS3{
+		etags: map[string]string{"README": `"` + hex.EncodeToString(sum[:]) + `"`},
+		puts:  map[string]string{},
+	}
+
+	uploaded, skipped, err := seedBucket(context.Background(), client, "documents", dir)
+	if err != nil {
+		t.Fatalf("seedBucket() error: %v", err)
+	}
+	if uploaded != 2 || skipped != 1 {
+		t.Errorf("seedBucket() = %d uploaded, %d skipped, want 2, 1", uploaded, skipped)
+	}
+
+	if got := client.puts["invoices/2024/01.pdf"]; got != "application/pdf" {
+		t.Errorf("content type for pdf = %q, want application/pdf", got)
+	}
+	if got := client.puts["config.json"]; got != "application/json" {
+		t.Errorf("content type for json = %q, want application/json", got)
+	}
+	if _, ok := client.puts["README"]; ok {
+		t.Error("Expected unchanged README to be skipped")
+	}
+}
+
+func TestSeedBucket_MissingPath(t *testing.T) {
+	client := &fakeS3{puts: map[string]string{}}
+	if _, _, err := seedBucket(context.Background(), client, "documents", filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected error for missing seed path")
+	}
+}
+
+func TestContentTypeFor(t *testing.T) {
+	if got := contentTypeFor("data.bin.unknownext"); got != defaultContentType {
+		t.Errorf("contentTypeFor() = %q, want %q", got, defaultContentType)
+	}
+}
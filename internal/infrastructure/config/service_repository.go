@@ -43,7 +43,35 @@ func (r *ServiceRepositoryImpl) FindByName(name service.ServiceName) (*service.S
 		return nil, fmt.Errorf("failed to parse YAML: %w", err)
 	}
 
-	return r.toDomainService(configDTO, name, servicePath)
+	svc, err := r.toDomainService(configDTO, name, servicePath)
+	if err != nil {
+		return nil, err
+	}
+	svc.Environment.Source = configPath
+	svc.Environment.ReferenceLines = envRefLines(data)
+	return svc, nil
+}
+
+// envRefLines maps each env_refs key to its line in grund.yaml
+func envRefLines(data []byte) map[string]int {
+	lines := make(map[string]int)
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return lines
+	}
+
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "env_refs" {
+			continue
+		}
+		refs := root.Content[i+1]
+		for j := 0; j+1 < len(refs.Content); j += 2 {
+			lines[refs.Content[j].Value] = refs.Content[j].Line
+		}
+	}
+	return lines
 }
 
 // FindAll finds all services
@@ -123,8 +151,10 @@ type BuildConfigDTO struct {
 }
 
 type RunConfigDTO struct {
-	Command   string `yaml:"command"`
-	HotReload bool   `yaml:"hot_reload"`
+	Command   string   `yaml:"command"`
+	HotReload bool     `yaml:"hot_reload"`
+	Workdir   string   `yaml:"workdir,omitempty"`
+	Ignore    []string `yaml:"ignore,omitempty"`
 }
 
 type HealthConfigDTO struct {
@@ -135,10 +165,36 @@ type HealthConfigDTO struct {
 }
 
 type RequirementsDTO struct {
-	Services       []string                `yaml:"services"`
+	Services       []ServiceRequirementDTO `yaml:"services"`
 	Infrastructure InfrastructureConfigDTO `yaml:"infrastructure"`
 }
 
+// ServiceRequirementDTO is a service dependency
+// Written either as a plain name or as {name, wait_for} to gate startup on the dependency.
+type ServiceRequirementDTO struct {
+	Name    string `yaml:"name"`
+	WaitFor string `yaml:"wait_for,omitempty"`
+}
+
+// UnmarshalYAML accepts a plain service name or a mapping
+func (s *ServiceRequirementDTO) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		s.Name = value.Value
+		return nil
+	}
+	type plain ServiceRequirementDTO
+	return value.Decode((*plain)(s))
+}
+
+// MarshalYAML writes dependencies without a wait_for as a plain name
+func (s ServiceRequirementDTO) MarshalYAML() (interface{}, error) {
+	if s.WaitFor == "" {
+		return s.Name, nil
+	}
+	type plain ServiceRequirementDTO
+	return plain(s), nil
+}
+
 type InfrastructureConfigDTO struct {
 	Postgres *PostgresConfigDTO `yaml:"postgres,omitempty"`
 	MongoDB  *MongoDBConfigDTO  `yaml:"mongodb,omitempty"`
@@ -162,6 +218,8 @@ type TunnelTargetDTO struct {
 
 type PostgresConfigDTO struct {
 	Database   string `yaml:"database"`
+	Username   string `yaml:"username,omitempty"`
+	Password   string `yaml:"password,omitempty"`
 	Migrations string `yaml:"migrations,omitempty"`
 	Seed       string `yaml:"seed,omitempty"`
 }
@@ -232,6 +290,9 @@ func (r *ServiceRepositoryImpl) toDomainService(dto ServiceConfigDTO, name servi
 		run = &service.RunConfig{
 			Command:   dto.Service.Run.Command,
 			HotReload: dto.Service.Run.HotReload,
+			Source:    servicePath,
+			Workdir:   dto.Service.Run.Workdir,
+			Ignore:    dto.Service.Run.Ignore,
 		}
 	}
 
@@ -248,15 +309,29 @@ func (r *ServiceRepositoryImpl) toDomainService(dto ServiceConfigDTO, name servi
 
 	// Convert service dependencies
 	var serviceDeps []service.ServiceName
+	var waitFor map[service.ServiceName]service.WaitCondition
 	for _, dep := range dto.Requires.Services {
-		serviceDeps = append(serviceDeps, service.ServiceName(dep))
+		depName := service.ServiceName(dep.Name)
+		serviceDeps = append(serviceDeps, depName)
+
+		cond, err := service.ParseWaitCondition(dep.WaitFor)
+		if err != nil {
+			return nil, fmt.Errorf("requires.services %s: %w", dep.Name, err)
+		}
+		if cond != service.WaitForNone {
+			if waitFor == nil {
+				waitFor = make(map[service.ServiceName]service.WaitCondition)
+			}
+			waitFor[depName] = cond
+		}
 	}
 
 	// Convert infrastructure requirements
-	infraReqs := r.toInfrastructureRequirements(dto.Requires.Infrastructure)
+	infraReqs := r.toInfrastructureRequirements(dto.Requires.Infrastructure, servicePath)
 
 	deps := service.ServiceDependencies{
 		Services:       serviceDeps,
+		WaitFor:        waitFor,
 		Infrastructure: infraReqs,
 	}
 
@@ -289,21 +364,25 @@ func (r *ServiceRepositoryImpl) toDomainService(dto ServiceConfigDTO, name servi
 	return svc, svc.Validate()
 }
 
-func (r *ServiceRepositoryImpl) toInfrastructureRequirements(dto InfrastructureConfigDTO) infrastructure.InfrastructureRequirements {
+// toInfrastructureRequirements converts infrastructure DTOs to the domain model
+// File paths (migrations etc.) are resolved relative to the service directory
+func (r *ServiceRepositoryImpl) toInfrastructureRequirements(dto InfrastructureConfigDTO, servicePath string) infrastructure.InfrastructureRequirements {
 	var req infrastructure.InfrastructureRequirements
 
 	if dto.Postgres != nil {
 		req.Postgres = &infrastructure.PostgresConfig{
 			Database:   dto.Postgres.Database,
-			Migrations: dto.Postgres.Migrations,
-			Seed:       dto.Postgres.Seed,
+			Username:   dto.Postgres.Username,
+			Password:   dto.Postgres.Password,
+			Migrations: resolveServicePath(servicePath, dto.Postgres.Migrations),
+			Seed:       resolveServicePath(servicePath, dto.Postgres.Seed),
 		}
 	}
 
 	if dto.MongoDB != nil {
 		req.MongoDB = &infrastructure.MongoDBConfig{
 			Database: dto.MongoDB.Database,
-			Seed:     dto.MongoDB.Seed,
+			Seed:     resolveServicePath(servicePath, dto.MongoDB.Seed),
 		}
 	}
 
@@ -346,7 +425,7 @@ func (r *ServiceRepositoryImpl) toInfrastructureRequirements(dto InfrastructureC
 		for _, b := range dto.S3.Buckets {
 			buckets = append(buckets, infrastructure.BucketConfig{
 				Name: b.Name,
-				Seed: b.Seed,
+				Seed: resolveServicePath(servicePath, b.Seed),
 			})
 		}
 		req.S3 = &infrastructure.S3Config{Buckets: buckets}
@@ -398,7 +477,7 @@ func (r *ServiceRepositoryImpl) toConfigDTO(svc *service.Service) ServiceConfigD
 			},
 		},
 		Requires: RequirementsDTO{
-			Services: make([]string, len(svc.Dependencies.Services)),
+			Services: make([]ServiceRequirementDTO, len(svc.Dependencies.Services)),
 		},
 		Env:     svc.Environment.Variables,
 		EnvRefs: svc.Environment.References,
@@ -406,7 +485,10 @@ func (r *ServiceRepositoryImpl) toConfigDTO(svc *service.Service) ServiceConfigD
 	}
 
 	for i, dep := range svc.Dependencies.Services {
-		dto.Requires.Services[i] = dep.String()
+		dto.Requires.Services[i] = ServiceRequirementDTO{Name: dep.String()}
+		if cond := svc.Dependencies.WaitConditionFor(dep); cond != service.WaitForNone {
+			dto.Requires.Services[i].WaitFor = string(cond)
+		}
 	}
 
 	if svc.Build != nil {
@@ -420,6 +502,8 @@ func (r *ServiceRepositoryImpl) toConfigDTO(svc *service.Service) ServiceConfigD
 		dto.Service.Run = &RunConfigDTO{
 			Command:   svc.Run.Command,
 			HotReload: svc.Run.HotReload,
+			Workdir:   svc.Run.Workdir,
+			Ignore:    svc.Run.Ignore,
 		}
 	}
 
@@ -428,6 +512,8 @@ func (r *ServiceRepositoryImpl) toConfigDTO(svc *service.Service) ServiceConfigD
 	if svc.Dependencies.Infrastructure.Postgres != nil {
 		infraDTO.Postgres = &PostgresConfigDTO{
 			Database:   svc.Dependencies.Infrastructure.Postgres.Database,
+			Username:   svc.Dependencies.Infrastructure.Postgres.Username,
+			Password:   svc.Dependencies.Infrastructure.Postgres.Password,
 			Migrations: svc.Dependencies.Infrastructure.Postgres.Migrations,
 			Seed:       svc.Dependencies.Infrastructure.Postgres.Seed,
 		}
@@ -439,6 +525,14 @@ func (r *ServiceRepositoryImpl) toConfigDTO(svc *service.Service) ServiceConfigD
 	return dto
 }
 
+// resolveServicePath makes a path from grund.yaml absolute, relative to the service directory
+func resolveServicePath(servicePath, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(servicePath, path)
+}
+
 func parseDuration(s string) (time.Duration, error) {
 	// Simple parser for duration strings like "5s", "10m"
 	// Can be enhanced
@@ -50,6 +50,8 @@ requires:
   infrastructure:
     postgres:
       database: testdb
+      username: test_user
+      password: test_pass
       migrations: ./migrations
     redis: true
     sqs:
@@ -134,6 +136,10 @@ func TestServiceRepository_FindByName(t *testing.T) {
 		t.Error("Expected postgres config")
 	} else if svc.Dependencies.Infrastructure.Postgres.Database != "testdb" {
 		t.Errorf("Expected postgres database 'testdb', got %q", svc.Dependencies.Infrastructure.Postgres.Database)
+	} else if user, pass := svc.Dependencies.Infrastructure.Postgres.AsDatabase().Credentials(); user != "test_user" || pass != "test_pass" {
+		t.Errorf("Expected postgres credentials test_user/test_pass, got %s/%s", user, pass)
+	} else if want := filepath.Join(svcDir, "migrations"); svc.Dependencies.Infrastructure.Postgres.Migrations != want {
+		t.Errorf("Expected migrations path %q, got %q", want, svc.Dependencies.Infrastructure.Postgres.Migrations)
 	}
 
 	if svc.Dependencies.Infrastructure.Redis == nil {
@@ -150,6 +156,14 @@ func TestServiceRepository_FindByName(t *testing.T) {
 	if svc.Environment.Variables["APP_ENV"] != "test" {
 		t.Errorf("Expected APP_ENV='test', got %q", svc.Environment.Variables["APP_ENV"])
 	}
+
+	// env_refs keys are located in grund.yaml for error messages
+	if want := filepath.Join(svcDir, "grund.yaml"); svc.Environment.Source != want {
+		t.Errorf("Expected source %q, got %q", want, svc.Environment.Source)
+	}
+	if line := svc.Environment.ReferenceLines["DATABASE_URL"]; line != 35 {
+		t.Errorf("Expected DATABASE_URL on line 35, got %d", line)
+	}
 }
 
 func TestServiceRepository_FindByName_NotFound(t *testing.T) {
@@ -255,3 +269,88 @@ service:
 		t.Error("Expected error for invalid port, got nil")
 	}
 }
+
+func TestServiceRepository_WaitFor(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	content := `version: "1"
+service:
+  name: orders
+  type: go
+  port: 8080
+  build:
+    dockerfile: Dockerfile
+    context: .
+  health:
+    endpoint: /health
+requires:
+  services:
+    - name: user-service
+      wait_for: healthy
+    - name: auth-service
+      wait_for: started
+    - notification-service
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "grund.yaml"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	repo := NewServiceRepository(&mockRegistryRepo{paths: map[string]string{"orders": tmpDir}})
+
+	svc, err := repo.FindByName(service.ServiceName("orders"))
+	if err != nil {
+		t.Fatalf("FindByName() returned error: %v", err)
+	}
+
+	if len(svc.Dependencies.Services) != 3 {
+		t.Fatalf("Expected 3 service dependencies, got %v", svc.Dependencies.Services)
+	}
+	tests := map[service.ServiceName]service.WaitCondition{
+		"user-service":         service.WaitForHealthy,
+		"auth-service":         service.WaitForStarted,
+		"notification-service": service.WaitForNone,
+	}
+	for dep, want := range tests {
+		if got := svc.Dependencies.WaitConditionFor(dep); got != want {
+			t.Errorf("WaitConditionFor(%s) = %q, want %q", dep, got, want)
+		}
+	}
+
+	// Saving keeps plain names for dependencies without wait_for
+	if err := repo.Save(svc); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+	saved, err := repo.FindByName(service.ServiceName("orders"))
+	if err != nil {
+		t.Fatalf("FindByName() after Save returned error: %v", err)
+	}
+	if got := saved.Dependencies.WaitConditionFor("user-service"); got != service.WaitForHealthy {
+		t.Errorf("WaitConditionFor(user-service) after Save = %q, want healthy", got)
+	}
+}
+
+func TestServiceRepository_InvalidWaitFor(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	content := `version: "1"
+service:
+  name: orders
+  type: go
+  port: 8080
+  health:
+    endpoint: /health
+requires:
+  services:
+    - name: user-service
+      wait_for: ready
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "grund.yaml"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	repo := NewServiceRepository(&mockRegistryRepo{paths: map[string]string{"orders": tmpDir}})
+
+	if _, err := repo.FindByName(service.ServiceName("orders")); err == nil {
+		t.Error("Expected error for invalid wait_for, got nil")
+	}
+}
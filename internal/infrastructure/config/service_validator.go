@@ -0,0 +1,612 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// yamlErrorLine extracts the line from yaml.v3 error messages
+	yamlErrorLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+	// envNamePattern matches valid environment variable names
+	envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// tunnelReference matches ${tunnel.<name>. in env_refs
+	tunnelReference = regexp.MustCompile(`\$\{tunnel\.([^.}]+)`)
+	// awsReference matches ${sqs.<name>., ${sns.<name>. and ${s3.<name>. placeholders
+	awsReference = regexp.MustCompile(`\$\{(sqs|sns|s3)\.([^.}$]+)\.`)
+	// localStackReference matches ${localstack. placeholders
+	localStackReference = regexp.MustCompile(`\$\{localstack\.`)
+)
+
+// Values accepted where the DTO only has a string
+var (
+	tunnelProviders    = []string{"cloudflared", "ngrok"}
+	snsProtocols       = []string{"sqs", "http", "https", "email", "email-json", "sms", "lambda", "application", "firehose"}
+	filterPolicyScopes = []string{"MessageBody", "MessageAttributes"}
+)
+
+const (
+	defaultDockerfile = "Dockerfile"
+	// previewTunnelURLHost stands in for tunnel URLs, which are only known once a tunnel starts
+	previewTunnelURLHost = "tunnel.invalid"
+)
+
+// ServiceConfigValidatorImpl validates grund.yaml files through the same DTO FindByName uses
+// Anything it accepts loads at grund up; it reports every problem with its line and column.
+type ServiceConfigValidatorImpl struct {
+	envResolver ports.EnvironmentResolver
+	services    ports.ServiceRepository
+}
+
+// NewServiceConfigValidator creates a grund.yaml validator
+// env_refs and SNS subscription endpoints are resolved with envResolver against the declared requirements.
+// services loads required services for the queues, topics and buckets they declare; it may be nil
+// outside a project, and AWS resource names are then only checked for services without dependencies.
+func NewServiceConfigValidator(envResolver ports.EnvironmentResolver, services ports.ServiceRepository) ports.ServiceConfigValidator {
+	return &ServiceConfigValidatorImpl{envResolver: envResolver, services: services}
+}
+
+// ValidateFile validates a grund.yaml
+func (v *ServiceConfigValidatorImpl) ValidateFile(configPath string, registered map[service.ServiceName]bool) (*service.Service, []ports.ValidationIssue) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, []ports.ValidationIssue{{Message: fmt.Sprintf("cannot read %s: %v", configPath, err)}}
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, []ports.ValidationIssue{yamlIssue(err.Error())}
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, []ports.ValidationIssue{{Line: 1, Column: 1, Message: "grund.yaml must be a mapping with a service section"}}
+	}
+
+	c := &issueCollector{root: doc.Content[0], decodeLines: make(map[int]bool)}
+	c.unknownFields(c.root, reflect.TypeOf(ServiceConfigDTO{}), nil)
+
+	var dto ServiceConfigDTO
+	if err := c.root.Decode(&dto); err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			c.issues = append(c.issues, yamlIssue(err.Error()))
+			return nil, c.sorted()
+		}
+		for _, msg := range typeErr.Errors {
+			issue := yamlIssue(msg)
+			c.decodeLines[issue.Line] = true
+			c.issues = append(c.issues, issue)
+		}
+	}
+
+	servicePath := filepath.Dir(configPath)
+	c.checkService(dto, servicePath)
+	c.checkDependencies(dto, registered)
+	c.checkInfrastructure(dto.Requires.Infrastructure, servicePath)
+	for name := range dto.Secrets {
+		if !envNamePattern.MatchString(name) {
+			c.add(at("secrets", name), "invalid secret name %q: use letters, digits and underscores", name)
+		}
+	}
+
+	// The domain model is the backstop: anything toDomainService rejects fails at grund up
+	svc, err := (&ServiceRepositoryImpl{}).toDomainService(dto, service.ServiceName(dto.Service.Name), servicePath)
+	if err != nil {
+		if len(c.issues) == 0 {
+			c.add(at("service"), "%v", err)
+		}
+		return nil, c.sorted()
+	}
+	svc.Environment.Source = configPath
+	svc.Environment.ReferenceLines = envRefLines(data)
+
+	c.checkReferences(svc, dto, v.envResolver)
+	if resources, ok := v.awsResources(svc); ok {
+		c.checkAWSReferences(svc, dto, resources)
+	}
+	return svc, c.sorted()
+}
+
+// awsResources returns the queues, topics and buckets declared by svc and the services
+// it requires, transitively, keyed by sqs, sns and s3
+// ok is false when a required service can't be loaded, as its declarations are unknown.
+func (v *ServiceConfigValidatorImpl) awsResources(svc *service.Service) (map[string]map[string]bool, bool) {
+	resources := map[string]map[string]bool{"sqs": {}, "sns": {}, "s3": {}}
+	seen := map[service.ServiceName]bool{service.ServiceName(svc.Name): true}
+	pending := []*service.Service{svc}
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+
+		infra := current.Dependencies.Infrastructure
+		if infra.SQS != nil {
+			for _, q := range infra.SQS.Queues {
+				resources["sqs"][q.Name] = true
+			}
+		}
+		if infra.SNS != nil {
+			for _, t := range infra.SNS.Topics {
+				resources["sns"][t.Name] = true
+			}
+		}
+		if infra.S3 != nil {
+			for _, b := range infra.S3.Buckets {
+				resources["s3"][b.Name] = true
+			}
+		}
+
+		for _, dep := range current.Dependencies.Services {
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			if v.services == nil {
+				return nil, false
+			}
+			depSvc, err := v.services.FindByName(dep)
+			if err != nil {
+				return nil, false
+			}
+			pending = append(pending, depSvc)
+		}
+	}
+	return resources, true
+}
+
+// yamlIssue converts a yaml.v3 error message into an issue
+func yamlIssue(msg string) ports.ValidationIssue {
+	if m := yamlErrorLine.FindStringSubmatch(msg); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return ports.ValidationIssue{Line: line, Message: m[2]}
+	}
+	return ports.ValidationIssue{Message: strings.TrimPrefix(msg, "yaml: ")}
+}
+
+// field is a path into grund.yaml: mapping keys are strings, sequence indexes ints
+type field []any
+
+func at(parts ...any) field {
+	return parts
+}
+
+func (f field) String() string {
+	var b strings.Builder
+	for _, part := range f {
+		switch p := part.(type) {
+		case int:
+			fmt.Fprintf(&b, "[%d]", p)
+		default:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			fmt.Fprint(&b, p)
+		}
+	}
+	return b.String()
+}
+
+// issueCollector accumulates issues, positioned with the YAML node tree
+type issueCollector struct {
+	root        *yaml.Node
+	issues      []ports.ValidationIssue
+	decodeLines map[int]bool // lines with type errors; their zero values aren't reported again
+}
+
+func (c *issueCollector) add(f field, format string, args ...any) {
+	node := c.locate(f)
+	if c.decodeLines[node.Line] {
+		return
+	}
+	c.issues = append(c.issues, ports.ValidationIssue{
+		Line:    node.Line,
+		Column:  node.Column,
+		Field:   f.String(),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// locate returns the node for a field, or the closest enclosing one when it is missing
+// For a mapping entry that is the key, so issues point at the field name.
+func (c *issueCollector) locate(f field) *yaml.Node {
+	pos, cur := c.root, c.root
+	for _, part := range f {
+		switch p := part.(type) {
+		case int:
+			if cur.Kind != yaml.SequenceNode || p >= len(cur.Content) {
+				return pos
+			}
+			cur = cur.Content[p]
+			pos = cur
+		default:
+			if cur.Kind != yaml.MappingNode {
+				return pos
+			}
+			found := false
+			for i := 0; i+1 < len(cur.Content); i += 2 {
+				if cur.Content[i].Value == fmt.Sprint(p) {
+					pos, cur = cur.Content[i], cur.Content[i+1]
+					found = true
+					break
+				}
+			}
+			if !found {
+				return pos
+			}
+		}
+	}
+	return pos
+}
+
+func (c *issueCollector) sorted() []ports.ValidationIssue {
+	sort.SliceStable(c.issues, func(i, j int) bool {
+		a, b := c.issues[i], c.issues[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return c.issues
+}
+
+// unknownFields reports mapping keys the DTO doesn't have, which yaml.v3 otherwise ignores
+func (c *issueCollector) unknownFields(node *yaml.Node, t reflect.Type, path field) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return // scalars such as plain dependency names; type errors are reported by Decode
+		}
+		fields, names := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			ft, ok := fields[key.Value]
+			if !ok {
+				c.issues = append(c.issues, ports.ValidationIssue{
+					Line:    key.Line,
+					Column:  key.Column,
+					Field:   append(path[:len(path):len(path)], key.Value).String(),
+					Message: fmt.Sprintf("unknown field %q (expected one of: %s)", key.Value, strings.Join(names, ", ")),
+				})
+				continue
+			}
+			c.unknownFields(node.Content[i+1], ft, append(path[:len(path):len(path)], key.Value))
+		}
+	case reflect.Slice:
+		if node.Kind == yaml.SequenceNode {
+			for i, item := range node.Content {
+				c.unknownFields(item, t.Elem(), append(path[:len(path):len(path)], i))
+			}
+		}
+	case reflect.Map:
+		if node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				c.unknownFields(node.Content[i+1], t.Elem(), append(path[:len(path):len(path)], node.Content[i].Value))
+			}
+		}
+	}
+}
+
+// yamlFields returns a struct's YAML field names and types
+func yamlFields(t reflect.Type) (map[string]reflect.Type, []string) {
+	fields := make(map[string]reflect.Type)
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+		names = append(names, name)
+	}
+	return fields, names
+}
+
+func (c *issueCollector) checkService(dto ServiceConfigDTO, servicePath string) {
+	info := dto.Service
+	if info.Name == "" {
+		c.add(at("service", "name"), "is required")
+	}
+
+	switch {
+	case info.Type == "":
+		c.add(at("service", "type"), "is required")
+	case !service.ServiceType(info.Type).IsValid():
+		types := make([]string, len(service.ServiceTypes))
+		for i, t := range service.ServiceTypes {
+			types[i] = string(t)
+		}
+		c.add(at("service", "type"), "unknown service type %q (expected one of: %s)", info.Type, strings.Join(types, ", "))
+	}
+
+	if _, err := service.NewPort(info.Port); err != nil {
+		c.add(at("service", "port"), "%v", err)
+	}
+
+	if info.Build == nil && info.Run == nil {
+		c.add(at("service"), "either build or run is required")
+	}
+	if info.Build != nil {
+		buildContext := resolveServicePath(servicePath, info.Build.Context)
+		if !c.checkPath(at("service", "build", "context"), buildContext, true) {
+			return
+		}
+		dockerfile := info.Build.Dockerfile
+		if dockerfile == "" {
+			dockerfile = defaultDockerfile
+		}
+		c.checkPath(at("service", "build", "dockerfile"), resolveServicePath(buildContext, dockerfile), false)
+	}
+
+	health := info.Health
+	if health.Endpoint == "" {
+		c.add(at("service", "health", "endpoint"), "is required")
+	}
+	for key, value := range map[string]string{"interval": health.Interval, "timeout": health.Timeout} {
+		if value == "" {
+			continue
+		}
+		if d, err := parseDuration(value); err != nil {
+			c.add(at("service", "health", key), "invalid duration %q (use e.g. 5s or 1m)", value)
+		} else if d <= 0 {
+			c.add(at("service", "health", key), "must be positive, got %s", value)
+		}
+	}
+	if health.Retries < 0 {
+		c.add(at("service", "health", "retries"), "must not be negative")
+	}
+}
+
+// checkPath reports a path from grund.yaml that doesn't exist or has the wrong kind
+func (c *issueCollector) checkPath(f field, path string, dir bool) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		c.add(f, "%s does not exist", path)
+		return false
+	}
+	if dir && !info.IsDir() {
+		c.add(f, "%s is not a directory", path)
+		return false
+	}
+	if !dir && info.IsDir() {
+		c.add(f, "%s is a directory, expected a file", path)
+		return false
+	}
+	return true
+}
+
+// checkOptionalPath checks a file or directory path when it is set
+func (c *issueCollector) checkOptionalPath(f field, servicePath, path string) {
+	if path == "" {
+		return
+	}
+	if _, err := os.Stat(resolveServicePath(servicePath, path)); err != nil {
+		c.add(f, "%s does not exist", resolveServicePath(servicePath, path))
+	}
+}
+
+func (c *issueCollector) checkDependencies(dto ServiceConfigDTO, registered map[service.ServiceName]bool) {
+	seen := make(map[string]bool)
+	for i, dep := range dto.Requires.Services {
+		f := at("requires", "services", i)
+		switch {
+		case dep.Name == "":
+			c.add(f, "service name is required")
+			continue
+		case dep.Name == dto.Service.Name:
+			c.add(f, "a service cannot depend on itself")
+		case seen[dep.Name]:
+			c.add(f, "duplicate dependency %s", dep.Name)
+		case registered != nil && !registered[service.ServiceName(dep.Name)]:
+			c.add(f, "service %s is not registered in services.yaml", dep.Name)
+		}
+		seen[dep.Name] = true
+
+		if _, err := service.ParseWaitCondition(dep.WaitFor); err != nil {
+			c.add(append(f, "wait_for"), "%v", err)
+		}
+	}
+}
+
+func (c *issueCollector) checkInfrastructure(infra InfrastructureConfigDTO, servicePath string) {
+	base := at("requires", "infrastructure")
+	sub := func(parts ...any) field {
+		return append(base[:len(base):len(base)], parts...)
+	}
+
+	if pg := infra.Postgres; pg != nil {
+		if pg.Database == "" {
+			c.add(sub("postgres", "database"), "is required")
+		}
+		c.checkOptionalPath(sub("postgres", "migrations"), servicePath, pg.Migrations)
+		c.checkOptionalPath(sub("postgres", "seed"), servicePath, pg.Seed)
+	}
+
+	if mongo := infra.MongoDB; mongo != nil {
+		if mongo.Database == "" {
+			c.add(sub("mongodb", "database"), "is required")
+		}
+		c.checkOptionalPath(sub("mongodb", "seed"), servicePath, mongo.Seed)
+	}
+
+	if infra.SQS != nil {
+		seen := make(map[string]bool)
+		for i, q := range infra.SQS.Queues {
+			c.checkName(sub("sqs", "queues", i, "name"), q.Name, "queue", seen)
+		}
+	}
+
+	if infra.SNS != nil {
+		seen := make(map[string]bool)
+		for i, topic := range infra.SNS.Topics {
+			c.checkName(sub("sns", "topics", i, "name"), topic.Name, "topic", seen)
+			for j, s := range topic.Subscriptions {
+				f := sub("sns", "topics", i, "subscriptions", j)
+				if !contains(snsProtocols, s.Protocol) {
+					c.add(append(f, "protocol"), "unknown protocol %q (expected one of: %s)", s.Protocol, strings.Join(snsProtocols, ", "))
+				}
+				if s.Endpoint == "" {
+					c.add(append(f, "endpoint"), "is required")
+				}
+				if policy, ok := s.Attributes["FilterPolicy"]; ok && !json.Valid([]byte(policy)) {
+					c.add(append(f, "attributes", "FilterPolicy"), "is not valid JSON")
+				}
+				if scope, ok := s.Attributes["FilterPolicyScope"]; ok && !contains(filterPolicyScopes, scope) {
+					c.add(append(f, "attributes", "FilterPolicyScope"), "unknown scope %q (expected one of: %s)", scope, strings.Join(filterPolicyScopes, ", "))
+				}
+			}
+		}
+	}
+
+	if infra.S3 != nil {
+		seen := make(map[string]bool)
+		for i, b := range infra.S3.Buckets {
+			c.checkName(sub("s3", "buckets", i, "name"), b.Name, "bucket", seen)
+			c.checkOptionalPath(sub("s3", "buckets", i, "seed"), servicePath, b.Seed)
+		}
+	}
+
+	if tunnel := infra.Tunnel; tunnel != nil {
+		if !contains(tunnelProviders, tunnel.Provider) {
+			c.add(sub("tunnel", "provider"), "unknown provider %q (expected one of: %s)", tunnel.Provider, strings.Join(tunnelProviders, ", "))
+		}
+		seen := make(map[string]bool)
+		for i, t := range tunnel.Targets {
+			c.checkName(sub("tunnel", "targets", i, "name"), t.Name, "tunnel target", seen)
+			if t.Host == "" {
+				c.add(sub("tunnel", "targets", i, "host"), "is required")
+			}
+			if t.Port == "" {
+				c.add(sub("tunnel", "targets", i, "port"), "is required")
+			}
+		}
+	}
+}
+
+// checkName checks that a resource name is set and unique
+func (c *issueCollector) checkName(f field, name, kind string, seen map[string]bool) {
+	if name == "" {
+		c.add(f, "%s name is required", kind)
+		return
+	}
+	if seen[name] {
+		c.add(f, "duplicate %s %s", kind, name)
+	}
+	seen[name] = true
+}
+
+// checkReferences resolves env_refs and SNS subscription endpoints against what the service declares
+func (c *issueCollector) checkReferences(svc *service.Service, dto ServiceConfigDTO, resolver ports.EnvironmentResolver) {
+	if resolver == nil {
+		return
+	}
+
+	// Dependencies resolve by name; their ports don't matter here
+	depPorts := make(map[string]int)
+	for _, dep := range svc.Dependencies.Services {
+		depPorts[dep.String()] = 0
+	}
+	ctx := ports.NewPreviewEnvironmentContext(svc, depPorts)
+	if tunnel := svc.Dependencies.Infrastructure.Tunnel; tunnel != nil {
+		for _, t := range tunnel.Targets {
+			ctx.Tunnel[t.Name] = ports.TunnelContext{Name: t.Name, PublicURL: "https://" + t.Name + "." + previewTunnelURLHost}
+		}
+	}
+
+	_, err := resolver.Resolve(svc.Environment.References, ctx)
+	var unresolved *ports.UnresolvedReferencesError
+	if errors.As(err, &unresolved) {
+		for _, ref := range unresolved.References {
+			c.add(at("env_refs", ref.Key), "%s: %s", ref.Placeholder, ref.Reason)
+		}
+	} else if err != nil {
+		c.add(at("env_refs"), "%v", err)
+	}
+
+	// Undeclared tunnels resolve to a pending placeholder, so check them explicitly
+	for key, value := range svc.Environment.References {
+		for _, m := range tunnelReference.FindAllStringSubmatch(value, -1) {
+			if _, ok := ctx.Tunnel[m[1]]; !ok {
+				c.add(at("env_refs", key), "tunnel %s is not declared under requires.infrastructure.tunnel.targets", m[1])
+			}
+		}
+	}
+
+	if dto.Requires.Infrastructure.SNS == nil {
+		return
+	}
+	for i, topic := range dto.Requires.Infrastructure.SNS.Topics {
+		for j, s := range topic.Subscriptions {
+			if s.Endpoint == "" {
+				continue
+			}
+			_, err := resolver.Resolve(map[string]string{"endpoint": s.Endpoint}, ctx)
+			if errors.As(err, &unresolved) {
+				for _, ref := range unresolved.References {
+					c.add(at("requires", "infrastructure", "sns", "topics", i, "subscriptions", j, "endpoint"), "%s: %s", ref.Placeholder, ref.Reason)
+				}
+			}
+		}
+	}
+}
+
+// checkAWSReferences reports queues, topics and buckets that neither the service nor the
+// services it requires declare, and LocalStack references when none of them need LocalStack
+// The resolver accepts any name, but undeclared resources are never created.
+func (c *issueCollector) checkAWSReferences(svc *service.Service, dto ServiceConfigDTO, resources map[string]map[string]bool) {
+	needsLocalStack := len(resources["sqs"])+len(resources["sns"])+len(resources["s3"]) > 0
+	check := func(f field, value string) {
+		reported := make(map[string]bool)
+		for _, m := range awsReference.FindAllStringSubmatch(value, -1) {
+			if resources[m[1]][m[2]] || reported[m[0]] {
+				continue
+			}
+			reported[m[0]] = true
+			c.add(f, "%s %s is not declared by %s or the services it requires", m[1], m[2], svc.Name)
+		}
+		if !needsLocalStack && localStackReference.MatchString(value) {
+			c.add(f, "localstack is not started: neither %s nor the services it requires declare sqs, sns or s3", svc.Name)
+		}
+	}
+
+	for key, value := range svc.Environment.References {
+		check(at("env_refs", key), value)
+	}
+	if dto.Requires.Infrastructure.SNS == nil {
+		return
+	}
+	for i, topic := range dto.Requires.Infrastructure.SNS.Topics {
+		for j, s := range topic.Subscriptions {
+			check(at("requires", "infrastructure", "sns", "topics", i, "subscriptions", j, "endpoint"), s.Endpoint)
+		}
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+	"github.com/vivekkundariya/grund/internal/infrastructure/generator"
+)
+
+// writeServiceDir writes grund.yaml and the given extra files into a temp service directory
+func writeServiceDir(t *testing.T, grundYaml string, files ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, f := range files {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	configPath := filepath.Join(dir, "grund.yaml")
+	if err := os.WriteFile(configPath, []byte(grundYaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return configPath
+}
+
+func validateYaml(t *testing.T, grundYaml string, registered map[service.ServiceName]bool, files ...string) (*service.Service, []ports.ValidationIssue) {
+	t.Helper()
+	validator := NewServiceConfigValidator(generator.NewEnvironmentResolver(), nil)
+	return validator.ValidateFile(writeServiceDir(t, grundYaml, files...), registered)
+}
+
+func TestServiceConfigValidator_Valid(t *testing.T) {
+	svc, issues := validateYaml(t, `service:
+  name: orders
+  type: go
+  port: 8080
+  build:
+    context: .
+  health:
+    endpoint: /health
+    interval: 5s
+
+requires:
+  services:
+    - name: payments
+      wait_for: healthy
+  infrastructure:
+    postgres:
+      database: orders
+      migrations: ./migrations
+    sqs:
+      queues:
+        - name: order-events
+    sns:
+      topics:
+        - name: orders
+          subscriptions:
+            - protocol: sqs
+              endpoint: "${sqs.order-events.arn}"
+              attributes:
+                FilterPolicy: '{"type": ["created"]}'
+
+env_refs:
+  DATABASE_URL: "postgres://${postgres.host}:${postgres.port}/${postgres.database}"
+  PAYMENTS_URL: "http://${payments.host}:${payments.port}"
+  QUEUE_URL: "${sqs.order-events.url}"
+`, map[service.ServiceName]bool{"orders": true, "payments": true}, "Dockerfile", "migrations/001.sql")
+
+	if len(issues) != 0 {
+		t.Fatalf("unexpected issues: %v", issues)
+	}
+	if svc == nil || svc.Name != "orders" {
+		t.Errorf("service = %+v, want orders", svc)
+	}
+}
+
+func TestServiceConfigValidator_Issues(t *testing.T) {
+	_, issues := validateYaml(t, `service:
+  name: orders
+  type: ruby
+  port: 8080
+  build:
+    context: .
+  health:
+    endpoint: /health
+    interval: 5x
+  replicas: 2
+
+requires:
+  services:
+    - billing
+  infrastructure:
+    postgres:
+      database: orders
+      migrations: ./migrations
+    sns:
+      topics:
+        - name: orders
+          subscriptions:
+            - protocol: smtp
+              endpoint: x
+
+env_refs:
+  CACHE: "${redis.host}"
+  QUEUE: "${sqs.orders.bogus}"
+`, map[service.ServiceName]bool{"orders": true})
+
+	want := []struct {
+		line  int
+		field string
+		text  string
+	}{
+		{3, "service.type", `unknown service type "ruby"`},
+		{5, "service.build.dockerfile", "does not exist"},
+		{9, "service.health.interval", "invalid duration"},
+		{10, "service.replicas", `unknown field "replicas"`},
+		{14, "requires.services[0]", "not registered"},
+		{18, "requires.infrastructure.postgres.migrations", "does not exist"},
+		{23, "requires.infrastructure.sns.topics[0].subscriptions[0].protocol", `unknown protocol "smtp"`},
+		{27, "env_refs.CACHE", "redis"},
+		{28, "env_refs.QUEUE", "bogus"},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("got %d issues, want %d:\n%v", len(issues), len(want), issues)
+	}
+	for i, w := range want {
+		got := issues[i]
+		if got.Line != w.line || got.Field != w.field || !strings.Contains(got.Message, w.text) {
+			t.Errorf("issue %d = %s, want line %d %s containing %q", i, got, w.line, w.field, w.text)
+		}
+	}
+	if issues[3].Column != 3 {
+		t.Errorf("unknown field column = %d, want 3", issues[3].Column)
+	}
+}
+
+func TestServiceConfigValidator_Syntax(t *testing.T) {
+	svc, issues := validateYaml(t, "service:\n  name: orders\n   type: go\n", nil)
+	if svc != nil || len(issues) != 1 || issues[0].Line == 0 {
+		t.Errorf("ValidateFile() = %v, %v; want one positioned syntax error", svc, issues)
+	}
+
+	svc, issues = validateYaml(t, "service:\n  name: orders\n  port: abc\n", nil)
+	var onPort []ports.ValidationIssue
+	for _, issue := range issues {
+		if issue.Line == 3 {
+			onPort = append(onPort, issue)
+		}
+	}
+	if svc != nil || len(onPort) != 1 || !strings.Contains(onPort[0].Message, "abc") {
+		t.Errorf("ValidateFile() = %v, %v; want only the type error at line 3", svc, issues)
+	}
+}
+
+func TestServiceConfigValidator_UndeclaredAWSResources(t *testing.T) {
+	// payments declares a queue and requires ledger, which declares a bucket
+	dependency := func(name, requires, infra string) string {
+		return filepath.Dir(writeServiceDir(t, `service:
+  name: `+name+`
+  type: go
+  port: 8080
+  run:
+    command: ./`+name+`
+  health:
+    endpoint: /health
+requires:
+  services: [`+requires+`]
+  infrastructure:
+`+infra, "Dockerfile"))
+	}
+	registry := &mockRegistryRepo{paths: map[string]string{
+		"payments": dependency("payments", "ledger", "    sqs:\n      queues:\n        - name: payment-events\n"),
+		"ledger":   dependency("ledger", "", "    s3:\n      buckets:\n        - name: receipts\n"),
+	}}
+	validator := NewServiceConfigValidator(generator.NewEnvironmentResolver(), NewServiceRepository(registry))
+
+	_, issues := validator.ValidateFile(writeServiceDir(t, `service:
+  name: orders
+  type: go
+  port: 8080
+  run:
+    command: ./orders
+  health:
+    endpoint: /health
+
+requires:
+  services:
+    - payments
+  infrastructure:
+    sns:
+      topics:
+        - name: orders
+          subscriptions:
+            - protocol: sqs
+              endpoint: "${sqs.payment-events.arn}"
+            - protocol: sqs
+              endpoint: "${sqs.refunds.arn}"
+
+env_refs:
+  PAYMENTS_QUEUE: "${sqs.payment-events.url}"
+  RECEIPTS: "${s3.receipts.name}"
+  TOPIC: "${sns.orders.arn}"
+  TYPO: "${sqs.oders.url}"
+`), nil)
+
+	want := []struct {
+		line  int
+		field string
+		text  string
+	}{
+		{21, "requires.infrastructure.sns.topics[0].subscriptions[1].endpoint", "sqs refunds is not declared by orders"},
+		{27, "env_refs.TYPO", "sqs oders is not declared by orders"},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("got %d issues, want %d:\n%v", len(issues), len(want), issues)
+	}
+	for i, w := range want {
+		got := issues[i]
+		if got.Line != w.line || got.Field != w.field || !strings.Contains(got.Message, w.text) {
+			t.Errorf("issue %d = %s, want line %d %s containing %q", i, got, w.line, w.field, w.text)
+		}
+	}
+}
+
+func TestServiceConfigValidator_LocalStackWithoutAWS(t *testing.T) {
+	_, issues := validateYaml(t, `service:
+  name: orders
+  type: go
+  port: 8080
+  run:
+    command: ./orders
+  health:
+    endpoint: /health
+
+env_refs:
+  AWS_ENDPOINT: "${localstack.endpoint}"
+`, nil)
+
+	if len(issues) != 1 || issues[0].Field != "env_refs.AWS_ENDPOINT" || !strings.Contains(issues[0].Message, "localstack is not started") {
+		t.Errorf("issues = %v, want one about localstack on env_refs.AWS_ENDPOINT", issues)
+	}
+}
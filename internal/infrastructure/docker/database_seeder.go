@@ -0,0 +1,209 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/config"
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
+	"github.com/vivekkundariya/grund/internal/ui"
+)
+
+// Seed markers live inside each database, so they disappear with the volume
+const (
+	postgresSeedTable = "grund_seed_history"
+	mongoSeedMarker   = "_grund_seed"
+)
+
+// DockerDatabaseSeeder implements DatabaseSeeder by running psql, mongosh and
+// mongoimport inside the infrastructure containers
+type DockerDatabaseSeeder struct {
+	runtime           *Runtime
+	postgresContainer string
+	mongoContainer    string
+}
+
+// NewDatabaseSeeder creates a new database seeder for the environment's containers
+func NewDatabaseSeeder(env *config.Environment, runtime *Runtime) ports.DatabaseSeeder {
+	return &DockerDatabaseSeeder{
+		runtime:           runtime,
+		postgresContainer: env.ContainerName("postgres"),
+		mongoContainer:    env.ContainerName("mongodb"),
+	}
+}
+
+// SeedPostgres runs the seed SQL (a file, or a directory of .sql files) as the database owner
+// The seed and its marker row are committed in one transaction, so a failed seed is retried next time
+func (s *DockerDatabaseSeeder) SeedPostgres(ctx context.Context, db infrastructure.PostgresDatabase, force bool) (bool, error) {
+	if db.Seed == "" {
+		return false, nil
+	}
+
+	files, err := seedFiles(db.Seed, ".sql")
+	if err != nil {
+		return false, err
+	}
+
+	owner, _ := db.Credentials()
+	if _, err := runPsql(ctx, s.runtime, s.postgresContainer, owner, db.Database, createSeedTableSQL()); err != nil {
+		return false, fmt.Errorf("failed to create %s table: %w", postgresSeedTable, err)
+	}
+
+	if !force {
+		out, err := runPsql(ctx, s.runtime, s.postgresContainer, owner, db.Database, fmt.Sprintf("SELECT count(*) FROM %s;\n", postgresSeedTable))
+		if err != nil {
+			return false, fmt.Errorf("failed to read seed marker: %w", err)
+		}
+		if n, _ := strconv.Atoi(strings.TrimSpace(out)); n > 0 {
+			ui.Debug("Postgres database already seeded: %s", db.Database)
+			return false, nil
+		}
+	}
+
+	var script strings.Builder
+	script.WriteString("BEGIN;\n")
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return false, fmt.Errorf("failed to read seed file %s: %w", file, err)
+		}
+		script.Write(data)
+		script.WriteString("\n;\n")
+	}
+	fmt.Fprintf(&script, "INSERT INTO %s DEFAULT VALUES;\nCOMMIT;\n", postgresSeedTable)
+
+	ui.SubStep("Seeding Postgres database: %s", db.Database)
+	if _, err := runPsql(ctx, s.runtime, s.postgresContainer, owner, db.Database, script.String()); err != nil {
+		return false, fmt.Errorf("seed failed: %w", err)
+	}
+	return true, nil
+}
+
+// SeedMongoDB imports <collection>.json / <collection>.jsonl files into the database
+// .json files may hold a single document or an array; .jsonl files hold one document per line.
+// With force, each collection is dropped before it is imported again.
+func (s *DockerDatabaseSeeder) SeedMongoDB(ctx context.Context, db infrastructure.MongoDBDatabase, force bool) (bool, error) {
+	if db.Seed == "" {
+		return false, nil
+	}
+
+	files, err := seedFiles(db.Seed, ".json", ".jsonl")
+	if err != nil {
+		return false, err
+	}
+
+	if !force {
+		out, err := runMongosh(ctx, s.runtime, s.mongoContainer, db.Database,
+			fmt.Sprintf("db.getCollection(%q).countDocuments()", mongoSeedMarker))
+		if err != nil {
+			return false, fmt.Errorf("failed to read seed marker: %w", err)
+		}
+		if n, _ := strconv.Atoi(strings.TrimSpace(out)); n > 0 {
+			ui.Debug("MongoDB database already seeded: %s", db.Database)
+			return false, nil
+		}
+	}
+
+	ui.SubStep("Seeding MongoDB database: %s", db.Database)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return false, fmt.Errorf("failed to read seed file %s: %w", file, err)
+		}
+
+		collection := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		args := []string{"--quiet", "--db", db.Database, "--collection", collection}
+		if isJSONArray(data) {
+			args = append(args, "--jsonArray")
+		}
+		if force {
+			args = append(args, "--drop")
+		}
+
+		ui.Debug("Importing %s into %s.%s", filepath.Base(file), db.Database, collection)
+		if err := runMongoImport(ctx, s.runtime, s.mongoContainer, args, data); err != nil {
+			return false, fmt.Errorf("failed to import %s: %w", filepath.Base(file), err)
+		}
+	}
+
+	if _, err := runMongosh(ctx, s.runtime, s.mongoContainer, db.Database,
+		fmt.Sprintf("db.getCollection(%q).insertOne({seededAt: new Date()})", mongoSeedMarker)); err != nil {
+		return false, fmt.Errorf("failed to write seed marker: %w", err)
+	}
+	return true, nil
+}
+
+// seedFiles returns the seed path itself, or the matching files in a seed directory sorted by name
+func seedFiles(path string, extensions ...string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("seed path not found: %s", path)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		for _, ext := range extensions {
+			if strings.HasSuffix(entry.Name(), ext) {
+				files = append(files, filepath.Join(path, entry.Name()))
+				break
+			}
+		}
+	}
+	sort.Strings(files)
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no seed files (%s) found in %s", strings.Join(extensions, ", "), path)
+	}
+	return files, nil
+}
+
+// isJSONArray reports whether a JSON document is a top-level array
+func isJSONArray(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("["))
+}
+
+func createSeedTableSQL() string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  seeded_at timestamptz NOT NULL DEFAULT now()
+);
+`, postgresSeedTable)
+}
+
+// runMongosh evaluates a script against a database in the mongodb container
+func runMongosh(ctx context.Context, runtime *Runtime, container, database, script string) (string, error) {
+	cmd := runtime.EngineCommand("exec", container, "mongosh", "--quiet", database, "--eval", script)
+	output, err := runtime.CombinedOutput(ctx, cmd)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
+
+// runMongoImport pipes documents into mongoimport in the mongodb container
+func runMongoImport(ctx context.Context, runtime *Runtime, container string, importArgs []string, data []byte) error {
+	cmd := runtime.EngineCommand(append([]string{"exec", "-i", container, "mongoimport"}, importArgs...)...)
+	cmd.Stdin = bytes.NewReader(data)
+	output, err := runtime.CombinedOutput(ctx, cmd)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSeedFiles_Directory(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"users.jsonl", "products.json", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := seedFiles(dir, ".json", ".jsonl")
+	if err != nil {
+		t.Fatalf("seedFiles() error: %v", err)
+	}
+
+	want := []string{filepath.Join(dir, "products.json"), filepath.Join(dir, "users.jsonl")}
+	if len(files) != len(want) {
+		t.Fatalf("seedFiles() = %v, want %v", files, want)
+	}
+	for i := range want {
+		if files[i] != want[i] {
+			t.Errorf("seedFiles()[%d] = %s, want %s", i, files[i], want[i])
+		}
+	}
+}
+
+func TestSeedFiles_SingleFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "seed.sql")
+	if err := os.WriteFile(file, []byte("SELECT 1;"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := seedFiles(file, ".sql")
+	if err != nil {
+		t.Fatalf("seedFiles() error: %v", err)
+	}
+	if len(files) != 1 || files[0] != file {
+		t.Errorf("seedFiles() = %v, want [%s]", files, file)
+	}
+}
+
+func TestSeedFiles_EmptyDirectory(t *testing.T) {
+	if _, err := seedFiles(t.TempDir(), ".sql"); err == nil {
+		t.Error("Expected error for directory without seed files")
+	}
+	if _, err := seedFiles(filepath.Join(t.TempDir(), "missing"), ".sql"); err == nil {
+		t.Error("Expected error for missing seed path")
+	}
+}
+
+func TestIsJSONArray(t *testing.T) {
+	if !isJSONArray([]byte("\n  [{\"a\": 1}]")) {
+		t.Error("isJSONArray() = false for array")
+	}
+	if isJSONArray([]byte(`{"a": 1}`)) {
+		t.Error("isJSONArray() = true for single document")
+	}
+}
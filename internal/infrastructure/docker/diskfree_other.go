@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package docker
+
+import "errors"
+
+// diskFree is not implemented on this platform, so the disk space check is skipped
+func diskFree(path string) (uint64, error) {
+	return 0, errors.New("disk space check not supported on this platform")
+}
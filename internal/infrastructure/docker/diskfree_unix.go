@@ -0,0 +1,14 @@
+//go:build linux || darwin
+
+package docker
+
+import "syscall"
+
+// diskFree returns the bytes available to unprivileged users on path's filesystem
+func diskFree(path string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), nil
+}
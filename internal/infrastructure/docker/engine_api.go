@@ -0,0 +1,337 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/config"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+	"github.com/vivekkundariya/grund/internal/ui"
+	"gopkg.in/yaml.v3"
+)
+
+// Compose labels set on every container of a project
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+)
+
+// engineRequestTimeout bounds a single Engine API request
+const engineRequestTimeout = 5 * time.Second
+
+// EngineAPIOrchestrator implements ContainerOrchestrator, answering status queries
+// from the Engine API over the local socket instead of running `compose ps` per service.
+// Lifecycle and logs go through the embedded compose CLI adapter, which also serves
+// status when the API is unreachable (e.g. nerdctl, or the socket isn't enabled).
+type EngineAPIOrchestrator struct {
+	*DockerOrchestrator
+	client *engineClient // nil when the runtime has no Docker-compatible API
+
+	mu          sync.Mutex
+	unavailable bool // set after the first failed request; later calls go straight to the CLI
+}
+
+// NewEngineAPIOrchestrator creates an orchestrator that reads status from the Engine API
+// The socket is taken from DOCKER_HOST when set, otherwise from the runtime.
+func NewEngineAPIOrchestrator(workingDir string, env *config.Environment, runtime *Runtime) ports.ContainerOrchestrator {
+	return &EngineAPIOrchestrator{
+		DockerOrchestrator: newDockerOrchestrator(workingDir, env, runtime),
+		client:             newEngineClient(os.Getenv("DOCKER_HOST"), runtime.Socket),
+	}
+}
+
+// GetServiceStatus gets the status of a service
+func (e *EngineAPIOrchestrator) GetServiceStatus(ctx context.Context, name service.ServiceName) (ports.ServiceStatus, error) {
+	containers, err := e.inspectProject(ctx, name.String())
+	if err != nil {
+		return e.DockerOrchestrator.GetServiceStatus(ctx, name)
+	}
+	return serviceStatus(name.String(), containers), nil
+}
+
+// GetAllServiceStatuses gets status of all services in the compose files
+// Services without a container are reported as not running.
+func (e *EngineAPIOrchestrator) GetAllServiceStatuses(ctx context.Context) ([]ports.ServiceStatus, error) {
+	allFiles := e.allComposeFiles()
+	if len(allFiles) == 0 {
+		return []ports.ServiceStatus{}, nil
+	}
+
+	names, err := composeServiceNames(allFiles)
+	if err != nil {
+		return e.DockerOrchestrator.GetAllServiceStatuses(ctx)
+	}
+
+	containers, err := e.inspectProject(ctx, "")
+	if err != nil {
+		return e.DockerOrchestrator.GetAllServiceStatuses(ctx)
+	}
+
+	byService := make(map[string][]engineContainer)
+	for _, c := range containers {
+		svcName := c.Config.Labels[composeServiceLabel]
+		byService[svcName] = append(byService[svcName], c)
+	}
+
+	statuses := make([]ports.ServiceStatus, 0, len(names))
+	for _, svcName := range names {
+		statuses = append(statuses, serviceStatus(svcName, byService[svcName]))
+	}
+	return statuses, nil
+}
+
+// inspectProject lists the project's containers (optionally one service's) and inspects them concurrently
+func (e *EngineAPIOrchestrator) inspectProject(ctx context.Context, serviceName string) ([]engineContainer, error) {
+	e.mu.Lock()
+	unavailable := e.unavailable || e.client == nil
+	e.mu.Unlock()
+	if unavailable {
+		return nil, fmt.Errorf("engine API not available")
+	}
+
+	labels := []string{composeProjectLabel + "=" + e.projectName}
+	if serviceName != "" {
+		labels = append(labels, composeServiceLabel+"="+serviceName)
+	}
+
+	containers, err := e.client.inspectByLabels(ctx, labels)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		ui.Debug("Engine API unavailable, falling back to compose CLI: %v", err)
+		e.mu.Lock()
+		e.unavailable = true
+		e.mu.Unlock()
+		return nil, err
+	}
+	return containers, nil
+}
+
+// serviceStatus builds a service's status from its containers
+// With several replicas the first running one wins.
+func serviceStatus(name string, containers []engineContainer) ports.ServiceStatus {
+	if len(containers) == 0 {
+		return ports.ServiceStatus{
+			Name:   name,
+			Status: "not running",
+			Health: "unknown",
+		}
+	}
+
+	sort.Slice(containers, func(i, j int) bool {
+		if running := containers[i].State.Running; running != containers[j].State.Running {
+			return running
+		}
+		return containers[i].Name < containers[j].Name
+	})
+	c := containers[0]
+
+	status := ports.ServiceStatus{
+		Name:         name,
+		Container:    strings.TrimPrefix(c.Name, "/"),
+		Status:       strings.ToLower(c.State.Status),
+		Health:       "-",
+		RestartCount: c.RestartCount,
+		ExitCode:     c.State.ExitCode,
+	}
+	if c.State.Health != nil && c.State.Health.Status != "" {
+		status.Health = c.State.Health.Status
+	}
+	if startedAt, err := time.Parse(time.RFC3339Nano, c.State.StartedAt); err == nil && startedAt.Year() > 1 {
+		status.StartedAt = startedAt
+	}
+
+	status.Ports = c.publishedPorts()
+	if len(status.Ports) > 0 {
+		status.Endpoint = fmt.Sprintf("http://localhost:%d", status.Ports[0].HostPort)
+	}
+
+	return status
+}
+
+// engineContainer is the subset of GET /containers/{id}/json that grund uses
+type engineContainer struct {
+	ID           string `json:"Id"`
+	Name         string `json:"Name"`
+	RestartCount int    `json:"RestartCount"`
+	State        struct {
+		Status    string `json:"Status"`
+		Running   bool   `json:"Running"`
+		ExitCode  int    `json:"ExitCode"`
+		StartedAt string `json:"StartedAt"`
+		Health    *struct {
+			Status string `json:"Status"`
+		} `json:"Health"`
+	} `json:"State"`
+	Config struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"Config"`
+	NetworkSettings struct {
+		Ports map[string][]struct {
+			HostIP   string `json:"HostIp"`
+			HostPort string `json:"HostPort"`
+		} `json:"Ports"`
+	} `json:"NetworkSettings"`
+}
+
+// publishedPorts returns the ports bound on the host, ordered by container port
+// IPv4 and IPv6 bindings of the same port are reported once.
+func (c engineContainer) publishedPorts() []ports.PortBinding {
+	seen := make(map[ports.PortBinding]bool)
+	var bindings []ports.PortBinding
+	for spec, hostBindings := range c.NetworkSettings.Ports {
+		portStr, protocol, _ := strings.Cut(spec, "/")
+		containerPort, err := strconv.Atoi(portStr)
+		if err != nil {
+			continue
+		}
+		for _, hb := range hostBindings {
+			hostPort, err := strconv.Atoi(hb.HostPort)
+			if err != nil || hostPort == 0 {
+				continue
+			}
+			binding := ports.PortBinding{HostPort: hostPort, ContainerPort: containerPort, Protocol: protocol}
+			if !seen[binding] {
+				seen[binding] = true
+				bindings = append(bindings, binding)
+			}
+		}
+	}
+
+	sort.Slice(bindings, func(i, j int) bool {
+		if bindings[i].ContainerPort != bindings[j].ContainerPort {
+			return bindings[i].ContainerPort < bindings[j].ContainerPort
+		}
+		return bindings[i].HostPort < bindings[j].HostPort
+	})
+	return bindings
+}
+
+// engineClient is a minimal client for the Docker Engine API (also served by Podman)
+type engineClient struct {
+	http    *http.Client
+	baseURL string
+}
+
+// newEngineClient connects to dockerHost (unix:// or tcp://) when set, otherwise to socket
+// Returns nil when there is nothing to connect to.
+func newEngineClient(dockerHost, socket string) *engineClient {
+	if strings.HasPrefix(dockerHost, "tcp://") {
+		return &engineClient{
+			http:    &http.Client{Timeout: engineRequestTimeout},
+			baseURL: "http://" + strings.TrimPrefix(dockerHost, "tcp://"),
+		}
+	}
+	if strings.HasPrefix(dockerHost, "unix://") {
+		socket = strings.TrimPrefix(dockerHost, "unix://")
+	}
+	if socket == "" {
+		return nil
+	}
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		},
+	}
+	return &engineClient{
+		http:    &http.Client{Transport: transport, Timeout: engineRequestTimeout},
+		baseURL: "http://engine",
+	}
+}
+
+// inspectByLabels lists all containers (running or not) matching every label and inspects each
+func (c *engineClient) inspectByLabels(ctx context.Context, labels []string) ([]engineContainer, error) {
+	filters, err := json.Marshal(map[string][]string{"label": labels})
+	if err != nil {
+		return nil, err
+	}
+
+	var listed []struct {
+		ID string `json:"Id"`
+	}
+	if err := c.get(ctx, "/containers/json?all=1&filters="+url.QueryEscape(string(filters)), &listed); err != nil {
+		return nil, err
+	}
+
+	containers := make([]engineContainer, len(listed))
+	errs := make([]error, len(listed))
+	var wg sync.WaitGroup
+	for i, l := range listed {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			errs[i] = c.get(ctx, "/containers/"+id+"/json", &containers[i])
+		}(i, l.ID)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return containers, nil
+}
+
+// get requests an API path and decodes the JSON response into v
+func (c *engineClient) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("engine API %s returned status %d", path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// composeServiceNames returns the services defined across compose files, sorted
+// Reading the files directly avoids a `compose config` process.
+func composeServiceNames(files []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var names []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read compose file: %w", err)
+		}
+
+		var compose struct {
+			Services map[string]yaml.Node `yaml:"services"`
+		}
+		if err := yaml.Unmarshal(data, &compose); err != nil {
+			return nil, fmt.Errorf("failed to parse compose file %s: %w", file, err)
+		}
+
+		for name := range compose.Services {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
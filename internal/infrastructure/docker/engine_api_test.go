@@ -0,0 +1,233 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/config"
+)
+
+const engineInspectAPI = `{
+	"Id": "c1",
+	"Name": "/grund-api",
+	"RestartCount": 2,
+	"State": {"Status": "running", "Running": true, "ExitCode": 0, "StartedAt": "2026-01-02T03:04:05.123456789Z", "Health": {"Status": "healthy"}},
+	"Config": {"Labels": {"com.docker.compose.project": "grund", "com.docker.compose.service": "api"}},
+	"NetworkSettings": {"Ports": {
+		"8080/tcp": [{"HostIp": "0.0.0.0", "HostPort": "9080"}, {"HostIp": "::", "HostPort": "9080"}],
+		"9090/tcp": null
+	}}
+}`
+
+const engineInspectWorker = `{
+	"Id": "c2",
+	"Name": "/grund-worker",
+	"State": {"Status": "exited", "Running": false, "ExitCode": 137, "StartedAt": "2026-01-02T03:04:05Z"},
+	"Config": {"Labels": {"com.docker.compose.project": "grund", "com.docker.compose.service": "worker"}},
+	"NetworkSettings": {"Ports": {}}
+}`
+
+// newFakeEngine serves a fake Engine API on a unix socket and records the label filters it was asked for
+func newFakeEngine(t *testing.T) (socket string, filters *[]string) {
+	t.Helper()
+
+	// Unix socket paths are length-limited, so avoid the long t.TempDir()
+	dir, err := os.MkdirTemp("", "grund")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket = filepath.Join(dir, "engine.sock")
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var recorded []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		var f map[string][]string
+		if err := json.Unmarshal([]byte(r.URL.Query().Get("filters")), &f); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		recorded = append(recorded, strings.Join(f["label"], ","))
+
+		ids := []string{`{"Id":"c1"}`, `{"Id":"c2"}`}
+		if strings.Contains(recorded[len(recorded)-1], "service=api") {
+			ids = ids[:1]
+		}
+		w.Write([]byte("[" + strings.Join(ids, ",") + "]"))
+	})
+	mux.HandleFunc("/containers/c1/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(engineInspectAPI))
+	})
+	mux.HandleFunc("/containers/c2/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(engineInspectWorker))
+	})
+
+	server := httptest.NewUnstartedServer(mux)
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return socket, &recorded
+}
+
+// writeComposeFile writes a compose file defining the given services
+func writeComposeFile(t *testing.T, dir string, services ...string) string {
+	t.Helper()
+	content := "services:\n"
+	for _, svc := range services {
+		content += "  " + svc + ":\n    image: " + svc + "\n"
+	}
+	path := filepath.Join(dir, services[0], "docker-compose.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEngineAPIOrchestrator_GetAllServiceStatuses(t *testing.T) {
+	socket, filters := newFakeEngine(t)
+	t.Setenv("DOCKER_HOST", "unix://"+socket)
+
+	rt, runner := newFakeRuntime(t, "docker compose")
+	orchestrator := NewEngineAPIOrchestrator("/test/workdir", config.DefaultEnvironment(), rt)
+
+	dir := t.TempDir()
+	orchestrator.SetComposeFiles([]string{
+		writeComposeFile(t, dir, "worker", "api"),
+		writeComposeFile(t, dir, "redis"),
+	})
+
+	statuses, err := orchestrator.GetAllServiceStatuses(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllServiceStatuses() error: %v", err)
+	}
+
+	if len(runner.commands) != 0 {
+		t.Errorf("expected no CLI commands, got %v", runner.commands)
+	}
+	if len(*filters) != 1 || (*filters)[0] != "com.docker.compose.project=grund" {
+		t.Errorf("label filters = %v, want one project filter", *filters)
+	}
+
+	if len(statuses) != 3 {
+		t.Fatalf("got %d statuses, want 3: %+v", len(statuses), statuses)
+	}
+
+	api := statuses[0]
+	if api.Name != "api" || api.Container != "grund-api" || api.Status != "running" || api.Health != "healthy" || api.RestartCount != 2 {
+		t.Errorf("api status = %+v", api)
+	}
+	wantStarted := time.Date(2026, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	if !api.StartedAt.Equal(wantStarted) {
+		t.Errorf("api StartedAt = %v, want %v", api.StartedAt, wantStarted)
+	}
+	wantPorts := []ports.PortBinding{{HostPort: 9080, ContainerPort: 8080, Protocol: "tcp"}}
+	if len(api.Ports) != 1 || api.Ports[0] != wantPorts[0] {
+		t.Errorf("api Ports = %+v, want %+v", api.Ports, wantPorts)
+	}
+	if api.Endpoint != "http://localhost:9080" {
+		t.Errorf("api Endpoint = %q", api.Endpoint)
+	}
+
+	if redis := statuses[1]; redis.Name != "redis" || redis.Status != "not running" {
+		t.Errorf("redis status = %+v, want not running", redis)
+	}
+
+	worker := statuses[2]
+	if worker.Name != "worker" || worker.Status != "exited" || worker.ExitCode != 137 || worker.Health != "-" {
+		t.Errorf("worker status = %+v", worker)
+	}
+}
+
+func TestEngineAPIOrchestrator_GetServiceStatus(t *testing.T) {
+	socket, filters := newFakeEngine(t)
+	t.Setenv("DOCKER_HOST", "unix://"+socket)
+
+	rt, _ := newFakeRuntime(t, "docker compose")
+	env := &config.Environment{Name: "review"}
+	orchestrator := NewEngineAPIOrchestrator("/test/workdir", env, rt)
+
+	status, err := orchestrator.GetServiceStatus(context.Background(), "api")
+	if err != nil {
+		t.Fatalf("GetServiceStatus() error: %v", err)
+	}
+	if status.Status != "running" || status.Endpoint != "http://localhost:9080" {
+		t.Errorf("GetServiceStatus() = %+v", status)
+	}
+
+	want := "com.docker.compose.project=grund-review,com.docker.compose.service=api"
+	if len(*filters) != 1 || (*filters)[0] != want {
+		t.Errorf("label filters = %v, want [%s]", *filters, want)
+	}
+}
+
+func TestEngineAPIOrchestrator_FallsBackToCLI(t *testing.T) {
+	// Nothing listens on the socket, so status comes from `compose ps`
+	t.Setenv("DOCKER_HOST", "unix://"+filepath.Join(t.TempDir(), "missing.sock"))
+
+	rt, runner := newFakeRuntime(t, "docker compose")
+	runner.output["docker compose"] = `{"Name":"grund-api","State":"exited","ExitCode":1,"Publishers":[]}`
+	orchestrator := NewEngineAPIOrchestrator("/test/workdir", config.DefaultEnvironment(), rt)
+	orchestrator.SetComposeFiles([]string{"/tmp/api/docker-compose.yaml"})
+
+	for i := 0; i < 2; i++ {
+		status, err := orchestrator.GetServiceStatus(context.Background(), "api")
+		if err != nil {
+			t.Fatalf("GetServiceStatus() error: %v", err)
+		}
+		if status.Status != "exited" || status.ExitCode != 1 {
+			t.Errorf("GetServiceStatus() = %+v, want exited with code 1", status)
+		}
+	}
+
+	if len(runner.commands) != 2 {
+		t.Errorf("expected one compose ps per call, got %v", runner.commands)
+	}
+}
+
+func TestNewEngineClient(t *testing.T) {
+	tests := []struct {
+		name       string
+		dockerHost string
+		socket     string
+		wantNil    bool
+		wantURL    string
+	}{
+		{"runtime socket", "", "/var/run/docker.sock", false, "http://engine"},
+		{"no socket", "", "", true, ""},
+		{"DOCKER_HOST unix", "unix:///tmp/podman.sock", "", false, "http://engine"},
+		{"DOCKER_HOST tcp", "tcp://127.0.0.1:2375", "/var/run/docker.sock", false, "http://127.0.0.1:2375"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newEngineClient(tt.dockerHost, tt.socket)
+			if tt.wantNil {
+				if client != nil {
+					t.Errorf("newEngineClient() = %+v, want nil", client)
+				}
+				return
+			}
+			if client == nil || client.baseURL != tt.wantURL {
+				t.Errorf("newEngineClient() = %+v, want base URL %s", client, tt.wantURL)
+			}
+		})
+	}
+}
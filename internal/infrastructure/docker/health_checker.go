@@ -17,14 +17,19 @@ type HTTPHealthChecker struct {
 // NewHTTPHealthChecker creates a new HTTP health checker
 func NewHTTPHealthChecker() ports.HealthChecker {
 	return &HTTPHealthChecker{
-		client: &http.Client{
-			Timeout: 5 * time.Second,
-		},
+		client: &http.Client{},
 	}
 }
 
 // CheckHealth checks if an endpoint is healthy
-func (h *HTTPHealthChecker) CheckHealth(ctx context.Context, endpoint string, timeout int) error {
+// Like the compose healthcheck (curl -f), any status below 400 counts as healthy.
+func (h *HTTPHealthChecker) CheckHealth(ctx context.Context, endpoint string, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
@@ -36,7 +41,7 @@ func (h *HTTPHealthChecker) CheckHealth(ctx context.Context, endpoint string, ti
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode >= http.StatusBadRequest {
 		return fmt.Errorf("health check returned status %d", resp.StatusCode)
 	}
 
@@ -44,27 +49,25 @@ func (h *HTTPHealthChecker) CheckHealth(ctx context.Context, endpoint string, ti
 }
 
 // WaitForHealthy waits for a service to become healthy
-func (h *HTTPHealthChecker) WaitForHealthy(ctx context.Context, endpoint string, interval, timeout, retries int) error {
-	intervalDuration := time.Duration(interval) * time.Second
-	timeoutDuration := time.Duration(timeout) * time.Second
-
-	deadline := time.Now().Add(timeoutDuration * time.Duration(retries))
+func (h *HTTPHealthChecker) WaitForHealthy(ctx context.Context, endpoint string, interval, timeout time.Duration, retries int) error {
+	if retries < 1 {
+		retries = 1
+	}
 
+	var lastErr error
 	for i := 0; i < retries; i++ {
-		if time.Now().After(deadline) {
-			return fmt.Errorf("health check timeout after %d retries", retries)
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(interval):
+			}
 		}
 
-		if err := h.CheckHealth(ctx, endpoint, timeout); err == nil {
+		if lastErr = h.CheckHealth(ctx, endpoint, timeout); lastErr == nil {
 			return nil
 		}
-
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(intervalDuration):
-		}
 	}
 
-	return fmt.Errorf("health check failed after %d retries", retries)
+	return fmt.Errorf("not healthy after %d checks: %w", retries, lastErr)
 }
@@ -0,0 +1,48 @@
+package docker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPHealthChecker_WaitForHealthy(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Unavailable for the first two checks, like a service still booting
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	checker := NewHTTPHealthChecker()
+	if err := checker.WaitForHealthy(context.Background(), server.URL+"/health", time.Millisecond, time.Second, 5); err != nil {
+		t.Fatalf("WaitForHealthy() error: %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("Expected 3 checks, got %d", got)
+	}
+}
+
+func TestHTTPHealthChecker_WaitForHealthy_GivesUp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	checker := NewHTTPHealthChecker()
+	err := checker.WaitForHealthy(context.Background(), server.URL, time.Millisecond, time.Second, 3)
+	if err == nil {
+		t.Fatal("Expected error for an endpoint that never becomes healthy")
+	}
+	if !strings.Contains(err.Error(), "after 3 checks") || !strings.Contains(err.Error(), "status 500") {
+		t.Errorf("error = %q, want the check count and last status", err)
+	}
+}
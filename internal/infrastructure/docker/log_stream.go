@@ -0,0 +1,239 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+)
+
+// logLevelRanks orders the levels accepted by --level
+var logLevelRanks = map[string]int{
+	"trace": 0,
+	"debug": 1,
+	"info":  2,
+	"warn":  3,
+	"error": 4,
+	"fatal": 5,
+}
+
+// logLevelAliases maps common spellings to the canonical level names
+var logLevelAliases = map[string]string{
+	"warning":  "warn",
+	"err":      "error",
+	"critical": "fatal",
+	"panic":    "fatal",
+}
+
+// jsonLevelKeys and jsonMessageKeys are probed in order when a line is a JSON object
+var (
+	jsonLevelKeys   = []string{"level", "lvl", "severity"}
+	jsonMessageKeys = []string{"msg", "message"}
+)
+
+// normalizeLogLevel returns the canonical level name, or "" if it is not recognised
+func normalizeLogLevel(level string) string {
+	level = strings.ToLower(strings.TrimSpace(level))
+	if alias, ok := logLevelAliases[level]; ok {
+		level = alias
+	}
+	if _, ok := logLevelRanks[level]; !ok {
+		return ""
+	}
+	return level
+}
+
+// logFilter applies --grep and --level to parsed entries
+type logFilter struct {
+	grep     *regexp.Regexp
+	minLevel int
+}
+
+// newLogFilter validates the filter options
+func newLogFilter(opts ports.LogOptions) (*logFilter, error) {
+	filter := &logFilter{minLevel: -1}
+
+	if opts.Grep != "" {
+		re, err := regexp.Compile(opts.Grep)
+		if err != nil {
+			return nil, fmt.Errorf("invalid grep pattern: %w", err)
+		}
+		filter.grep = re
+	}
+
+	if opts.Level != "" {
+		level := normalizeLogLevel(opts.Level)
+		if level == "" {
+			return nil, fmt.Errorf("invalid log level %q (use trace, debug, info, warn, error or fatal)", opts.Level)
+		}
+		filter.minLevel = logLevelRanks[level]
+	}
+
+	return filter, nil
+}
+
+// match reports whether an entry passes the filter
+// Entries without a parsed level can't be judged and always pass the level check.
+func (f *logFilter) match(entry ports.LogEntry) bool {
+	if f.minLevel >= 0 && entry.Level != "" && logLevelRanks[entry.Level] < f.minLevel {
+		return false
+	}
+	if f.grep != nil && !f.grep.MatchString(entry.Message) {
+		return false
+	}
+	return true
+}
+
+// parseLogLine parses a line of `docker compose logs --timestamps --no-log-prefix` output
+// JSON lines keep their raw text as the message but contribute their level.
+func parseLogLine(serviceName, line string) ports.LogEntry {
+	entry := ports.LogEntry{Service: serviceName, Message: line}
+
+	if ts, rest, ok := strings.Cut(line, " "); ok {
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			entry.Timestamp = t
+			entry.Message = rest
+		}
+	}
+
+	trimmed := strings.TrimSpace(entry.Message)
+	if !strings.HasPrefix(trimmed, "{") {
+		return entry
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(trimmed), &fields); err != nil {
+		return entry
+	}
+	for _, key := range jsonLevelKeys {
+		if value, ok := fields[key].(string); ok {
+			entry.Level = normalizeLogLevel(value)
+			break
+		}
+	}
+	return entry
+}
+
+// readLogLines parses and filters lines from r and sends matching entries to out
+func readLogLines(ctx context.Context, serviceName string, r io.Reader, filter *logFilter, out chan<- ports.LogEntry) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		entry := parseLogLine(serviceName, scanner.Text())
+		if !filter.match(entry) {
+			continue
+		}
+		select {
+		case out <- entry:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// logStream multiplexes per-service log readers into a single ports.LogStream
+type logStream struct {
+	entries chan ports.LogEntry
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	// sorted buffers everything and orders it by timestamp (non-follow mode)
+	sorted   bool
+	buffered []ports.LogEntry
+	drained  bool
+
+	mu  sync.Mutex
+	err error
+}
+
+// newLogStream creates a stream; the caller starts one reader per service with start
+func newLogStream(cancel context.CancelFunc, sorted bool) *logStream {
+	return &logStream{
+		entries: make(chan ports.LogEntry, 256),
+		cancel:  cancel,
+		sorted:  sorted,
+	}
+}
+
+// start runs a reader in the background and closes the stream once all readers are done
+func (s *logStream) start(read func() error) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := read(); err != nil {
+			s.mu.Lock()
+			if s.err == nil {
+				s.err = err
+			}
+			s.mu.Unlock()
+		}
+	}()
+}
+
+// closeWhenDone closes the entries channel after every reader has returned
+func (s *logStream) closeWhenDone() {
+	go func() {
+		s.wg.Wait()
+		close(s.entries)
+	}()
+}
+
+// Next returns the next log entry, or io.EOF when all services are exhausted
+func (s *logStream) Next() (ports.LogEntry, error) {
+	if s.sorted && !s.drained {
+		for entry := range s.entries {
+			s.buffered = append(s.buffered, entry)
+		}
+		sort.SliceStable(s.buffered, func(i, j int) bool {
+			return s.buffered[i].Timestamp.Before(s.buffered[j].Timestamp)
+		})
+		s.drained = true
+	}
+
+	if s.sorted {
+		if len(s.buffered) == 0 {
+			return ports.LogEntry{}, s.finalErr()
+		}
+		entry := s.buffered[0]
+		s.buffered = s.buffered[1:]
+		return entry, nil
+	}
+
+	entry, ok := <-s.entries
+	if !ok {
+		return ports.LogEntry{}, s.finalErr()
+	}
+	return entry, nil
+}
+
+// finalErr returns the first reader error, or io.EOF
+func (s *logStream) finalErr() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	return io.EOF
+}
+
+// Close stops all readers
+func (s *logStream) Close() error {
+	s.cancel()
+	// Unblock readers waiting to send, then wait for them to exit
+	go func() {
+		for range s.entries {
+		}
+	}()
+	s.wg.Wait()
+	return nil
+}
@@ -0,0 +1,153 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+)
+
+func TestParseLogLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		wantLevel string
+		wantMsg   string
+		wantTime  bool
+	}{
+		{
+			name:     "plain text",
+			line:     "2026-10-16T05:12:17.123456789Z server listening on :8080",
+			wantMsg:  "server listening on :8080",
+			wantTime: true,
+		},
+		{
+			name:      "json with level",
+			line:      `2026-10-16T05:12:17Z {"level":"WARNING","msg":"slow query"}`,
+			wantLevel: "warn",
+			wantMsg:   `{"level":"WARNING","msg":"slow query"}`,
+			wantTime:  true,
+		},
+		{
+			name:      "json with severity",
+			line:      `2026-10-16T05:12:17Z {"severity":"error","message":"boom"}`,
+			wantLevel: "error",
+			wantMsg:   `{"severity":"error","message":"boom"}`,
+			wantTime:  true,
+		},
+		{
+			name:    "no timestamp",
+			line:    "no such service: api",
+			wantMsg: "no such service: api",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := parseLogLine("api", tt.line)
+			if entry.Service != "api" {
+				t.Errorf("Service = %q, want api", entry.Service)
+			}
+			if entry.Level != tt.wantLevel {
+				t.Errorf("Level = %q, want %q", entry.Level, tt.wantLevel)
+			}
+			if entry.Message != tt.wantMsg {
+				t.Errorf("Message = %q, want %q", entry.Message, tt.wantMsg)
+			}
+			if entry.Timestamp.IsZero() == tt.wantTime {
+				t.Errorf("Timestamp = %v, want parsed=%v", entry.Timestamp, tt.wantTime)
+			}
+		})
+	}
+}
+
+func TestLogFilter(t *testing.T) {
+	filter, err := newLogFilter(ports.LogOptions{Grep: "order", Level: "warning"})
+	if err != nil {
+		t.Fatalf("newLogFilter() error: %v", err)
+	}
+
+	tests := []struct {
+		entry ports.LogEntry
+		want  bool
+	}{
+		{ports.LogEntry{Level: "error", Message: "order failed"}, true},
+		{ports.LogEntry{Level: "info", Message: "order created"}, false},
+		{ports.LogEntry{Level: "error", Message: "payment failed"}, false},
+		{ports.LogEntry{Message: "order without level"}, true},
+	}
+
+	for _, tt := range tests {
+		if got := filter.match(tt.entry); got != tt.want {
+			t.Errorf("match(%+v) = %v, want %v", tt.entry, got, tt.want)
+		}
+	}
+}
+
+func TestNewLogFilter_Invalid(t *testing.T) {
+	if _, err := newLogFilter(ports.LogOptions{Grep: "("}); err == nil {
+		t.Error("Expected error for invalid grep pattern")
+	}
+	if _, err := newLogFilter(ports.LogOptions{Level: "loud"}); err == nil {
+		t.Error("Expected error for unknown level")
+	}
+}
+
+func TestLogStream_SortsWithoutFollow(t *testing.T) {
+	filter, _ := newLogFilter(ports.LogOptions{})
+	stream := newLogStream(func() {}, true)
+
+	sources := map[string]string{
+		"api":    "2026-10-16T05:00:01Z api first\n2026-10-16T05:00:03Z api second\n",
+		"worker": "2026-10-16T05:00:02Z worker first\n",
+	}
+	for name, logs := range sources {
+		name, logs := name, logs
+		stream.start(func() error {
+			return readLogLines(context.Background(), name, strings.NewReader(logs), filter, stream.entries)
+		})
+	}
+	stream.closeWhenDone()
+
+	var got []string
+	for {
+		entry, err := stream.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() error: %v", err)
+		}
+		got = append(got, entry.Message)
+	}
+
+	want := []string{"api first", "worker first", "api second"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Next() order = %v, want %v", got, want)
+	}
+}
+
+func TestLogStream_ReportsReaderError(t *testing.T) {
+	stream := newLogStream(func() {}, false)
+	stream.start(func() error { return errors.New("docker failed") })
+	stream.closeWhenDone()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := stream.Next()
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || err.Error() != "docker failed" {
+			t.Errorf("Next() error = %v, want docker failed", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for Next()")
+	}
+}
@@ -9,18 +9,33 @@ import (
 )
 
 // MongoDBProvisioner implements infrastructure provisioning for MongoDB
-type MongoDBProvisioner struct{}
+type MongoDBProvisioner struct {
+	seeder ports.DatabaseSeeder
+}
 
 // NewMongoDBProvisioner creates a new MongoDB provisioner
-func NewMongoDBProvisioner() ports.InfrastructureProvisioner {
-	return &MongoDBProvisioner{}
+func NewMongoDBProvisioner(seeder ports.DatabaseSeeder) ports.InfrastructureProvisioner {
+	return &MongoDBProvisioner{
+		seeder: seeder,
+	}
 }
 
 // ProvisionMongoDB provisions MongoDB
+// MongoDB is started via docker-compose and creates databases on first write,
+// so provisioning here only seeds each service's database on a fresh volume.
 func (m *MongoDBProvisioner) ProvisionMongoDB(ctx context.Context, config *infrastructure.MongoDBConfig) error {
-	// MongoDB is started via docker-compose, provisioning here
-	// mainly involves seeding if needed
-	// TODO: Implement seeding logic
+	if config == nil || m.seeder == nil {
+		return nil
+	}
+
+	for _, db := range config.AllDatabases() {
+		if db.Database == "" || db.Seed == "" {
+			continue
+		}
+		if _, err := m.seeder.SeedMongoDB(ctx, db, false); err != nil {
+			return fmt.Errorf("database %s: %w", db.Database, err)
+		}
+	}
 	return nil
 }
 
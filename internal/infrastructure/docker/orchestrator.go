@@ -5,8 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/vivekkundariya/grund/internal/application/ports"
@@ -15,16 +15,12 @@ import (
 	"github.com/vivekkundariya/grund/internal/ui"
 )
 
-const (
-	// ProjectName is the static project name for all docker compose commands
-	ProjectName = "grund"
-)
-
 // dockerComposeService represents the JSON output from docker compose ps
 type dockerComposeService struct {
 	Name       string `json:"Name"`
 	State      string `json:"State"`
 	Health     string `json:"Health"`
+	ExitCode   int    `json:"ExitCode"`
 	Publishers []struct {
 		URL           string `json:"URL"`
 		TargetPort    int    `json:"TargetPort"`
@@ -40,16 +36,23 @@ type DockerOrchestrator struct {
 	serviceFiles       []string // service compose files (use external network)
 	workingDir         string
 	projectName        string
+	runtime            *Runtime
 }
 
 // NewDockerOrchestrator creates a new Docker orchestrator
-// projectName ensures consistent container naming across runs
-func NewDockerOrchestrator(workingDir string) ports.ContainerOrchestrator {
+// The environment's project name ensures consistent container naming across runs;
+// compose commands run through the configured runtime (docker, podman, nerdctl).
+func NewDockerOrchestrator(workingDir string, env *config.Environment, runtime *Runtime) ports.ContainerOrchestrator {
+	return newDockerOrchestrator(workingDir, env, runtime)
+}
+
+func newDockerOrchestrator(workingDir string, env *config.Environment, runtime *Runtime) *DockerOrchestrator {
 	return &DockerOrchestrator{
 		infrastructureFile: "",
 		serviceFiles:       []string{},
 		workingDir:         workingDir,
-		projectName:        ProjectName,
+		projectName:        env.ProjectName(),
+		runtime:            runtime,
 	}
 }
 
@@ -68,18 +71,16 @@ func (d *DockerOrchestrator) SetComposeFiles(files []string) {
 	}
 }
 
-// composeArgs returns the base docker compose arguments with project name and all compose files
-func (d *DockerOrchestrator) composeArgs() []string {
-	return d.composeArgsForFiles(d.allComposeFiles())
+// compose builds a compose command over all compose files (infrastructure + services)
+func (d *DockerOrchestrator) compose(args ...string) Command {
+	return d.composeForFiles(d.allComposeFiles(), args...)
 }
 
-// composeArgsForFiles returns docker compose arguments for specific files
-func (d *DockerOrchestrator) composeArgsForFiles(files []string) []string {
-	args := []string{"compose", "-p", d.projectName}
-	for _, file := range files {
-		args = append(args, "-f", file)
-	}
-	return args
+// composeForFiles builds a compose command over specific files
+func (d *DockerOrchestrator) composeForFiles(files []string, args ...string) Command {
+	cmd := d.runtime.ComposeCommand(d.projectName, files, args...)
+	cmd.Dir = d.workingDir
+	return cmd
 }
 
 // allComposeFiles returns all compose files (infrastructure + services)
@@ -106,13 +107,10 @@ func (d *DockerOrchestrator) StartInfrastructure(ctx context.Context) error {
 	ui.Debug("Reading infrastructure compose file: %s", d.infrastructureFile)
 
 	// Use only infrastructure file for this operation
-	infraArgs := d.composeArgsForFiles([]string{d.infrastructureFile})
+	infraFiles := []string{d.infrastructureFile}
 
 	// First, get the list of services defined in the infrastructure compose file
-	configArgs := append(infraArgs, "config", "--services")
-	configCmd := exec.CommandContext(ctx, "docker", configArgs...)
-	configCmd.Dir = d.workingDir
-	configOutput, err := configCmd.Output()
+	configOutput, err := d.runtime.Output(ctx, d.composeForFiles(infraFiles, "config", "--services"))
 	if err != nil {
 		return fmt.Errorf("failed to read compose config: %w", err)
 	}
@@ -144,15 +142,10 @@ func (d *DockerOrchestrator) StartInfrastructure(ctx context.Context) error {
 
 	// Start infrastructure services with --wait to ensure they're healthy
 	// Only use infrastructure file to create the network first
-	args := append(infraArgs, "up", "-d", "--wait")
-	args = append(args, servicesToStart...)
-
-	ui.Debug("Running: docker %s", strings.Join(args, " "))
-	cmd := exec.CommandContext(ctx, "docker", args...)
-	cmd.Dir = d.workingDir
-	output, err := cmd.CombinedOutput()
+	args := append([]string{"up", "-d", "--wait"}, servicesToStart...)
+	output, err := d.runtime.CombinedOutput(ctx, d.composeForFiles(infraFiles, args...))
 	if err != nil {
-		ui.Errorf("Docker compose output:\n%s", string(output))
+		ui.Errorf("Compose output:\n%s", string(output))
 		return fmt.Errorf("failed to start infrastructure: %w", err)
 	}
 
@@ -160,24 +153,25 @@ func (d *DockerOrchestrator) StartInfrastructure(ctx context.Context) error {
 	return nil
 }
 
-// StartServices starts services using docker-compose
-func (d *DockerOrchestrator) StartServices(ctx context.Context, services []service.ServiceName) error {
+// StartServices starts services using docker-compose, recreating containers whose compose definition changed
+// With build, images are rebuilt first; otherwise only missing images are built.
+func (d *DockerOrchestrator) StartServices(ctx context.Context, services []service.ServiceName, build bool) error {
 	serviceNames := make([]string, len(services))
 	for i, svc := range services {
 		serviceNames[i] = svc.String()
 	}
 
-	ui.SubStep("Building and starting: %s", strings.Join(serviceNames, ", "))
-
-	args := append(d.composeArgs(), "up", "-d", "--build")
+	args := []string{"up", "-d"}
+	if build {
+		ui.SubStep("Building and starting: %s", strings.Join(serviceNames, ", "))
+		args = append(args, "--build")
+	} else {
+		ui.SubStep("Starting: %s", strings.Join(serviceNames, ", "))
+	}
 	args = append(args, serviceNames...)
-
-	ui.Debug("Running: docker %s", strings.Join(args, " "))
-	cmd := exec.CommandContext(ctx, "docker", args...)
-	cmd.Dir = d.workingDir
-	output, err := cmd.CombinedOutput()
+	output, err := d.runtime.CombinedOutput(ctx, d.compose(args...))
 	if err != nil {
-		ui.Errorf("Docker compose output:\n%s", string(output))
+		ui.Errorf("Compose output:\n%s", string(output))
 		return fmt.Errorf("failed to start services: %w", err)
 	}
 
@@ -194,12 +188,7 @@ func (d *DockerOrchestrator) StopServices(ctx context.Context) error {
 
 	ui.Step("Stopping services...")
 
-	args := append(d.composeArgs(), "down")
-	ui.Debug("Running: docker %s", strings.Join(args, " "))
-
-	cmd := exec.CommandContext(ctx, "docker", args...)
-	cmd.Dir = d.workingDir
-	output, err := cmd.CombinedOutput()
+	output, err := d.runtime.CombinedOutput(ctx, d.compose("down"))
 
 	// Show output regardless of error
 	if len(output) > 0 {
@@ -219,16 +208,78 @@ func (d *DockerOrchestrator) StopServices(ctx context.Context) error {
 	return nil
 }
 
+// RemoveServices stops and removes the given services' containers
+// Their generated compose files are deleted so later commands no longer include them;
+// the infrastructure compose file is shared and kept.
+func (d *DockerOrchestrator) RemoveServices(ctx context.Context, names []service.ServiceName) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	serviceNames := make([]string, len(names))
+	remove := make(map[string]bool, len(names))
+	for i, name := range names {
+		serviceNames[i] = name.String()
+		remove[name.String()] = true
+	}
+
+	ui.Step("Stopping %s...", strings.Join(serviceNames, ", "))
+
+	// stop then rm, rather than rm --stop, works across compose implementations
+	for _, args := range [][]string{{"stop"}, {"rm", "-f"}} {
+		output, err := d.runtime.CombinedOutput(ctx, d.compose(append(args, serviceNames...)...))
+		if len(output) > 0 {
+			for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+				if line != "" {
+					ui.SubStep("%s", line)
+				}
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to stop %s: %w", strings.Join(serviceNames, ", "), err)
+		}
+	}
+
+	kept := []string{}
+	var tmpDir string
+	var removed []string
+	for _, file := range d.serviceFiles {
+		dir := filepath.Dir(file)
+		if !remove[filepath.Base(dir)] {
+			kept = append(kept, file)
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to remove compose file %s: %w", file, err)
+		}
+		tmpDir = filepath.Dir(dir)
+		removed = append(removed, filepath.Base(dir))
+	}
+	d.serviceFiles = kept
+
+	if len(removed) > 0 {
+		return forgetServices(tmpDir, removed)
+	}
+	return nil
+}
+
+// forgetServices removes services from grund up's manifest in tmpDir
+func forgetServices(tmpDir string, names []string) error {
+	state, err := config.LoadState(tmpDir)
+	if err != nil || state == nil {
+		return err
+	}
+	for _, name := range names {
+		state.Forget(name)
+	}
+	return config.SaveState(tmpDir, state)
+}
+
 // RestartService restarts a specific service
 func (d *DockerOrchestrator) RestartService(ctx context.Context, name service.ServiceName) error {
 	ui.Step("Restarting %s...", name.String())
 
-	args := append(d.composeArgs(), "restart", name.String())
-	ui.Debug("Running: docker %s", strings.Join(args, " "))
-
-	cmd := exec.CommandContext(ctx, "docker", args...)
-	cmd.Dir = d.workingDir
-	output, err := cmd.CombinedOutput()
+	output, err := d.runtime.CombinedOutput(ctx, d.compose("restart", name.String()))
 
 	// Show output regardless of error
 	if len(output) > 0 {
@@ -247,13 +298,35 @@ func (d *DockerOrchestrator) RestartService(ctx context.Context, name service.Se
 	return nil
 }
 
+// RecreateServices replaces containers with new ones from their current compose files
+// --no-deps keeps compose from recreating the services they depend on.
+func (d *DockerOrchestrator) RecreateServices(ctx context.Context, names []service.ServiceName, build bool) error {
+	serviceNames := make([]string, len(names))
+	for i, name := range names {
+		serviceNames[i] = name.String()
+	}
+
+	args := []string{"up", "-d", "--force-recreate", "--no-deps"}
+	if build {
+		ui.SubStep("Rebuilding and recreating: %s", strings.Join(serviceNames, ", "))
+		args = append(args, "--build")
+	} else {
+		ui.SubStep("Recreating: %s", strings.Join(serviceNames, ", "))
+	}
+	args = append(args, serviceNames...)
+	output, err := d.runtime.CombinedOutput(ctx, d.compose(args...))
+	if err != nil {
+		ui.Errorf("Compose output:\n%s", string(output))
+		return fmt.Errorf("failed to recreate services: %w", err)
+	}
+
+	return nil
+}
+
 // GetServiceStatus gets the status of a service
 func (d *DockerOrchestrator) GetServiceStatus(ctx context.Context, name service.ServiceName) (ports.ServiceStatus, error) {
 	// Use docker compose ps to get status
-	args := append(d.composeArgs(), "ps", "--format", "json", name.String())
-	cmd := exec.CommandContext(ctx, "docker", args...)
-	cmd.Dir = d.workingDir
-	output, err := cmd.Output()
+	output, err := d.runtime.Output(ctx, d.compose("ps", "--format", "json", name.String()))
 	if err != nil {
 		return ports.ServiceStatus{
 			Name:   name.String(),
@@ -279,25 +352,32 @@ func (d *DockerOrchestrator) GetServiceStatus(ctx context.Context, name service.
 	}
 
 	status := ports.ServiceStatus{
-		Name:   name.String(),
-		Status: strings.ToLower(svc.State),
-		Health: svc.Health,
+		Name:      name.String(),
+		Container: svc.Name,
+		Status:    strings.ToLower(svc.State),
+		Health:    svc.Health,
+		ExitCode:  svc.ExitCode,
 	}
 
 	if status.Health == "" {
 		status.Health = "-"
 	}
 
-	// Build localhost URL from publishers
-	if len(svc.Publishers) > 0 {
-		for _, pub := range svc.Publishers {
-			if pub.PublishedPort > 0 {
-				status.Endpoint = fmt.Sprintf("http://localhost:%d", pub.PublishedPort)
-				break // Use first published port
-			}
+	for _, pub := range svc.Publishers {
+		if pub.PublishedPort > 0 {
+			status.Ports = append(status.Ports, ports.PortBinding{
+				HostPort:      pub.PublishedPort,
+				ContainerPort: pub.TargetPort,
+				Protocol:      pub.Protocol,
+			})
 		}
 	}
 
+	// Build localhost URL from the first published port
+	if len(status.Ports) > 0 {
+		status.Endpoint = fmt.Sprintf("http://localhost:%d", status.Ports[0].HostPort)
+	}
+
 	return status, nil
 }
 
@@ -334,21 +414,13 @@ func (d *DockerOrchestrator) GetAllServiceStatuses(ctx context.Context) ([]ports
 		return []ports.ServiceStatus{}, nil
 	}
 
-	// Get all services from compose file
-	configArgs := append(d.composeArgs(), "config", "--services")
-	configCmd := exec.CommandContext(ctx, "docker", configArgs...)
-	configCmd.Dir = d.workingDir
-	configOutput, err := configCmd.Output()
+	serviceNames, err := d.listServices(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read compose config: %w", err)
+		return nil, err
 	}
 
 	var statuses []ports.ServiceStatus
-	for _, line := range strings.Split(string(configOutput), "\n") {
-		svcName := strings.TrimSpace(line)
-		if svcName == "" {
-			continue
-		}
+	for _, svcName := range serviceNames {
 		status, _ := d.GetServiceStatus(ctx, service.ServiceName(svcName))
 		statuses = append(statuses, status)
 	}
@@ -356,24 +428,94 @@ func (d *DockerOrchestrator) GetAllServiceStatuses(ctx context.Context) ([]ports
 	return statuses, nil
 }
 
-// GetLogs gets logs for a service
-func (d *DockerOrchestrator) GetLogs(ctx context.Context, name service.ServiceName, follow bool, tail int) (ports.LogStream, error) {
-	// TODO: Implement log streaming
-	return nil, fmt.Errorf("not implemented")
+// listServices returns every service defined across the compose files
+func (d *DockerOrchestrator) listServices(ctx context.Context) ([]string, error) {
+	configOutput, err := d.runtime.Output(ctx, d.compose("config", "--services"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compose config: %w", err)
+	}
+
+	var names []string
+	for _, line := range strings.Split(string(configOutput), "\n") {
+		if svcName := strings.TrimSpace(line); svcName != "" {
+			names = append(names, svcName)
+		}
+	}
+	return names, nil
 }
 
-// GetGrundTmpDir returns the path to ~/.grund/tmp
-func GetGrundTmpDir() (string, error) {
-	grundHome, err := config.GetGrundHome()
+// GetLogs streams logs from the given services (all services when names is empty)
+// Each service gets its own `docker compose logs` process so lines can be tagged,
+// filtered and merged. Without follow, entries are returned in timestamp order.
+func (d *DockerOrchestrator) GetLogs(ctx context.Context, names []service.ServiceName, opts ports.LogOptions) (ports.LogStream, error) {
+	if len(d.allComposeFiles()) == 0 {
+		return nil, fmt.Errorf("no services are running. Run 'grund up <service>' first")
+	}
+
+	filter, err := newLogFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceNames := make([]string, len(names))
+	for i, name := range names {
+		serviceNames[i] = name.String()
+	}
+	if len(serviceNames) == 0 {
+		if serviceNames, err = d.listServices(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	args := []string{"logs", "--no-color", "--no-log-prefix", "--timestamps"}
+	if opts.Follow {
+		args = append(args, "--follow")
+	}
+	if opts.Tail > 0 {
+		args = append(args, "--tail", strconv.Itoa(opts.Tail))
+	}
+	if opts.Since != "" {
+		args = append(args, "--since", opts.Since)
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	stream := newLogStream(cancel, !opts.Follow)
+	for _, svcName := range serviceNames {
+		svcName := svcName
+		cmd := d.compose(append(append([]string{}, args...), svcName)...)
+
+		stream.start(func() error {
+			out, err := d.runtime.Stream(streamCtx, cmd)
+			if err != nil {
+				return fmt.Errorf("failed to read logs for %s: %w", svcName, err)
+			}
+
+			err = readLogLines(streamCtx, svcName, out, filter, stream.entries)
+			out.Close()
+			if err != nil && streamCtx.Err() == nil {
+				return fmt.Errorf("failed to read logs for %s: %w", svcName, err)
+			}
+			return nil
+		})
+	}
+	stream.closeWhenDone()
+
+	return stream, nil
+}
+
+// GetGrundTmpDir returns the environment's directory for generated files
+// ~/.grund/tmp for the default environment
+func GetGrundTmpDir(env *config.Environment) (string, error) {
+	tmpDir, err := env.TmpDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get grund home: %w", err)
 	}
-	return filepath.Join(grundHome, "tmp"), nil
+	return tmpDir, nil
 }
 
 // GetInfrastructureComposePath returns the path to the infrastructure compose file
-func GetInfrastructureComposePath() (string, error) {
-	tmpDir, err := GetGrundTmpDir()
+func GetInfrastructureComposePath(env *config.Environment) (string, error) {
+	tmpDir, err := GetGrundTmpDir(env)
 	if err != nil {
 		return "", err
 	}
@@ -381,17 +523,26 @@ func GetInfrastructureComposePath() (string, error) {
 }
 
 // GetServiceComposePath returns the path to a service's compose file
-func GetServiceComposePath(serviceName string) (string, error) {
-	tmpDir, err := GetGrundTmpDir()
+func GetServiceComposePath(env *config.Environment, serviceName string) (string, error) {
+	tmpDir, err := GetGrundTmpDir(env)
 	if err != nil {
 		return "", err
 	}
 	return filepath.Join(tmpDir, serviceName, "docker-compose.yaml"), nil
 }
 
-// DiscoverComposeFiles scans ~/.grund/tmp/ and returns all compose files found
-func DiscoverComposeFiles() (*ports.ComposeFileSet, error) {
-	tmpDir, err := GetGrundTmpDir()
+// DiscoverComposeFiles scans the environment's tmp directory and returns the active compose files
+// Once grund up has written a manifest, service directories it doesn't list are skipped.
+func DiscoverComposeFiles(env *config.Environment) (*ports.ComposeFileSet, error) {
+	tmpDir, err := GetGrundTmpDir(env)
+	if err != nil {
+		return nil, err
+	}
+	return discoverComposeFiles(tmpDir)
+}
+
+func discoverComposeFiles(tmpDir string) (*ports.ComposeFileSet, error) {
+	state, err := config.LoadState(tmpDir)
 	if err != nil {
 		return nil, err
 	}
@@ -423,7 +574,7 @@ func DiscoverComposeFiles() (*ports.ComposeFileSet, error) {
 
 		if entry.Name() == "infrastructure" {
 			fileSet.InfrastructurePath = composePath
-		} else {
+		} else if state == nil || state.Has(entry.Name()) {
 			fileSet.ServicePaths[entry.Name()] = composePath
 		}
 	}
@@ -431,9 +582,10 @@ func DiscoverComposeFiles() (*ports.ComposeFileSet, error) {
 	return fileSet, nil
 }
 
-// StopAllProjects stops all services using discovered compose files
-func StopAllProjects(ctx context.Context) error {
-	fileSet, err := DiscoverComposeFiles()
+// StopAllProjects stops all of an environment's services using discovered compose files
+// With removeVolumes the environment's named volumes (database data) are deleted too.
+func StopAllProjects(ctx context.Context, runtime *Runtime, env *config.Environment, removeVolumes bool) error {
+	fileSet, err := DiscoverComposeFiles(env)
 	if err != nil {
 		return fmt.Errorf("failed to discover compose files: %w", err)
 	}
@@ -446,15 +598,13 @@ func StopAllProjects(ctx context.Context) error {
 
 	ui.Infof("Stopping all Grund services...")
 
-	// Build docker compose command with all files
-	args := []string{"compose", "-p", ProjectName}
-	for _, path := range allPaths {
-		args = append(args, "-f", path)
+	// Build compose command with all files
+	args := []string{"down"}
+	if removeVolumes {
+		args = append(args, "-v")
 	}
-	args = append(args, "down")
 
-	cmd := exec.CommandContext(ctx, "docker", args...)
-	output, err := cmd.CombinedOutput()
+	output, err := runtime.CombinedOutput(ctx, runtime.ComposeCommand(env.ProjectName(), allPaths, args...))
 
 	if err != nil {
 		ui.Warnf("Failed to stop services: %s", string(output))
@@ -465,8 +615,12 @@ func StopAllProjects(ctx context.Context) error {
 	return nil
 }
 
-// GetProjectName returns the static project name "grund"
-// This is used as the docker compose project name for consistent container naming
-func GetProjectName() string {
-	return ProjectName
+// CountRunningContainers returns how many containers of the environment's project are running
+func CountRunningContainers(ctx context.Context, runtime *Runtime, env *config.Environment) (int, error) {
+	output, err := runtime.Output(ctx, runtime.EngineCommand("ps", "-q",
+		"--filter", "label=com.docker.compose.project="+env.ProjectName()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list containers: %w", err)
+	}
+	return len(strings.Fields(string(output))), nil
 }
@@ -1,14 +1,18 @@
 package docker
 
 import (
+	"context"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/vivekkundariya/grund/internal/config"
+	"github.com/vivekkundariya/grund/internal/domain/service"
 )
 
 func TestNewDockerOrchestrator(t *testing.T) {
-	orchestrator := NewDockerOrchestrator("/working/dir")
+	orchestrator := NewDockerOrchestrator("/working/dir", config.DefaultEnvironment(), NewRuntime(config.DefaultRuntime(), NewExecRunner()))
 
 	if orchestrator == nil {
 		t.Fatal("NewDockerOrchestrator() returned nil")
@@ -20,8 +24,8 @@ func TestNewDockerOrchestrator(t *testing.T) {
 		t.Error("NewDockerOrchestrator() should return *DockerOrchestrator")
 	}
 
-	if do.projectName != ProjectName {
-		t.Errorf("Expected projectName %q, got %q", ProjectName, do.projectName)
+	if do.projectName != "grund" {
+		t.Errorf("Expected projectName %q, got %q", "grund", do.projectName)
 	}
 }
 
@@ -31,14 +35,14 @@ func TestGetGrundTmpDir(t *testing.T) {
 		t.Fatalf("Failed to get grund home: %v", err)
 	}
 
-	tmpDir, err := GetGrundTmpDir()
+	tmpDir, err := GetGrundTmpDir(config.DefaultEnvironment())
 	if err != nil {
-		t.Fatalf("GetGrundTmpDir() returned error: %v", err)
+		t.Fatalf("GetGrundTmpDir(config.DefaultEnvironment()) returned error: %v", err)
 	}
 
 	expected := filepath.Join(grundHome, "tmp")
 	if tmpDir != expected {
-		t.Errorf("GetGrundTmpDir() = %q, want %q", tmpDir, expected)
+		t.Errorf("GetGrundTmpDir(config.DefaultEnvironment()) = %q, want %q", tmpDir, expected)
 	}
 }
 
@@ -48,7 +52,7 @@ func TestGetInfrastructureComposePath(t *testing.T) {
 		t.Fatalf("Failed to get grund home: %v", err)
 	}
 
-	path, err := GetInfrastructureComposePath()
+	path, err := GetInfrastructureComposePath(config.DefaultEnvironment())
 	if err != nil {
 		t.Fatalf("GetInfrastructureComposePath() returned error: %v", err)
 	}
@@ -75,7 +79,7 @@ func TestGetServiceComposePath(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.serviceName, func(t *testing.T) {
-			path, err := GetServiceComposePath(tt.serviceName)
+			path, err := GetServiceComposePath(config.DefaultEnvironment(), tt.serviceName)
 			if err != nil {
 				t.Fatalf("GetServiceComposePath(%q) returned error: %v", tt.serviceName, err)
 			}
@@ -87,7 +91,7 @@ func TestGetServiceComposePath(t *testing.T) {
 }
 
 func TestDockerOrchestrator_SetComposeFiles(t *testing.T) {
-	orchestrator := NewDockerOrchestrator("/test/workdir").(*DockerOrchestrator)
+	orchestrator := NewDockerOrchestrator("/test/workdir", config.DefaultEnvironment(), NewRuntime(config.DefaultRuntime(), NewExecRunner())).(*DockerOrchestrator)
 
 	// Files with "infrastructure" in the path are treated as infrastructure files
 	files := []string{"/path/infrastructure/docker-compose.yaml", "/path/mars/docker-compose.yaml"}
@@ -106,49 +110,145 @@ func TestDockerOrchestrator_SetComposeFiles(t *testing.T) {
 	}
 }
 
-func TestDockerOrchestrator_composeArgs(t *testing.T) {
-	orchestrator := NewDockerOrchestrator("/test/workdir").(*DockerOrchestrator)
+func TestDockerOrchestrator_compose(t *testing.T) {
+	rt, _ := newFakeRuntime(t, "docker compose")
+	orchestrator := NewDockerOrchestrator("/test/workdir", config.DefaultEnvironment(), rt).(*DockerOrchestrator)
 	orchestrator.SetComposeFiles([]string{"/path/infrastructure/docker-compose.yaml", "/path/svc/docker-compose.yaml"})
 
-	args := orchestrator.composeArgs()
+	cmd := orchestrator.compose("ps")
 
-	// Expected: compose -p grund -f /path/infrastructure/docker-compose.yaml -f /path/svc/docker-compose.yaml
-	expected := []string{"compose", "-p", ProjectName, "-f", "/path/infrastructure/docker-compose.yaml", "-f", "/path/svc/docker-compose.yaml"}
-	if len(args) != len(expected) {
-		t.Fatalf("Expected %d args, got %d: %v", len(expected), len(args), args)
+	expected := "docker compose -p grund -f /path/infrastructure/docker-compose.yaml -f /path/svc/docker-compose.yaml ps"
+	if cmd.String() != expected {
+		t.Errorf("compose() = %q, want %q", cmd, expected)
 	}
+	if cmd.Dir != "/test/workdir" {
+		t.Errorf("compose() dir = %q, want /test/workdir", cmd.Dir)
+	}
+}
 
-	for i, arg := range expected {
-		if args[i] != arg {
-			t.Errorf("Arg %d: expected %q, got %q", i, arg, args[i])
+func TestDockerOrchestrator_RemoveServices(t *testing.T) {
+	tmp := t.TempDir()
+	infraFile := filepath.Join(tmp, "infrastructure", "docker-compose.yaml")
+	files := []string{infraFile}
+	for _, name := range []string{"orders", "payments"} {
+		file := filepath.Join(tmp, name, "docker-compose.yaml")
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte("services: {}\n"), 0644); err != nil {
+			t.Fatal(err)
 		}
+		files = append(files, file)
+	}
+
+	rt, runner := newFakeRuntime(t, "docker compose")
+	orchestrator := NewDockerOrchestrator(tmp, config.DefaultEnvironment(), rt).(*DockerOrchestrator)
+	orchestrator.SetComposeFiles(files)
+
+	if err := orchestrator.RemoveServices(context.Background(), []service.ServiceName{"payments", "redis"}); err != nil {
+		t.Fatalf("RemoveServices() error: %v", err)
+	}
+
+	if len(runner.commands) != 2 ||
+		!strings.HasSuffix(runner.commands[0].String(), " stop payments redis") ||
+		!strings.HasSuffix(runner.commands[1].String(), " rm -f payments redis") {
+		t.Errorf("commands = %v, want stop then rm -f of payments and redis", runner.commands)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "payments")); !os.IsNotExist(err) {
+		t.Error("payments compose directory should be deleted")
+	}
+	if _, err := os.Stat(files[1]); err != nil {
+		t.Errorf("orders compose file should be kept: %v", err)
+	}
+	if got := orchestrator.allComposeFiles(); len(got) != 2 || got[0] != infraFile || got[1] != files[1] {
+		t.Errorf("compose files = %v, want infrastructure and orders", got)
+	}
+}
+
+func TestNewDockerOrchestrator_NamedEnvironment(t *testing.T) {
+	// Named environments get their own compose project so stacks don't collide
+	env := &config.Environment{Name: "review", PortOffset: 1000}
+	orchestrator := NewDockerOrchestrator("/test/workdir", env, NewRuntime(config.DefaultRuntime(), NewExecRunner())).(*DockerOrchestrator)
+	if orchestrator.projectName != "grund-review" {
+		t.Errorf("projectName = %q, want grund-review", orchestrator.projectName)
+	}
+}
+
+func TestDockerOrchestrator_StartServices(t *testing.T) {
+	tests := []struct {
+		name  string
+		build bool
+		want  string
+	}{
+		{"rebuild", true, "podman compose -p grund -f /tmp/api/docker-compose.yaml up -d --build api worker"},
+		{"recreate only", false, "podman compose -p grund -f /tmp/api/docker-compose.yaml up -d api worker"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt, runner := newFakeRuntime(t, "podman compose")
+			orchestrator := NewDockerOrchestrator("/test/workdir", config.DefaultEnvironment(), rt)
+			orchestrator.SetComposeFiles([]string{"/tmp/api/docker-compose.yaml"})
+
+			if err := orchestrator.StartServices(context.Background(), []service.ServiceName{"api", "worker"}, tt.build); err != nil {
+				t.Fatalf("StartServices() error: %v", err)
+			}
+
+			if len(runner.commands) != 1 || runner.commands[0].String() != tt.want {
+				t.Errorf("commands = %v, want [%s]", runner.commands, tt.want)
+			}
+		})
+	}
+}
+
+func TestDockerOrchestrator_RecreateServices(t *testing.T) {
+	rt, runner := newFakeRuntime(t, "docker compose")
+	orchestrator := NewDockerOrchestrator("/test/workdir", config.DefaultEnvironment(), rt)
+	orchestrator.SetComposeFiles([]string{"/tmp/api/docker-compose.yaml"})
+
+	if err := orchestrator.RecreateServices(context.Background(), []service.ServiceName{"api"}, true); err != nil {
+		t.Fatalf("RecreateServices() error: %v", err)
+	}
+
+	want := "docker compose -p grund -f /tmp/api/docker-compose.yaml up -d --force-recreate --no-deps --build api"
+	if len(runner.commands) != 1 || runner.commands[0].String() != want {
+		t.Errorf("commands = %v, want [%s]", runner.commands, want)
 	}
 }
 
-func TestGetProjectName(t *testing.T) {
-	// GetProjectName should always return the static "grund" project name
-	result := GetProjectName()
-	if result != ProjectName {
-		t.Errorf("GetProjectName() = %q, want %q", result, ProjectName)
+func TestDockerOrchestrator_GetServiceStatus(t *testing.T) {
+	rt, runner := newFakeRuntime(t, "docker compose")
+	runner.output["docker compose"] = `{"Name":"grund-api","State":"running","Health":"healthy","Publishers":[{"TargetPort":8080,"PublishedPort":9080}]}`
+	orchestrator := NewDockerOrchestrator("/test/workdir", config.DefaultEnvironment(), rt)
+	orchestrator.SetComposeFiles([]string{"/tmp/api/docker-compose.yaml"})
+
+	status, err := orchestrator.GetServiceStatus(context.Background(), "api")
+	if err != nil {
+		t.Fatalf("GetServiceStatus() error: %v", err)
+	}
+	if status.Container != "grund-api" || status.Status != "running" || status.Health != "healthy" || status.Endpoint != "http://localhost:9080" {
+		t.Errorf("GetServiceStatus() = %+v", status)
 	}
 }
 
-// Note: Integration tests for StartServices, StopServices, RestartService
-// would require Docker to be available. These are better suited for
-// end-to-end tests or tests with mocked exec.Command.
-// Below we document what those tests would verify:
-
-// TestDockerOrchestrator_StartServices_Integration would verify:
-// - Correct docker compose command is executed
-// - Services are passed as arguments
-// - -d flag is used for detached mode
-// - Errors from docker are propagated
-
-// TestDockerOrchestrator_StopServices_Integration would verify:
-// - docker compose down is executed
-// - Correct compose file is specified
-// - Working directory is correct
-
-// TestDockerOrchestrator_RestartService_Integration would verify:
-// - docker compose restart is executed with service name
-// - Errors are handled appropriately
+func TestStopAllProjects_RemovesVolumes(t *testing.T) {
+	t.Setenv(config.EnvGrundHome, t.TempDir())
+	env := config.DefaultEnvironment()
+	composePath, _ := GetServiceComposePath(env, "api")
+	if err := os.MkdirAll(filepath.Dir(composePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(composePath, []byte("services: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rt, runner := newFakeRuntime(t, "docker-compose")
+	if err := StopAllProjects(context.Background(), rt, env, true); err != nil {
+		t.Fatalf("StopAllProjects() error: %v", err)
+	}
+
+	want := "docker-compose -p grund -f " + composePath + " down -v"
+	if len(runner.commands) != 1 || runner.commands[0].String() != want {
+		t.Errorf("commands = %v, want [%s]", runner.commands, want)
+	}
+}
@@ -0,0 +1,206 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/config"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+	"github.com/vivekkundariya/grund/internal/ui"
+	"gopkg.in/yaml.v3"
+)
+
+// composeVolumeLabel names the compose volume a volume was created for
+const composeVolumeLabel = "com.docker.compose.volume"
+
+// infrastructureVolumes hold infrastructure data; they are kept even when the current
+// infrastructure compose file doesn't declare them, since a later grund up may need them again
+var infrastructureVolumes = []string{"postgres-data", "mongodb-data", "localstack-data"}
+
+// OrphanCollectorImpl finds an environment's compose directories, containers and volumes
+// that belong to services no longer in the registry
+type OrphanCollectorImpl struct {
+	tmpDir      string
+	projectName string
+	runtime     *Runtime
+}
+
+// NewOrphanCollector creates a collector for the environment whose files live in tmpDir
+func NewOrphanCollector(tmpDir string, env *config.Environment, runtime *Runtime) ports.OrphanCollector {
+	return &OrphanCollectorImpl{
+		tmpDir:      tmpDir,
+		projectName: env.ProjectName(),
+		runtime:     runtime,
+	}
+}
+
+// FindOrphans lists what no registered service or infrastructure container owns
+// Volumes count as owned while a kept compose file declares them, and infrastructure
+// volumes always do; without the infrastructure compose file nothing tells which
+// are in use, so none are reported.
+func (c *OrphanCollectorImpl) FindOrphans(ctx context.Context, registered []service.ServiceName) ([]ports.Orphan, error) {
+	owned := make(map[string]bool, len(registered)+len(infrastructureServices))
+	for _, name := range registered {
+		owned[name.String()] = true
+	}
+	for _, name := range infrastructureServices {
+		owned[name] = true
+	}
+
+	var orphans []ports.Orphan
+
+	// Compose directories, and manifest entries whose directory is already gone
+	orphanDirs := make(map[string]bool)
+	entries, err := os.ReadDir(c.tmpDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read tmp directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != "infrastructure" && !owned[entry.Name()] {
+			orphanDirs[entry.Name()] = true
+		}
+	}
+	state, err := config.LoadState(c.tmpDir)
+	if err != nil {
+		return nil, err
+	}
+	if state != nil {
+		for name := range state.Services {
+			if !owned[name] {
+				orphanDirs[name] = true
+			}
+		}
+	}
+	for _, name := range sortedKeys(orphanDirs) {
+		orphans = append(orphans, ports.Orphan{Kind: ports.OrphanComposeFile, Name: filepath.Join(c.tmpDir, name), Service: name})
+	}
+
+	// Containers of the project
+	out, err := c.runtime.Output(ctx, c.runtime.EngineCommand("ps", "-a",
+		"--filter", "label="+composeProjectLabel+"="+c.projectName,
+		"--format", `{{.Names}}	{{.Label "`+composeServiceLabel+`"}}`))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		name, svc := splitLabelLine(line, c.projectName+"-")
+		if name != "" && !owned[svc] {
+			orphans = append(orphans, ports.Orphan{Kind: ports.OrphanContainer, Name: name, Service: svc})
+		}
+	}
+
+	// Volumes of the project that no kept compose file declares
+	declared, ok := c.declaredVolumes(owned)
+	if !ok {
+		ui.Debug("No infrastructure compose file, not checking volumes")
+		return orphans, nil
+	}
+	out, err = c.runtime.Output(ctx, c.runtime.EngineCommand("volume", "ls",
+		"--filter", "label="+composeProjectLabel+"="+c.projectName,
+		"--format", `{{.Name}}	{{.Label "`+composeVolumeLabel+`"}}`))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volumes: %w", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		name, volume := splitLabelLine(line, c.projectName+"_")
+		if name != "" && !declared[volume] {
+			orphans = append(orphans, ports.Orphan{Kind: ports.OrphanVolume, Name: name})
+		}
+	}
+
+	return orphans, nil
+}
+
+// RemoveOrphans deletes containers, then the volumes they may hold, then compose directories
+func (c *OrphanCollectorImpl) RemoveOrphans(ctx context.Context, orphans []ports.Orphan) error {
+	byKind := make(map[ports.OrphanKind][]string)
+	var services []string
+	for _, o := range orphans {
+		byKind[o.Kind] = append(byKind[o.Kind], o.Name)
+		if o.Kind == ports.OrphanComposeFile {
+			services = append(services, o.Service)
+		}
+	}
+
+	if names := byKind[ports.OrphanContainer]; len(names) > 0 {
+		if output, err := c.runtime.CombinedOutput(ctx, c.runtime.EngineCommand(append([]string{"rm", "-f"}, names...)...)); err != nil {
+			return fmt.Errorf("failed to remove containers: %s: %w", firstLine(string(output)), err)
+		}
+	}
+	if names := byKind[ports.OrphanVolume]; len(names) > 0 {
+		if output, err := c.runtime.CombinedOutput(ctx, c.runtime.EngineCommand(append([]string{"volume", "rm"}, names...)...)); err != nil {
+			return fmt.Errorf("failed to remove volumes: %s: %w", firstLine(string(output)), err)
+		}
+	}
+	for _, dir := range byKind[ports.OrphanComposeFile] {
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", dir, err)
+		}
+	}
+	if len(services) > 0 {
+		return forgetServices(c.tmpDir, services)
+	}
+	return nil
+}
+
+// declaredVolumes returns the infrastructure volumes and the named volumes declared by the
+// infrastructure compose file and the compose files of owned services; ok is false
+// without an infrastructure file
+func (c *OrphanCollectorImpl) declaredVolumes(owned map[string]bool) (declared map[string]bool, ok bool) {
+	declared = make(map[string]bool)
+	for _, name := range infrastructureVolumes {
+		declared[name] = true
+	}
+	dirs := []string{"infrastructure"}
+	for name := range owned {
+		dirs = append(dirs, name)
+	}
+	for _, dir := range dirs {
+		data, err := os.ReadFile(filepath.Join(c.tmpDir, dir, "docker-compose.yaml"))
+		if err != nil {
+			if dir == "infrastructure" {
+				return nil, false
+			}
+			continue
+		}
+		var compose struct {
+			Volumes map[string]any `yaml:"volumes"`
+		}
+		if err := yaml.Unmarshal(data, &compose); err != nil {
+			if dir == "infrastructure" {
+				return nil, false
+			}
+			continue
+		}
+		for name := range compose.Volumes {
+			declared[name] = true
+		}
+	}
+	return declared, true
+}
+
+// splitLabelLine splits a "<name>\t<label>" line of engine output
+// Engines that don't support the label template print nothing for it; then the
+// label is taken from the name, which compose derives from the project.
+func splitLabelLine(line, prefix string) (name, label string) {
+	name, label, _ = strings.Cut(strings.TrimSpace(line), "\t")
+	name, label = strings.TrimSpace(name), strings.TrimSpace(label)
+	if label == "" {
+		label = strings.TrimPrefix(name, prefix)
+	}
+	return name, label
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
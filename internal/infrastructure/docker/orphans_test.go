@@ -0,0 +1,127 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/config"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+func writeTestComposeFile(t *testing.T, tmpDir, dir, content string) {
+	t.Helper()
+	path := filepath.Join(tmpDir, dir, "docker-compose.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOrphanCollector(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestComposeFile(t, tmpDir, "infrastructure", "volumes:\n  postgres-data: {}\n")
+	writeTestComposeFile(t, tmpDir, "orders", "services: {}\n")
+	writeTestComposeFile(t, tmpDir, "old-billing", "services: {}\n")
+	state := config.NewState()
+	state.Services["orders"] = config.ServiceState{}
+	state.Services["old-billing"] = config.ServiceState{}
+	state.Services["gone"] = config.ServiceState{}
+	if err := config.SaveState(tmpDir, state); err != nil {
+		t.Fatal(err)
+	}
+
+	rt, runner := newFakeRuntime(t, "docker compose")
+	runner.output["docker ps"] = "grund-orders\torders\ngrund-postgres\tpostgres\ngrund-old-billing\t\n"
+	runner.output["docker volume ls"] = "grund_postgres-data\tpostgres-data\ngrund_kafka-data\tkafka-data\n"
+	collector := NewOrphanCollector(tmpDir, config.DefaultEnvironment(), rt)
+	ctx := context.Background()
+
+	orphans, err := collector.FindOrphans(ctx, []service.ServiceName{"orders"})
+	if err != nil {
+		t.Fatalf("FindOrphans() error: %v", err)
+	}
+	want := []ports.Orphan{
+		{Kind: ports.OrphanComposeFile, Name: filepath.Join(tmpDir, "gone"), Service: "gone"},
+		{Kind: ports.OrphanComposeFile, Name: filepath.Join(tmpDir, "old-billing"), Service: "old-billing"},
+		{Kind: ports.OrphanContainer, Name: "grund-old-billing", Service: "old-billing"},
+		{Kind: ports.OrphanVolume, Name: "grund_kafka-data"},
+	}
+	if len(orphans) != len(want) {
+		t.Fatalf("FindOrphans() = %+v, want %+v", orphans, want)
+	}
+	for i := range want {
+		if orphans[i] != want[i] {
+			t.Errorf("orphan %d = %+v, want %+v", i, orphans[i], want[i])
+		}
+	}
+
+	runner.commands = nil
+	if err := collector.RemoveOrphans(ctx, orphans); err != nil {
+		t.Fatalf("RemoveOrphans() error: %v", err)
+	}
+	if len(runner.commands) != 2 ||
+		runner.commands[0].String() != "docker rm -f grund-old-billing" ||
+		runner.commands[1].String() != "docker volume rm grund_kafka-data" {
+		t.Errorf("commands = %v, want the container removed before the volume", runner.commands)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "old-billing")); !os.IsNotExist(err) {
+		t.Error("old-billing compose directory should be deleted")
+	}
+	state, _ = config.LoadState(tmpDir)
+	if state.Has("old-billing") || state.Has("gone") || !state.Has("orders") {
+		t.Errorf("manifest services = %v, want only orders", state.Services)
+	}
+}
+
+// grund up A (postgres), grund down, grund up B (redis only) leaves an infrastructure
+// compose file without postgres-data; its data must survive a prune all the same
+func TestOrphanCollector_KeepsUndeclaredInfrastructureVolumes(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestComposeFile(t, tmpDir, "infrastructure", "services:\n  redis: {}\n")
+	writeTestComposeFile(t, tmpDir, "b", "services: {}\n")
+
+	rt, runner := newFakeRuntime(t, "docker compose")
+	runner.output["docker volume ls"] = "grund_postgres-data\tpostgres-data\ngrund_mongodb-data\t\ngrund_kafka-data\tkafka-data\n"
+	collector := NewOrphanCollector(tmpDir, config.DefaultEnvironment(), rt)
+
+	orphans, err := collector.FindOrphans(context.Background(), []service.ServiceName{"a", "b"})
+	if err != nil {
+		t.Fatalf("FindOrphans() error: %v", err)
+	}
+	var volumes []string
+	for _, o := range orphans {
+		if o.Kind == ports.OrphanVolume {
+			volumes = append(volumes, o.Name)
+		}
+	}
+	if len(volumes) != 1 || volumes[0] != "grund_kafka-data" {
+		t.Errorf("orphan volumes = %v, want only grund_kafka-data", volumes)
+	}
+}
+
+func TestOrphanCollector_NoInfrastructureFile(t *testing.T) {
+	rt, runner := newFakeRuntime(t, "docker compose")
+	runner.output["docker volume ls"] = "grund_postgres-data\tpostgres-data\n"
+	collector := NewOrphanCollector(t.TempDir(), config.DefaultEnvironment(), rt)
+
+	orphans, err := collector.FindOrphans(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("FindOrphans() error: %v", err)
+	}
+	for _, o := range orphans {
+		if o.Kind == ports.OrphanVolume {
+			t.Errorf("volume %s reported without an infrastructure compose file to check against", o.Name)
+		}
+	}
+	for _, cmd := range runner.commands {
+		if strings.Contains(cmd.String(), "volume") {
+			t.Errorf("volumes should not be listed, ran %q", cmd)
+		}
+	}
+}
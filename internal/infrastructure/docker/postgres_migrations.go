@@ -0,0 +1,164 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migration is a single schema migration loaded from a migrations directory
+type migration struct {
+	Version       string
+	Name          string
+	Up            string
+	Down          string
+	NoTransaction bool
+
+	number  int64 // numeric version, used for ordering
+	numeric bool
+	hasUp   bool
+}
+
+// versionedFileRegex matches files like 001_create_users.sql or 20240101120000_init.up.sql
+var versionedFileRegex = regexp.MustCompile(`^(\d+)_(.+)$`)
+
+// Goose annotations (https://github.com/pressly/goose)
+const (
+	gooseUp             = "-- +goose Up"
+	gooseDown           = "-- +goose Down"
+	gooseNoTransaction  = "-- +goose NO TRANSACTION"
+	gooseStatementBegin = "-- +goose StatementBegin"
+	gooseStatementEnd   = "-- +goose StatementEnd"
+)
+
+// loadMigrations reads all migrations from dir, sorted in apply order
+// Supported layouts:
+//   - golang-migrate: NNN_name.up.sql / NNN_name.down.sql
+//   - goose: NNN_name.sql with -- +goose Up / -- +goose Down annotations
+//   - plain: *.sql files applied in order (up only)
+func loadMigrations(dir string) ([]*migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	byVersion := make(map[string]*migration)
+	getOrCreate := func(stem string) *migration {
+		version, name, number, numeric := parseMigrationStem(stem)
+		if m, ok := byVersion[version]; ok {
+			return m
+		}
+		m := &migration{Version: version, Name: name, number: number, numeric: numeric}
+		byVersion[version] = m
+		return m
+	}
+
+	for _, entry := range entries {
+		fileName := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(fileName, ".sql") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, fileName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", fileName, err)
+		}
+		content := string(data)
+
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			m := getOrCreate(strings.TrimSuffix(fileName, ".up.sql"))
+			if m.hasUp {
+				return nil, fmt.Errorf("duplicate migration version %s (%s)", m.Version, fileName)
+			}
+			m.Up, m.hasUp = content, true
+
+		case strings.HasSuffix(fileName, ".down.sql"):
+			m := getOrCreate(strings.TrimSuffix(fileName, ".down.sql"))
+			if m.Down != "" {
+				return nil, fmt.Errorf("duplicate migration version %s (%s)", m.Version, fileName)
+			}
+			m.Down = content
+
+		default:
+			m := getOrCreate(strings.TrimSuffix(fileName, ".sql"))
+			if m.hasUp {
+				return nil, fmt.Errorf("duplicate migration version %s (%s)", m.Version, fileName)
+			}
+			if strings.Contains(content, gooseUp) {
+				m.Up, m.Down, m.NoTransaction = parseGooseMigration(content)
+			} else {
+				m.Up = content
+			}
+			m.hasUp = true
+		}
+	}
+
+	migrations := make([]*migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if !m.hasUp {
+			return nil, fmt.Errorf("migration %s has a down migration but no up migration", m.Version)
+		}
+		migrations = append(migrations, m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		a, b := migrations[i], migrations[j]
+		if a.numeric != b.numeric {
+			return a.numeric // numbered migrations run before unnumbered ones
+		}
+		if a.numeric && a.number != b.number {
+			return a.number < b.number
+		}
+		return a.Version < b.Version
+	})
+
+	return migrations, nil
+}
+
+// parseMigrationStem splits a file stem like "001_create_users" into version and name
+// Unnumbered files use the whole stem as their version
+func parseMigrationStem(stem string) (version, name string, number int64, numeric bool) {
+	match := versionedFileRegex.FindStringSubmatch(stem)
+	if match == nil {
+		return stem, stem, 0, false
+	}
+	n, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return stem, stem, 0, false
+	}
+	return strconv.FormatInt(n, 10), match[2], n, true
+}
+
+// parseGooseMigration splits a goose-annotated file into its up and down sections
+func parseGooseMigration(content string) (up, down string, noTransaction bool) {
+	var upLines, downLines []string
+	var current *[]string
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, gooseUp):
+			current = &upLines
+			continue
+		case strings.HasPrefix(trimmed, gooseDown):
+			current = &downLines
+			continue
+		case strings.HasPrefix(trimmed, gooseNoTransaction):
+			noTransaction = true
+			continue
+		case strings.HasPrefix(trimmed, gooseStatementBegin), strings.HasPrefix(trimmed, gooseStatementEnd):
+			// psql handles multi-statement bodies (dollar quoting etc.) on its own
+			continue
+		}
+		if current != nil {
+			*current = append(*current, line)
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(upLines, "\n")), strings.TrimSpace(strings.Join(downLines, "\n")), noTransaction
+}
@@ -0,0 +1,156 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeMigrationFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestLoadMigrations_GolangMigrate(t *testing.T) {
+	dir := writeMigrationFiles(t, map[string]string{
+		"000002_add_email.up.sql":      "ALTER TABLE users ADD email text;",
+		"000002_add_email.down.sql":    "ALTER TABLE users DROP email;",
+		"000001_create_users.up.sql":   "CREATE TABLE users (id int);",
+		"000001_create_users.down.sql": "DROP TABLE users;",
+		"README.md":                    "not a migration",
+	})
+
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		t.Fatalf("loadMigrations() error: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("Expected 2 migrations, got %d", len(migrations))
+	}
+
+	first := migrations[0]
+	if first.Version != "1" || first.Name != "create_users" {
+		t.Errorf("Expected version 1 create_users, got %s %s", first.Version, first.Name)
+	}
+	if first.Up != "CREATE TABLE users (id int);" || first.Down != "DROP TABLE users;" {
+		t.Errorf("Unexpected up/down: %q / %q", first.Up, first.Down)
+	}
+	if migrations[1].Version != "2" {
+		t.Errorf("Expected second migration version 2, got %s", migrations[1].Version)
+	}
+}
+
+func TestLoadMigrations_Goose(t *testing.T) {
+	dir := writeMigrationFiles(t, map[string]string{
+		"20240101120000_create_orders.sql": `-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE orders (id int);
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE orders;
+`,
+		"20240102120000_index_orders.sql": `-- +goose NO TRANSACTION
+-- +goose Up
+CREATE INDEX CONCURRENTLY orders_id ON orders (id);
+-- +goose Down
+DROP INDEX orders_id;
+`,
+	})
+
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		t.Fatalf("loadMigrations() error: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("Expected 2 migrations, got %d", len(migrations))
+	}
+
+	create := migrations[0]
+	if create.Up != "CREATE TABLE orders (id int);" {
+		t.Errorf("Unexpected up section: %q", create.Up)
+	}
+	if create.Down != "DROP TABLE orders;" {
+		t.Errorf("Unexpected down section: %q", create.Down)
+	}
+	if create.NoTransaction {
+		t.Error("Expected create_orders to run in a transaction")
+	}
+	if !migrations[1].NoTransaction {
+		t.Error("Expected index_orders to opt out of the transaction")
+	}
+}
+
+func TestLoadMigrations_PlainSQLOrdering(t *testing.T) {
+	dir := writeMigrationFiles(t, map[string]string{
+		"10_later.sql":  "SELECT 10;",
+		"2_earlier.sql": "SELECT 2;",
+		"extras.sql":    "SELECT 'extras';",
+	})
+
+	migrations, err := loadMigrations(dir)
+	if err != nil {
+		t.Fatalf("loadMigrations() error: %v", err)
+	}
+
+	var versions []string
+	for _, m := range migrations {
+		versions = append(versions, m.Version)
+	}
+	if got := strings.Join(versions, ","); got != "2,10,extras" {
+		t.Errorf("Expected order 2,10,extras, got %s", got)
+	}
+	if migrations[0].Down != "" {
+		t.Errorf("Expected plain migration to have no down section, got %q", migrations[0].Down)
+	}
+}
+
+func TestLoadMigrations_DuplicateVersion(t *testing.T) {
+	dir := writeMigrationFiles(t, map[string]string{
+		"001_first.sql":  "SELECT 1;",
+		"1_again.up.sql": "SELECT 1;",
+	})
+
+	if _, err := loadMigrations(dir); err == nil {
+		t.Error("Expected error for duplicate migration version")
+	}
+}
+
+func TestLoadMigrations_DownWithoutUp(t *testing.T) {
+	dir := writeMigrationFiles(t, map[string]string{
+		"001_orphan.down.sql": "DROP TABLE orphan;",
+	})
+
+	if _, err := loadMigrations(dir); err == nil {
+		t.Error("Expected error for down migration without up")
+	}
+}
+
+func TestParseAppliedMigrations(t *testing.T) {
+	applied := parseAppliedMigrations("1|1700000000\n2|1700000060\n\n")
+
+	if len(applied) != 2 {
+		t.Fatalf("Expected 2 applied migrations, got %d", len(applied))
+	}
+	if applied["2"].Unix() != 1700000060 {
+		t.Errorf("Expected applied_at 1700000060, got %d", applied["2"].Unix())
+	}
+}
+
+func TestWrapTransaction(t *testing.T) {
+	wrapped := wrapTransaction("SELECT 1;\n", false)
+	if !strings.HasPrefix(wrapped, "BEGIN;") || !strings.HasSuffix(wrapped, "COMMIT;\n") {
+		t.Errorf("Expected script wrapped in a transaction, got %q", wrapped)
+	}
+
+	if got := wrapTransaction("SELECT 1;\n", true); got != "SELECT 1;\n" {
+		t.Errorf("Expected script unchanged, got %q", got)
+	}
+}
@@ -0,0 +1,173 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/config"
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
+	"github.com/vivekkundariya/grund/internal/ui"
+)
+
+// migrationsTable records applied migrations in each service database
+// The name is grund-specific so it never clashes with golang-migrate or goose tables
+const migrationsTable = "grund_schema_migrations"
+
+// PostgresMigrator implements DatabaseMigrator by running SQL through psql in the postgres container
+type PostgresMigrator struct {
+	runtime   *Runtime
+	container string
+}
+
+// NewPostgresMigrator creates a new Postgres migrator for the environment's postgres container
+func NewPostgresMigrator(env *config.Environment, runtime *Runtime) ports.DatabaseMigrator {
+	return &PostgresMigrator{runtime: runtime, container: env.ContainerName("postgres")}
+}
+
+// Migrate applies all pending migrations in order
+// Migrations run as the database owner so the created objects belong to the service's role
+func (m *PostgresMigrator) Migrate(ctx context.Context, db infrastructure.PostgresDatabase) (int, error) {
+	migrations, applied, err := m.load(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	owner, _ := db.Credentials()
+	count := 0
+	for _, mig := range migrations {
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+
+		ui.SubStep("Applying migration %s_%s to %s", mig.Version, mig.Name, db.Database)
+		script := mig.Up + "\n;\n" + fmt.Sprintf("INSERT INTO %s (version, name) VALUES (%s, %s);\n",
+			migrationsTable, quoteLiteral(mig.Version), quoteLiteral(mig.Name))
+		if _, err := runPsql(ctx, m.runtime, m.container, owner, db.Database, wrapTransaction(script, mig.NoTransaction)); err != nil {
+			return count, fmt.Errorf("migration %s_%s failed: %w", mig.Version, mig.Name, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// Rollback reverts the last `steps` applied migrations, newest first
+func (m *PostgresMigrator) Rollback(ctx context.Context, db infrastructure.PostgresDatabase, steps int) (int, error) {
+	migrations, applied, err := m.load(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	owner, _ := db.Credentials()
+	count := 0
+	for i := len(migrations) - 1; i >= 0 && count < steps; i-- {
+		mig := migrations[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+		if strings.TrimSpace(mig.Down) == "" {
+			return count, fmt.Errorf("migration %s_%s has no down migration", mig.Version, mig.Name)
+		}
+
+		ui.SubStep("Reverting migration %s_%s on %s", mig.Version, mig.Name, db.Database)
+		script := mig.Down + "\n;\n" + fmt.Sprintf("DELETE FROM %s WHERE version = %s;\n",
+			migrationsTable, quoteLiteral(mig.Version))
+		if _, err := runPsql(ctx, m.runtime, m.container, owner, db.Database, wrapTransaction(script, mig.NoTransaction)); err != nil {
+			return count, fmt.Errorf("rollback of %s_%s failed: %w", mig.Version, mig.Name, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// Status lists every migration in the directory with its applied state
+func (m *PostgresMigrator) Status(ctx context.Context, db infrastructure.PostgresDatabase) ([]ports.MigrationStatus, error) {
+	migrations, applied, err := m.load(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]ports.MigrationStatus, 0, len(migrations))
+	for _, mig := range migrations {
+		appliedAt, ok := applied[mig.Version]
+		statuses = append(statuses, ports.MigrationStatus{
+			Version:   mig.Version,
+			Name:      mig.Name,
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		})
+	}
+	return statuses, nil
+}
+
+// load reads the migrations directory and the applied versions from the tracking table
+func (m *PostgresMigrator) load(ctx context.Context, db infrastructure.PostgresDatabase) ([]*migration, map[string]time.Time, error) {
+	if db.Migrations == "" {
+		return nil, nil, fmt.Errorf("no migrations configured for database %s", db.Database)
+	}
+	if _, err := os.Stat(db.Migrations); err != nil {
+		return nil, nil, fmt.Errorf("migrations directory not found: %s", db.Migrations)
+	}
+
+	migrations, err := loadMigrations(db.Migrations)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	owner, _ := db.Credentials()
+	if _, err := runPsql(ctx, m.runtime, m.container, owner, db.Database, createMigrationsTableSQL()); err != nil {
+		return nil, nil, fmt.Errorf("failed to create %s table: %w", migrationsTable, err)
+	}
+
+	out, err := runPsql(ctx, m.runtime, m.container, owner, db.Database,
+		fmt.Sprintf("SELECT version, extract(epoch from applied_at)::bigint FROM %s;\n", migrationsTable))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	return migrations, parseAppliedMigrations(out), nil
+}
+
+func createMigrationsTableSQL() string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  version    text PRIMARY KEY,
+  name       text NOT NULL,
+  applied_at timestamptz NOT NULL DEFAULT now()
+);
+`, migrationsTable)
+}
+
+// parseAppliedMigrations parses psql unaligned output of "version|epoch" rows
+func parseAppliedMigrations(out string) map[string]time.Time {
+	applied := make(map[string]time.Time)
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "|", 2)
+		var appliedAt time.Time
+		if len(parts) == 2 {
+			if epoch, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
+				appliedAt = time.Unix(epoch, 0)
+			}
+		}
+		applied[parts[0]] = appliedAt
+	}
+	return applied
+}
+
+// wrapTransaction runs a script atomically unless the migration opted out
+// (e.g. CREATE INDEX CONCURRENTLY cannot run inside a transaction)
+func wrapTransaction(script string, noTransaction bool) string {
+	if noTransaction {
+		return script
+	}
+	return "BEGIN;\n" + script + "COMMIT;\n"
+}
@@ -3,27 +3,155 @@ package docker
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/config"
 	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
+	"github.com/vivekkundariya/grund/internal/ui"
 )
 
 // PostgresProvisioner implements infrastructure provisioning for PostgreSQL
-type PostgresProvisioner struct{}
+type PostgresProvisioner struct {
+	runtime   *Runtime
+	container string // container running the shared PostgreSQL instance
+	migrator  ports.DatabaseMigrator
+	seeder    ports.DatabaseSeeder
+}
 
 // NewPostgresProvisioner creates a new Postgres provisioner
-func NewPostgresProvisioner() ports.InfrastructureProvisioner {
-	return &PostgresProvisioner{}
+func NewPostgresProvisioner(env *config.Environment, runtime *Runtime, migrator ports.DatabaseMigrator, seeder ports.DatabaseSeeder) ports.InfrastructureProvisioner {
+	return &PostgresProvisioner{
+		runtime:   runtime,
+		container: env.ContainerName("postgres"),
+		migrator:  migrator,
+		seeder:    seeder,
+	}
 }
 
 // ProvisionPostgres provisions PostgreSQL
+// The container is started via docker-compose; here we make sure every
+// service's database (and optional dedicated role) exists on the shared instance
+// then apply each database's migrations and, on a fresh volume, its seed.
 func (p *PostgresProvisioner) ProvisionPostgres(ctx context.Context, config *infrastructure.PostgresConfig) error {
-	// PostgreSQL is started via docker-compose, so provisioning here
-	// mainly involves running migrations and seeding if needed
-	// TODO: Implement migration and seeding logic
+	if config == nil {
+		return nil
+	}
+
+	for _, db := range config.AllDatabases() {
+		if db.Database == "" {
+			continue
+		}
+		if err := p.ensureDatabase(ctx, db); err != nil {
+			return fmt.Errorf("database %s: %w", db.Database, err)
+		}
+		if db.Migrations != "" && p.migrator != nil {
+			if _, err := p.migrator.Migrate(ctx, db); err != nil {
+				return fmt.Errorf("database %s: %w", db.Database, err)
+			}
+		}
+		if db.Seed != "" && p.seeder != nil {
+			if _, err := p.seeder.SeedPostgres(ctx, db, false); err != nil {
+				return fmt.Errorf("database %s: %w", db.Database, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ensureDatabase creates the role and database if they don't already exist
+func (p *PostgresProvisioner) ensureDatabase(ctx context.Context, db infrastructure.PostgresDatabase) error {
+	owner, password := db.Credentials()
+
+	if owner != infrastructure.DefaultPostgresUsername {
+		if _, err := runPsql(ctx, p.runtime, p.container, infrastructure.DefaultPostgresUsername, "postgres", ensureRoleSQL(owner, password)); err != nil {
+			return fmt.Errorf("failed to ensure role %s: %w", owner, err)
+		}
+	}
+
+	out, err := runPsql(ctx, p.runtime, p.container, infrastructure.DefaultPostgresUsername, "postgres", databaseExistsSQL(db.Database))
+	if err != nil {
+		return fmt.Errorf("failed to check database: %w", err)
+	}
+	if strings.TrimSpace(out) == "1" {
+		ui.Debug("Postgres database already exists: %s", db.Database)
+	} else {
+		// CREATE DATABASE cannot run inside a transaction block, so it has its own call
+		ui.SubStep("Creating Postgres database: %s", db.Database)
+		if _, err := runPsql(ctx, p.runtime, p.container, infrastructure.DefaultPostgresUsername, "postgres", createDatabaseSQL(db.Database, owner)); err != nil {
+			return fmt.Errorf("failed to create database: %w", err)
+		}
+	}
+
+	if owner != infrastructure.DefaultPostgresUsername {
+		if _, err := runPsql(ctx, p.runtime, p.container, infrastructure.DefaultPostgresUsername, "postgres", grantDatabaseSQL(db.Database, owner)); err != nil {
+			return fmt.Errorf("failed to grant privileges to %s: %w", owner, err)
+		}
+		// Databases created before the role was configured keep postgres as schema owner
+		if _, err := runPsql(ctx, p.runtime, p.container, infrastructure.DefaultPostgresUsername, db.Database, grantSchemaSQL(owner)); err != nil {
+			return fmt.Errorf("failed to grant schema privileges to %s: %w", owner, err)
+		}
+	}
 	return nil
 }
 
+// runPsql executes SQL against a database in the postgres container as the given role
+// The SQL is passed on stdin so it never shows up in the process list.
+// Connections inside the container use the local socket, which needs no password.
+func runPsql(ctx context.Context, runtime *Runtime, container, username, database, sql string) (string, error) {
+	cmd := runtime.EngineCommand(
+		"exec", "-i", container,
+		"psql", "-U", username, "-d", database,
+		"-v", "ON_ERROR_STOP=1", "-q", "-t", "-A",
+	)
+	cmd.Stdin = strings.NewReader(sql)
+	output, err := runtime.CombinedOutput(ctx, cmd)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
+
+// quoteIdent quotes a PostgreSQL identifier (database or role name)
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteLiteral quotes a PostgreSQL string literal
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// ensureRoleSQL creates a login role, or resets its password if it already exists
+func ensureRoleSQL(role, password string) string {
+	return fmt.Sprintf(`DO $$
+BEGIN
+  IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = %s) THEN
+    CREATE ROLE %s LOGIN PASSWORD %s;
+  ELSE
+    ALTER ROLE %s WITH LOGIN PASSWORD %s;
+  END IF;
+END
+$$;
+`, quoteLiteral(role), quoteIdent(role), quoteLiteral(password), quoteIdent(role), quoteLiteral(password))
+}
+
+func databaseExistsSQL(database string) string {
+	return fmt.Sprintf("SELECT 1 FROM pg_database WHERE datname = %s;\n", quoteLiteral(database))
+}
+
+func createDatabaseSQL(database, owner string) string {
+	return fmt.Sprintf("CREATE DATABASE %s OWNER %s;\n", quoteIdent(database), quoteIdent(owner))
+}
+
+func grantDatabaseSQL(database, role string) string {
+	return fmt.Sprintf("GRANT ALL PRIVILEGES ON DATABASE %s TO %s;\n", quoteIdent(database), quoteIdent(role))
+}
+
+func grantSchemaSQL(role string) string {
+	return fmt.Sprintf("GRANT ALL ON SCHEMA public TO %s;\n", quoteIdent(role))
+}
+
 // ProvisionMongoDB not applicable
 func (p *PostgresProvisioner) ProvisionMongoDB(ctx context.Context, config *infrastructure.MongoDBConfig) error {
 	return fmt.Errorf("mongodb provisioning not supported by postgres provisioner")
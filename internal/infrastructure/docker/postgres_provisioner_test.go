@@ -0,0 +1,63 @@
+package docker
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vivekkundariya/grund/internal/config"
+)
+
+func TestQuoteIdent(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"orders_db", `"orders_db"`},
+		{"my-db", `"my-db"`},
+		{`we"ird`, `"we""ird"`},
+	}
+
+	for _, tt := range tests {
+		if got := quoteIdent(tt.in); got != tt.want {
+			t.Errorf("quoteIdent(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteLiteral(t *testing.T) {
+	if got := quoteLiteral("it's"); got != "'it''s'" {
+		t.Errorf("quoteLiteral() = %q, want %q", got, "'it''s'")
+	}
+}
+
+func TestEnsureRoleSQL(t *testing.T) {
+	sql := ensureRoleSQL("payments", "s3cr'et")
+
+	for _, want := range []string{
+		"rolname = 'payments'",
+		`CREATE ROLE "payments" LOGIN PASSWORD 's3cr''et'`,
+		`ALTER ROLE "payments" WITH LOGIN PASSWORD 's3cr''et'`,
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("ensureRoleSQL() missing %q in:\n%s", want, sql)
+		}
+	}
+}
+
+func TestCreateDatabaseSQL(t *testing.T) {
+	got := createDatabaseSQL("payments-db", "payments")
+	want := `CREATE DATABASE "payments-db" OWNER "payments";` + "\n"
+	if got != want {
+		t.Errorf("createDatabaseSQL() = %q, want %q", got, want)
+	}
+}
+
+func TestPostgresProvisioner_NilConfig(t *testing.T) {
+	env := config.DefaultEnvironment()
+	rt := NewRuntime(config.DefaultRuntime(), NewExecRunner())
+	p := NewPostgresProvisioner(env, rt, NewPostgresMigrator(env, rt), NewDatabaseSeeder(env, rt))
+	if err := p.ProvisionPostgres(context.Background(), nil); err != nil {
+		t.Errorf("ProvisionPostgres(nil) returned error: %v", err)
+	}
+}
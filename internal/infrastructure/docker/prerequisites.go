@@ -0,0 +1,249 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/config"
+)
+
+const (
+	gib = 1 << 30
+	// Images and volumes for a typical stack (LocalStack, Postgres, service builds) need a few GiB
+	diskSpaceWarn = 5 * gib
+	diskSpaceFail = 1 * gib
+)
+
+// composeVersionPattern extracts the major version from compose version output
+var composeVersionPattern = regexp.MustCompile(`v?(\d+)\.\d+(\.\d+)?`)
+
+// tunnelInstallHints tells users how to install each tunnel provider
+var tunnelInstallHints = map[string]string{
+	"cloudflared": "install with 'brew install cloudflared' or see https://developers.cloudflare.com/cloudflare-one/connections/connect-networks/downloads/",
+	"ngrok":       "install from https://ngrok.com/download",
+}
+
+// PrerequisiteCheckerImpl checks the container runtime and host resources grund up needs
+type PrerequisiteCheckerImpl struct {
+	runtime *Runtime
+
+	// Host access, replaced in tests
+	lookPath   func(file string) (string, error)
+	portFree   func(port int) bool
+	otherPorts func() (map[int]config.PublishedPort, error)
+	diskFree   func(path string) (uint64, error)
+	dataPaths  func() []string
+}
+
+// NewPrerequisiteChecker creates a checker for the configured runtime and environment
+func NewPrerequisiteChecker(runtime *Runtime, env *config.Environment) ports.PrerequisiteChecker {
+	return &PrerequisiteCheckerImpl{
+		runtime:  runtime,
+		lookPath: exec.LookPath,
+		portFree: hostPortFree,
+		otherPorts: func() (map[int]config.PublishedPort, error) {
+			return config.OtherEnvironmentHostPorts(env)
+		},
+		diskFree:  diskFree,
+		dataPaths: grundDataPaths,
+	}
+}
+
+// CheckRuntime checks the engine is reachable and compose is a supported version
+func (c *PrerequisiteCheckerImpl) CheckRuntime(ctx context.Context) []ports.Check {
+	engine := ports.Check{Category: "runtime", Name: c.runtime.Engine}
+	out, err := c.runtime.Output(ctx, c.runtime.EngineCommand("info", "--format", c.infoFormat("version")))
+	if err != nil {
+		engine.Status = ports.CheckFail
+		engine.Message = fmt.Sprintf("cannot reach the %s engine: %v", c.runtime.Name, firstLine(err.Error()))
+		engine.Hint = fmt.Sprintf("start %s (e.g. Docker Desktop or the %s service)", c.runtime.Name, c.runtime.Engine)
+	} else {
+		engine.Status = ports.CheckOK
+		engine.Message = fmt.Sprintf("%s engine %s is reachable", c.runtime.Name, strings.TrimSpace(string(out)))
+	}
+
+	compose := ports.Check{Category: "runtime", Name: strings.Join(c.runtime.Compose, " ")}
+	args := append([]string{}, c.runtime.Compose[1:]...)
+	args = append(args, "version")
+	if c.runtime.Name == config.RuntimeDocker {
+		args = append(args, "--short")
+	}
+	out, err = c.runtime.Output(ctx, Command{Name: c.runtime.Compose[0], Args: args})
+	version := composeVersionPattern.FindStringSubmatch(string(out))
+	major := 0
+	if version != nil {
+		major, _ = strconv.Atoi(version[1])
+	}
+	switch {
+	case err != nil:
+		compose.Status = ports.CheckFail
+		compose.Message = fmt.Sprintf("%s is not available: %v", compose.Name, firstLine(err.Error()))
+		compose.Hint = "install Docker Compose v2, or set docker.compose_command in ~/.grund/config.yaml"
+	case version == nil:
+		compose.Status = ports.CheckWarn
+		compose.Message = fmt.Sprintf("cannot determine the compose version from %q", firstLine(string(out)))
+	case c.runtime.Name == config.RuntimeDocker && major < 2:
+		compose.Status = ports.CheckFail
+		compose.Message = fmt.Sprintf("compose %s is not supported; grund needs Compose v2", version[0])
+		compose.Hint = "install Docker Compose v2 and use docker.compose_command: docker compose"
+	default:
+		compose.Status = ports.CheckOK
+		compose.Message = fmt.Sprintf("compose %s", strings.TrimPrefix(version[0], "v"))
+	}
+
+	return []ports.Check{engine, compose}
+}
+
+// CheckHostPorts checks no other environment records a published port and every one
+// can be bound, unless grund itself holds it
+func (c *PrerequisiteCheckerImpl) CheckHostPorts(ctx context.Context, assignments []ports.HostPortAssignment, inUse map[int]bool) []ports.Check {
+	var checks []ports.Check
+	busy := 0
+	others, err := c.otherPorts()
+	if err != nil {
+		busy++
+		checks = append(checks, ports.Check{
+			Category: "host ports",
+			Name:     "environments",
+			Status:   ports.CheckWarn,
+			Message:  fmt.Sprintf("cannot read the ports of other environments: %v", err),
+		})
+	}
+	for _, a := range assignments {
+		if other, ok := others[a.HostPort]; ok {
+			busy++
+			checks = append(checks, ports.Check{
+				Category: "host ports",
+				Name:     strconv.Itoa(a.HostPort),
+				Status:   ports.CheckFail,
+				Message:  fmt.Sprintf("host port %d for %s is also published by %s in environment %s", a.HostPort, a.Owner, other.Owner, other.Environment),
+				Hint:     fmt.Sprintf("change %s's port, or stop and remove environment %s", a.Owner, other.Environment),
+			})
+			continue
+		}
+		if inUse[a.HostPort] || c.portFree(a.HostPort) {
+			continue
+		}
+		busy++
+		checks = append(checks, ports.Check{
+			Category: "host ports",
+			Name:     strconv.Itoa(a.HostPort),
+			Status:   ports.CheckFail,
+			Message:  fmt.Sprintf("host port %d for %s is in use by another process", a.HostPort, a.Owner),
+			Hint:     "stop the process using it, or run in a separate environment with --env <name> to shift grund's ports",
+		})
+	}
+	if busy == 0 {
+		checks = append(checks, ports.Check{
+			Category: "host ports",
+			Name:     "free",
+			Status:   ports.CheckOK,
+			Message:  fmt.Sprintf("%d host port(s) available", len(assignments)),
+		})
+	}
+	return checks
+}
+
+// CheckDiskSpace checks free space where images, volumes and grund's files live
+func (c *PrerequisiteCheckerImpl) CheckDiskSpace(ctx context.Context) []ports.Check {
+	paths := c.dataPaths()
+	if out, err := c.runtime.Output(ctx, c.runtime.EngineCommand("info", "--format", c.infoFormat("root"))); err == nil {
+		// The engine's data directory only exists here when it runs on this host, not in a VM
+		if root := strings.TrimSpace(string(out)); root != "" {
+			if _, err := os.Stat(root); err == nil {
+				paths = append([]string{root}, paths...)
+			}
+		}
+	}
+
+	var checks []ports.Check
+	for _, path := range paths {
+		// grund's directory may not exist before the first up; its filesystem does
+		for {
+			if _, err := os.Stat(path); !os.IsNotExist(err) || filepath.Dir(path) == path {
+				break
+			}
+			path = filepath.Dir(path)
+		}
+		free, err := c.diskFree(path)
+		if err != nil {
+			continue
+		}
+		check := ports.Check{Category: "disk", Name: path, Status: ports.CheckOK}
+		check.Message = fmt.Sprintf("%.1f GiB free", float64(free)/gib)
+		switch {
+		case free < diskSpaceFail:
+			check.Status = ports.CheckFail
+		case free < diskSpaceWarn:
+			check.Status = ports.CheckWarn
+		}
+		if check.Status != ports.CheckOK {
+			check.Message = fmt.Sprintf("only %.1f GiB free for images and volumes", float64(free)/gib)
+			check.Hint = fmt.Sprintf("free up space, e.g. with %s system prune", c.runtime.Engine)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// CheckTunnelProvider checks the provider's binary is on PATH
+func (c *PrerequisiteCheckerImpl) CheckTunnelProvider(provider string) ports.Check {
+	check := ports.Check{Category: "tunnel", Name: provider}
+	path, err := c.lookPath(provider)
+	if err != nil {
+		check.Status = ports.CheckFail
+		check.Message = fmt.Sprintf("%s not found in PATH", provider)
+		check.Hint = tunnelInstallHints[provider]
+		return check
+	}
+	check.Status = ports.CheckOK
+	check.Message = path
+	return check
+}
+
+// infoFormat returns the engine info template for the server version or data root
+// Podman reports both in different fields than Docker and nerdctl.
+func (c *PrerequisiteCheckerImpl) infoFormat(field string) string {
+	if c.runtime.Name == config.RuntimePodman {
+		if field == "root" {
+			return "{{.Store.GraphRoot}}"
+		}
+		return "{{.Version.Version}}"
+	}
+	if field == "root" {
+		return "{{.DockerRootDir}}"
+	}
+	return "{{.ServerVersion}}"
+}
+
+// hostPortFree reports whether port can be bound on all interfaces, as compose publishes it
+func hostPortFree(port int) bool {
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	l.Close()
+	return true
+}
+
+// grundDataPaths returns grund's own data directory
+func grundDataPaths() []string {
+	home, err := config.GetGrundHome()
+	if err != nil {
+		return nil
+	}
+	return []string{home}
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
@@ -0,0 +1,84 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/config"
+)
+
+func newTestPrerequisiteChecker(rt *Runtime) *PrerequisiteCheckerImpl {
+	c := NewPrerequisiteChecker(rt, config.DefaultEnvironment()).(*PrerequisiteCheckerImpl)
+	c.lookPath = func(file string) (string, error) { return "", errors.New("not found") }
+	c.portFree = func(port int) bool { return port != 5432 }
+	c.otherPorts = func() (map[int]config.PublishedPort, error) {
+		return map[int]config.PublishedPort{9080: {Environment: "review", Owner: "api"}}, nil
+	}
+	c.diskFree = func(path string) (uint64, error) { return 3 * gib, nil }
+	c.dataPaths = func() []string { return []string{"/"} }
+	return c
+}
+
+func TestPrerequisiteChecker_CheckRuntime(t *testing.T) {
+	tests := []struct {
+		name           string
+		composeCommand string
+		output         map[string]string
+		err            error
+		want           [2]ports.CheckStatus // engine, compose
+	}{
+		{"compose v2", "docker compose", map[string]string{"docker info": "27.0.3", "docker compose version": "v2.27.1"}, nil, [2]ports.CheckStatus{ports.CheckOK, ports.CheckOK}},
+		{"compose v1", "docker-compose", map[string]string{"docker info": "27.0.3", "docker-compose version": "1.29.2"}, nil, [2]ports.CheckStatus{ports.CheckOK, ports.CheckFail}},
+		{"podman compose 1.x", "podman-compose", map[string]string{"podman info": "5.0.0", "podman-compose version": "podman-compose version 1.1.0"}, nil, [2]ports.CheckStatus{ports.CheckOK, ports.CheckOK}},
+		{"engine down", "docker compose", nil, errors.New("Cannot connect to the Docker daemon"), [2]ports.CheckStatus{ports.CheckFail, ports.CheckFail}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt, runner := newFakeRuntime(t, tt.composeCommand)
+			for prefix, out := range tt.output {
+				runner.output[prefix] = out
+			}
+			runner.err = tt.err
+
+			checks := newTestPrerequisiteChecker(rt).CheckRuntime(context.Background())
+			if len(checks) != 2 || checks[0].Status != tt.want[0] || checks[1].Status != tt.want[1] {
+				t.Errorf("CheckRuntime() = %+v, want %v", checks, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrerequisiteChecker_HostResources(t *testing.T) {
+	rt, _ := newFakeRuntime(t, "docker compose")
+	c := newTestPrerequisiteChecker(rt)
+	ctx := context.Background()
+
+	assignments := []ports.HostPortAssignment{
+		{Owner: "postgres", HostPort: 5432},
+		{Owner: "api", HostPort: 8080},
+	}
+	checks := c.CheckHostPorts(ctx, assignments, nil)
+	if len(checks) != 1 || checks[0].Status != ports.CheckFail || checks[0].Name != "5432" {
+		t.Errorf("CheckHostPorts() = %+v, want port 5432 busy", checks)
+	}
+	if checks := c.CheckHostPorts(ctx, assignments, map[int]bool{5432: true}); len(checks) != 1 || checks[0].Status != ports.CheckOK {
+		t.Errorf("CheckHostPorts() = %+v, want ports held by grund to pass", checks)
+	}
+	// A free port another environment publishes its own container on is still taken
+	web := []ports.HostPortAssignment{{Owner: "web", HostPort: 9080}}
+	if checks := c.CheckHostPorts(ctx, web, nil); len(checks) != 1 || checks[0].Status != ports.CheckFail || !strings.Contains(checks[0].Message, "environment review") {
+		t.Errorf("CheckHostPorts() = %+v, want port 9080 reported as review's", checks)
+	}
+
+	if checks := c.CheckDiskSpace(ctx); len(checks) != 1 || checks[0].Status != ports.CheckWarn {
+		t.Errorf("CheckDiskSpace() = %+v, want a low space warning", checks)
+	}
+
+	if check := c.CheckTunnelProvider("ngrok"); check.Status != ports.CheckFail || check.Hint == "" {
+		t.Errorf("CheckTunnelProvider() = %+v, want a failure with an install hint", check)
+	}
+}
@@ -0,0 +1,144 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/vivekkundariya/grund/internal/config"
+	"github.com/vivekkundariya/grund/internal/ui"
+)
+
+// Command is a single invocation of the runtime's CLI
+type Command struct {
+	Name  string
+	Args  []string
+	Dir   string    // working directory; empty for the current one
+	Stdin io.Reader // optional
+}
+
+func (c Command) String() string {
+	return strings.TrimSpace(c.Name + " " + strings.Join(c.Args, " "))
+}
+
+// CommandRunner runs runtime commands
+// Every engine and compose invocation goes through it, so tests can substitute a fake.
+type CommandRunner interface {
+	// Output runs the command and returns its stdout; stderr is included in the error
+	Output(ctx context.Context, cmd Command) ([]byte, error)
+	// CombinedOutput runs the command and returns stdout and stderr together
+	CombinedOutput(ctx context.Context, cmd Command) ([]byte, error)
+	// Stream starts the command and returns its stdout and stderr as they are written
+	// Reading returns the command's exit error at the end; Close stops it.
+	Stream(ctx context.Context, cmd Command) (io.ReadCloser, error)
+}
+
+// ExecRunner runs commands as local processes
+type ExecRunner struct{}
+
+// NewExecRunner creates a runner that executes commands on the host
+func NewExecRunner() CommandRunner {
+	return ExecRunner{}
+}
+
+func (ExecRunner) command(ctx context.Context, c Command) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, c.Name, c.Args...)
+	cmd.Dir = c.Dir
+	cmd.Stdin = c.Stdin
+	return cmd
+}
+
+// Output runs the command and returns its stdout
+func (r ExecRunner) Output(ctx context.Context, c Command) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := r.command(ctx, c)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil && stderr.Len() > 0 {
+		return output, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return output, err
+}
+
+// CombinedOutput runs the command and returns stdout and stderr together
+func (r ExecRunner) CombinedOutput(ctx context.Context, c Command) ([]byte, error) {
+	return r.command(ctx, c).CombinedOutput()
+}
+
+// Stream starts the command with stdout and stderr piped to the returned reader
+func (r ExecRunner) Stream(ctx context.Context, c Command) (io.ReadCloser, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	cmd := r.command(ctx, c)
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, err
+	}
+	go func() {
+		pw.CloseWithError(cmd.Wait())
+		cancel()
+	}()
+
+	return &streamReader{PipeReader: pr, cancel: cancel}, nil
+}
+
+// streamReader stops the command when the reader is closed
+type streamReader struct {
+	*io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (s *streamReader) Close() error {
+	s.cancel()
+	return s.PipeReader.Close()
+}
+
+// Runtime builds and runs engine and compose commands for the configured container runtime
+type Runtime struct {
+	*config.ContainerRuntime
+	runner CommandRunner
+}
+
+// NewRuntime creates a runtime that runs its commands through runner
+func NewRuntime(rt *config.ContainerRuntime, runner CommandRunner) *Runtime {
+	return &Runtime{ContainerRuntime: rt, runner: runner}
+}
+
+// ComposeCommand builds a compose command for a project and its compose files
+func (r *Runtime) ComposeCommand(project string, files []string, args ...string) Command {
+	cmdArgs := append([]string{}, r.Compose[1:]...)
+	cmdArgs = append(cmdArgs, "-p", project)
+	for _, file := range files {
+		cmdArgs = append(cmdArgs, "-f", file)
+	}
+	return Command{Name: r.Compose[0], Args: append(cmdArgs, args...)}
+}
+
+// EngineCommand builds a command for the engine CLI (exec, ps, ...)
+func (r *Runtime) EngineCommand(args ...string) Command {
+	return Command{Name: r.Engine, Args: args}
+}
+
+// Output runs a command and returns its stdout
+func (r *Runtime) Output(ctx context.Context, cmd Command) ([]byte, error) {
+	ui.Debug("Running: %s", cmd)
+	return r.runner.Output(ctx, cmd)
+}
+
+// CombinedOutput runs a command and returns stdout and stderr together
+func (r *Runtime) CombinedOutput(ctx context.Context, cmd Command) ([]byte, error) {
+	ui.Debug("Running: %s", cmd)
+	return r.runner.CombinedOutput(ctx, cmd)
+}
+
+// Stream starts a command and returns its output as it is written
+func (r *Runtime) Stream(ctx context.Context, cmd Command) (io.ReadCloser, error) {
+	ui.Debug("Running: %s", cmd)
+	return r.runner.Stream(ctx, cmd)
+}
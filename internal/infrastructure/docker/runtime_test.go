@@ -0,0 +1,101 @@
+package docker
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/vivekkundariya/grund/internal/config"
+)
+
+// fakeRunner records commands and returns canned output instead of running them
+type fakeRunner struct {
+	commands []Command
+	output   map[string]string // keyed by a prefix of Command.String()
+	err      error
+}
+
+func (f *fakeRunner) respond(cmd Command) ([]byte, error) {
+	f.commands = append(f.commands, cmd)
+	for prefix, out := range f.output {
+		if strings.HasPrefix(cmd.String(), prefix) {
+			return []byte(out), f.err
+		}
+	}
+	return nil, f.err
+}
+
+func (f *fakeRunner) Output(ctx context.Context, cmd Command) ([]byte, error) {
+	return f.respond(cmd)
+}
+
+func (f *fakeRunner) CombinedOutput(ctx context.Context, cmd Command) ([]byte, error) {
+	return f.respond(cmd)
+}
+
+func (f *fakeRunner) Stream(ctx context.Context, cmd Command) (io.ReadCloser, error) {
+	out, err := f.respond(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(strings.NewReader(string(out))), nil
+}
+
+// newFakeRuntime returns a runtime for composeCommand backed by a fake runner
+func newFakeRuntime(t *testing.T, composeCommand string) (*Runtime, *fakeRunner) {
+	t.Helper()
+	rt, err := config.DetectRuntime(composeCommand)
+	if err != nil {
+		t.Fatalf("DetectRuntime(%q) error: %v", composeCommand, err)
+	}
+	runner := &fakeRunner{output: make(map[string]string)}
+	return NewRuntime(rt, runner), runner
+}
+
+func TestRuntime_ComposeCommand(t *testing.T) {
+	tests := []struct {
+		composeCommand string
+		want           string
+	}{
+		{"docker compose", "docker compose -p grund -f a.yaml up -d"},
+		{"docker-compose", "docker-compose -p grund -f a.yaml up -d"},
+		{"podman compose", "podman compose -p grund -f a.yaml up -d"},
+		{"nerdctl compose", "nerdctl compose -p grund -f a.yaml up -d"},
+	}
+
+	for _, tt := range tests {
+		rt, _ := newFakeRuntime(t, tt.composeCommand)
+		if got := rt.ComposeCommand("grund", []string{"a.yaml"}, "up", "-d").String(); got != tt.want {
+			t.Errorf("%s: ComposeCommand() = %q, want %q", tt.composeCommand, got, tt.want)
+		}
+	}
+}
+
+func TestRuntime_EngineCommand(t *testing.T) {
+	rt, _ := newFakeRuntime(t, "podman-compose")
+	if got := rt.EngineCommand("exec", "grund-postgres", "psql").String(); got != "podman exec grund-postgres psql" {
+		t.Errorf("EngineCommand() = %q, want podman exec", got)
+	}
+}
+
+func TestRunPsql_UsesRuntimeEngine(t *testing.T) {
+	rt, runner := newFakeRuntime(t, "podman compose")
+	runner.output["podman exec"] = "1\n"
+
+	out, err := runPsql(context.Background(), rt, "grund-postgres", "postgres", "app_db", "SELECT 1;")
+	if err != nil {
+		t.Fatalf("runPsql() error: %v", err)
+	}
+	if out != "1\n" {
+		t.Errorf("runPsql() = %q, want 1", out)
+	}
+
+	cmd := runner.commands[0]
+	if !strings.HasPrefix(cmd.String(), "podman exec -i grund-postgres psql -U postgres -d app_db") {
+		t.Errorf("command = %q, want podman exec into grund-postgres", cmd)
+	}
+	if sql, _ := io.ReadAll(cmd.Stdin); string(sql) != "SELECT 1;" {
+		t.Errorf("stdin = %q, want the SQL", sql)
+	}
+}
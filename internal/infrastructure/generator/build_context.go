@@ -0,0 +1,217 @@
+package generator
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+// hashBuildContext hashes what docker build would send for a service: every file in
+// the context not excluded by .dockerignore, plus the Dockerfile
+// Paths, modes and contents are hashed, so any edit, rename or chmod changes the result.
+func hashBuildContext(build *service.BuildConfig) (string, error) {
+	ignore, err := loadDockerignore(filepath.Join(build.Context, ".dockerignore"))
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	err = filepath.WalkDir(build.Context, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(build.Context, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if ignore.excludes(rel) {
+			// Contents of an excluded directory can only come back through a ! pattern
+			if d.IsDir() && !ignore.hasExceptions {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		return hashContextFile(h, path, rel, d)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read build context %s: %w", build.Context, err)
+	}
+
+	// The Dockerfile is always sent, even when .dockerignore excludes it or it lives outside the context
+	dockerfile := build.Dockerfile
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+	if !filepath.IsAbs(dockerfile) {
+		dockerfile = filepath.Join(build.Context, dockerfile)
+	}
+	if data, err := os.ReadFile(dockerfile); err == nil {
+		fmt.Fprintf(h, "dockerfile\x00%d\x00", len(data))
+		h.Write(data)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashContextFile adds a file's path, mode and content (or symlink target) to h
+func hashContextFile(h io.Writer, path, rel string, d fs.DirEntry) error {
+	info, err := d.Info()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(h, "%s\x00%o\x00", rel, info.Mode())
+
+	if info.Mode()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00", target)
+		return nil
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintf(h, "%d\x00", info.Size())
+	_, err = io.Copy(h, f)
+	return err
+}
+
+// dockerignore holds the patterns of a .dockerignore file
+type dockerignore struct {
+	patterns      []ignorePattern
+	hasExceptions bool // some pattern starts with !
+}
+
+type ignorePattern struct {
+	re        *regexp.Regexp
+	exception bool
+}
+
+// loadDockerignore parses a .dockerignore file; a missing file excludes nothing
+func loadDockerignore(path string) (*dockerignore, error) {
+	ignore := &dockerignore{}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ignore, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		exception := strings.HasPrefix(line, "!")
+		if exception {
+			line = strings.TrimSpace(line[1:])
+			ignore.hasExceptions = true
+		}
+		line = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(line)), "/")
+		if line == "." {
+			continue
+		}
+		re, err := dockerignorePattern(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q in %s: %w", line, path, err)
+		}
+		ignore.patterns = append(ignore.patterns, ignorePattern{re: re, exception: exception})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return ignore, nil
+}
+
+// excludes reports whether docker build leaves rel out of the context
+// As in docker, the last matching pattern wins, and a pattern matching a parent
+// directory matches everything below it.
+func (d *dockerignore) excludes(rel string) bool {
+	excluded := false
+	for _, p := range d.patterns {
+		if p.matches(rel) {
+			excluded = !p.exception
+		}
+	}
+	return excluded
+}
+
+func (p ignorePattern) matches(rel string) bool {
+	for path := rel; ; {
+		if p.re.MatchString(path) {
+			return true
+		}
+		parent := filepath.ToSlash(filepath.Dir(path))
+		if parent == "." || parent == path {
+			return false
+		}
+		path = parent
+	}
+}
+
+// dockerignorePattern converts a .dockerignore pattern to a regular expression
+// * and ? don't cross a /, ** matches any number of directories.
+func dockerignorePattern(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '*' && i+1 < len(pattern) && pattern[i+1] == '*':
+			i++
+			if i+1 < len(pattern) && pattern[i+1] == '/' {
+				// **/ matches zero or more directories
+				i++
+				b.WriteString("(.*/)?")
+			} else {
+				b.WriteString(".*")
+			}
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [")
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+func TestDockerignore_Excludes(t *testing.T) {
+	dir := t.TempDir()
+	ignore := "# comments and blank lines are skipped\n\nnode_modules\n*.log\n**/tmp\n/dist\n!dist/keep.txt\ndocs/?.md\n"
+	if err := os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte(ignore), 0644); err != nil {
+		t.Fatal(err)
+	}
+	d, err := loadDockerignore(filepath.Join(dir, ".dockerignore"))
+	if err != nil {
+		t.Fatalf("loadDockerignore() error: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"node_modules", true},
+		{"node_modules/lib/index.js", true},
+		{"app.log", true},
+		{"logs/app.log", false},
+		{"tmp", true},
+		{"a/b/tmp/cache", true},
+		{"dist/bundle.js", true},
+		{"dist/keep.txt", false},
+		{"docs/a.md", true},
+		{"docs/ab.md", false},
+		{"main.go", false},
+	}
+	for _, tt := range tests {
+		if got := d.excludes(tt.path); got != tt.want {
+			t.Errorf("excludes(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestHashBuildContext(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("Dockerfile", "FROM golang\n")
+	write(".dockerignore", "node_modules\nDockerfile\n")
+	write("main.go", "package main\n")
+	write("node_modules/dep/index.js", "1")
+
+	build := &service.BuildConfig{Context: dir, Dockerfile: "Dockerfile"}
+	hash := func() string {
+		t.Helper()
+		h, err := hashBuildContext(build)
+		if err != nil {
+			t.Fatalf("hashBuildContext() error: %v", err)
+		}
+		return h
+	}
+
+	base := hash()
+	if hash() != base {
+		t.Fatal("hash should be stable")
+	}
+
+	write("node_modules/dep/index.js", "2")
+	if hash() != base {
+		t.Error("changes to ignored files should not change the hash")
+	}
+
+	write("main.go", "package main\n\nfunc main() {}\n")
+	edited := hash()
+	if edited == base {
+		t.Error("changes to sent files should change the hash")
+	}
+
+	write("Dockerfile", "FROM golang:1.23\n")
+	if hash() == edited {
+		t.Error("Dockerfile changes should change the hash even when .dockerignore excludes it")
+	}
+}
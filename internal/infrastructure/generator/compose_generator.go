@@ -1,12 +1,17 @@
 package generator
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/config"
 	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
 	"github.com/vivekkundariya/grund/internal/domain/service"
 	"github.com/vivekkundariya/grund/internal/ui"
@@ -15,21 +20,34 @@ import (
 
 // ComposeGeneratorImpl implements ComposeGenerator
 type ComposeGeneratorImpl struct {
-	tmpDir        string // ~/.grund/tmp
+	tmpDir        string                   // ~/.grund/tmp, or the named environment's tmp directory
+	env           *config.Environment      // namespaces network, container names and host ports
+	runtime       *config.ContainerRuntime // host gateway name and engine socket
 	envResolver   ports.EnvironmentResolver
 	secretsLoader *SecretsLoader
+	localServices map[string]bool                // services run on the host (grund up --local)
+	pending       map[string]config.ServiceState // hashes from the last generate, recorded once started
 }
 
 // NewComposeGenerator creates a new compose generator
-// tmpDir should be ~/.grund/tmp
-func NewComposeGenerator(tmpDir string) ports.ComposeGenerator {
+// tmpDir should be the environment's tmp directory (~/.grund/tmp for the default environment)
+func NewComposeGenerator(tmpDir string, env *config.Environment, runtime *config.ContainerRuntime) ports.ComposeGenerator {
 	return &ComposeGeneratorImpl{
 		tmpDir:        tmpDir,
+		env:           env,
+		runtime:       runtime,
 		envResolver:   NewEnvironmentResolver(),
 		secretsLoader: NewSecretsLoader(),
+		localServices: make(map[string]bool),
 	}
 }
 
+// localEnvFile is the env file written for each host-run service
+const localEnvFile = "local.env"
+
+// networkKey is how compose files refer to the environment's shared network
+const networkKey = "grund-network"
+
 // ComposeFile represents a docker-compose.yaml structure
 type ComposeFile struct {
 	Services map[string]ComposeService `yaml:"services"`
@@ -48,7 +66,9 @@ type ComposeService struct {
 	Networks      []string          `yaml:"networks,omitempty"`
 	Healthcheck   *ComposeHealth    `yaml:"healthcheck,omitempty"`
 	Command       []string          `yaml:"command,omitempty"`
+	WorkingDir    string            `yaml:"working_dir,omitempty"`
 	ContainerName string            `yaml:"container_name,omitempty"`
+	ExtraHosts    []string          `yaml:"extra_hosts,omitempty"`
 }
 
 // ComposeBuild represents build configuration
@@ -161,23 +181,106 @@ func (pa *portAllocator) allocate(serviceName string, containerPort int) (hostPo
 // Each service goes in ~/.grund/tmp/<service>/docker-compose.yaml
 // Returns ALL compose files (including existing ones from previous runs)
 func (g *ComposeGeneratorImpl) Generate(services []*service.Service, infra infrastructure.InfrastructureRequirements) (*ports.ComposeFileSet, error) {
+	return g.generate(services, infra, nil, nil)
+}
+
+// GenerateWithTunnels generates compose files with tunnel context for env_refs resolution
+func (g *ComposeGeneratorImpl) GenerateWithTunnels(services []*service.Service, infra infrastructure.InfrastructureRequirements, tunnelCtx map[string]ports.TunnelContext) (*ports.ComposeFileSet, error) {
+	return g.generate(services, infra, tunnelCtx, nil)
+}
+
+// GenerateServices regenerates the compose files of the named services only
+// Their env is resolved against all of services; infrastructure and the other
+// services' compose files are left as they are. Host ports and the services run on
+// the host come from the manifest, as the last Generate recorded them.
+func (g *ComposeGeneratorImpl) GenerateServices(services []*service.Service, infra infrastructure.InfrastructureRequirements, names []service.ServiceName) (*ports.ComposeFileSet, error) {
+	only := make(map[string]bool, len(names))
+	for _, name := range names {
+		only[name.String()] = true
+	}
+	return g.generate(services, infra, nil, only)
+}
+
+// GeneratedEnv returns the environment in a service's current compose file
+// It returns nil, without an error, when the service has no compose file yet.
+func (g *ComposeGeneratorImpl) GeneratedEnv(name service.ServiceName) (map[string]string, error) {
+	path := filepath.Join(g.tmpDir, name.String(), "docker-compose.yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read compose file: %w", err)
+	}
+
+	var compose ComposeFile
+	if err := yaml.Unmarshal(data, &compose); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	env := make(map[string]string, len(compose.Services[name.String()].Environment))
+	for key, value := range compose.Services[name.String()].Environment {
+		env[key] = strings.ReplaceAll(value, "$$", "$")
+	}
+	return env, nil
+}
+
+// escapeComposeEnv doubles every $ so compose passes values through as resolved
+// instead of interpolating them again from the host shell.
+func escapeComposeEnv(env map[string]string) map[string]string {
+	escaped := make(map[string]string, len(env))
+	for key, value := range env {
+		escaped[key] = strings.ReplaceAll(value, "$", "$$")
+	}
+	return escaped
+}
+
+// SetLocalServices marks services that run on the host instead of in a container
+func (g *ComposeGeneratorImpl) SetLocalServices(names []service.ServiceName) {
+	g.localServices = make(map[string]bool, len(names))
+	for _, name := range names {
+		g.localServices[name.String()] = true
+	}
+}
+
+// generate writes the compose files; with only set, just those services' files are written
+func (g *ComposeGeneratorImpl) generate(services []*service.Service, infra infrastructure.InfrastructureRequirements, tunnelCtx map[string]ports.TunnelContext, only map[string]bool) (*ports.ComposeFileSet, error) {
 	fileSet := &ports.ComposeFileSet{
-		ServicePaths: make(map[string]string),
+		ServicePaths:  make(map[string]string),
+		LocalEnvPaths: make(map[string]string),
+	}
+
+	// The manifest tells active compose files from those left by removed services
+	state, err := config.LoadState(g.tmpDir)
+	if err != nil {
+		return nil, err
 	}
 
 	// First, discover existing compose files from previous runs
-	g.discoverExistingComposeFiles(fileSet)
+	g.discoverExistingComposeFiles(fileSet, state)
+
+	// Assign host ports up front so host-run services can reach containers by published port
+	// Regenerating a few services keeps the layout the running ones were generated with.
+	var layout *config.State
+	if only != nil {
+		layout = state
+	}
+	hostPorts, err := g.layoutHostPorts(services, layout)
+	if err != nil {
+		return nil, err
+	}
 
 	// Build environment context for variable resolution
 	envContext := g.buildEnvironmentContext(services, infra)
 
-	// Create port allocator to handle port conflicts
-	portAlloc := newPortAllocator()
+	// Inject tunnel context for ${tunnel.<name>.url} placeholders
+	for name, tc := range tunnelCtx {
+		envContext.Tunnel[name] = tc
+	}
 
 	// Generate infrastructure compose file if there are any infrastructure requirements
 	// Note: generateInfrastructure merges with existing infrastructure
-	if infra.Postgres != nil || infra.MongoDB != nil || infra.Redis != nil ||
-		infra.SQS != nil || infra.SNS != nil || infra.S3 != nil {
+	if only == nil && (infra.Postgres != nil || infra.MongoDB != nil || infra.Redis != nil ||
+		infra.SQS != nil || infra.SNS != nil || infra.S3 != nil) {
 		infraPath, err := g.generateInfrastructure(infra)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate infrastructure compose: %w", err)
@@ -189,63 +292,286 @@ func (g *ComposeGeneratorImpl) Generate(services []*service.Service, infra infra
 	}
 
 	// Generate per-service compose files (overwrites if service already exists)
+	// Host-run services get an env file instead of a compose file
+	var generated []*service.Service
 	for _, svc := range services {
-		svcPath, err := g.generateService(svc, envContext, portAlloc)
+		if only != nil && !only[svc.Name] {
+			continue
+		}
+		generated = append(generated, svc)
+		if g.localServices[svc.Name] {
+			envPath, err := g.generateLocalEnv(svc, envContext, hostPorts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate env for %s: %w", svc.Name, err)
+			}
+			fileSet.LocalEnvPaths[svc.Name] = envPath
+			continue
+		}
+
+		svcPath, err := g.generateService(svc, envContext, hostPorts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate compose for %s: %w", svc.Name, err)
 		}
 		fileSet.ServicePaths[svc.Name] = svcPath
 	}
 
+	// Infrastructure ports are recorded too, so other environments can tell they're taken
+	var infraPorts []ports.HostPortAssignment
+	if only == nil {
+		infraPorts = g.infrastructureHostPorts(infra)
+	}
+	if err := g.recordState(state, fileSet, generated, hostPorts, infraPorts); err != nil {
+		return nil, err
+	}
+
 	return fileSet, nil
 }
 
-// GenerateWithTunnels generates compose files with tunnel context for env_refs resolution
-func (g *ComposeGeneratorImpl) GenerateWithTunnels(services []*service.Service, infra infrastructure.InfrastructureRequirements, tunnelCtx map[string]ports.TunnelContext) (*ports.ComposeFileSet, error) {
-	fileSet := &ports.ComposeFileSet{
-		ServicePaths: make(map[string]string),
+// recordState updates the manifest with the services just generated, their host ports,
+// whether they run on the host and the infrastructure's host ports, and fills in
+// fileSet.Changes against what each service last started from
+// Without a manifest (generated by an older grund), every discovered compose file is
+// adopted as active so nothing already running disappears from status and down.
+func (g *ComposeGeneratorImpl) recordState(state *config.State, fileSet *ports.ComposeFileSet, services []*service.Service, hostPorts map[string]int, infraPorts []ports.HostPortAssignment) error {
+	generated := make(map[string]bool, len(services))
+	for _, svc := range services {
+		generated[svc.Name] = true
+	}
+	if state == nil {
+		state = config.NewState()
+		for name, path := range fileSet.ServicePaths {
+			if generated[name] {
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read compose file: %w", err)
+			}
+			entry := config.ServiceState{ConfigHash: config.HashConfig(data)}
+			if info, err := os.Stat(path); err == nil {
+				entry.GeneratedAt = info.ModTime().UTC()
+			}
+			state.Services[name] = entry
+		}
 	}
 
-	// First, discover existing compose files from previous runs
-	g.discoverExistingComposeFiles(fileSet)
+	// The recorded hashes only move on once RecordStarted confirms the services started
+	now := time.Now().UTC()
+	fileSet.Changes = make(map[string]ports.ServiceChange)
+	g.pending = make(map[string]config.ServiceState)
+	if state.HostPorts == nil {
+		state.HostPorts = make(map[string]int)
+	}
+	for _, a := range infraPorts {
+		state.HostPorts[a.Owner] = a.HostPort
+	}
+	for _, svc := range services {
+		state.HostPorts[svc.Name] = hostPorts[svc.Name]
+		state.SetLocal(svc.Name, g.localServices[svc.Name])
 
-	// Build environment context for variable resolution
-	envContext := g.buildEnvironmentContext(services, infra)
+		path, ok := fileSet.ServicePaths[svc.Name]
+		if !ok {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read compose file: %w", err)
+		}
+		current := config.ServiceState{ConfigHash: config.HashConfig(data)}
+		if svc.Build != nil {
+			if current.BuildHash, err = hashBuildContext(svc.Build); err != nil {
+				return fmt.Errorf("failed to hash build context of %s: %w", svc.Name, err)
+			}
+		}
 
-	// Inject tunnel context for ${tunnel.<name>.url} placeholders
-	for name, tc := range tunnelCtx {
-		envContext.Tunnel[name] = tc
+		entry := state.Services[svc.Name]
+		fileSet.Changes[svc.Name] = ports.ServiceChange{
+			Config: entry.ConfigHash != current.ConfigHash,
+			Build:  entry.BuildHash != current.BuildHash,
+		}
+		g.pending[svc.Name] = current
+
+		entry.GeneratedAt = now
+		state.Services[svc.Name] = entry
+	}
+
+	return config.SaveState(g.tmpDir, state)
+}
+
+// RecordStarted records that services now run from the compose files and build contexts last generated
+func (g *ComposeGeneratorImpl) RecordStarted(names []service.ServiceName) error {
+	state, err := config.LoadState(g.tmpDir)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		state = config.NewState()
+	}
+
+	for _, name := range names {
+		current, ok := g.pending[name.String()]
+		if !ok {
+			continue
+		}
+		entry := state.Services[name.String()]
+		entry.ConfigHash = current.ConfigHash
+		entry.BuildHash = current.BuildHash
+		state.Services[name.String()] = entry
+	}
+
+	return config.SaveState(g.tmpDir, state)
+}
+
+// allocateHostPorts assigns a published host port to every service, warning about conflicts
+// It fails when the environment's port offset pushes a port past 65535.
+func (g *ComposeGeneratorImpl) allocateHostPorts(services []*service.Service) (map[string]int, error) {
+	hostPorts := make(map[string]int, len(services))
+	for _, a := range g.assignHostPorts(services) {
+		if a.HostPort > config.MaxHostPort {
+			return nil, fmt.Errorf("%s: host port %d is out of range (port %d + environment %s offset %d); use a lower port or another environment",
+				a.Owner, a.HostPort, a.ContainerPort, g.env.Name, g.env.PortOffset)
+		}
+		if a.ConflictsWith != "" {
+			if g.localServices[a.Owner] {
+				ui.Warnf("Port conflict: %s runs on host port %d, which is also used by %s", a.Owner, a.HostPort, a.ConflictsWith)
+			} else {
+				ui.Warnf("Port conflict: %s uses container port %d, assigned host port %d", a.Owner, a.ContainerPort, a.HostPort)
+			}
+		}
+		hostPorts[a.Owner] = a.HostPort
+	}
+	return hostPorts, nil
+}
+
+// layoutHostPorts allocates host ports like allocateHostPorts, but with a manifest the
+// services it records as run on the host, and the host ports it records, are kept
+func (g *ComposeGeneratorImpl) layoutHostPorts(services []*service.Service, state *config.State) (map[string]int, error) {
+	if state != nil {
+		g.SetLocalServices(nil)
+		for _, name := range state.Local {
+			g.localServices[name] = true
+		}
+	}
+
+	hostPorts, err := g.allocateHostPorts(services)
+	if err != nil {
+		return nil, err
+	}
+	if state != nil {
+		for name, port := range state.HostPorts {
+			if _, ok := hostPorts[name]; ok {
+				hostPorts[name] = port
+			}
+		}
 	}
+	return hostPorts, nil
+}
 
-	// Create port allocator to handle port conflicts
+// assignHostPorts assigns a published host port to every service
+// Host-run services listen on their configured port directly, so they are reserved first.
+// Container services are allocated without conflicts, then shifted by the environment's port offset.
+func (g *ComposeGeneratorImpl) assignHostPorts(services []*service.Service) []ports.HostPortAssignment {
 	portAlloc := newPortAllocator()
+	assignments := make([]ports.HostPortAssignment, 0, len(services))
 
-	// Generate infrastructure compose file if there are any infrastructure requirements
-	if infra.Postgres != nil || infra.MongoDB != nil || infra.Redis != nil ||
-		infra.SQS != nil || infra.SNS != nil || infra.S3 != nil {
-		infraPath, err := g.generateInfrastructure(infra)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate infrastructure compose: %w", err)
+	for _, svc := range services {
+		if g.localServices[svc.Name] {
+			port := svc.Port.Value()
+			owner := portAlloc.usedPorts[port]
+			portAlloc.usedPorts[port] = svc.Name
+			assignments = append(assignments, ports.HostPortAssignment{
+				Owner:         svc.Name,
+				HostPort:      port,
+				ContainerPort: port,
+				ConflictsWith: owner,
+			})
 		}
-		fileSet.InfrastructurePath = infraPath
-	} else if fileSet.InfrastructurePath != "" {
-		ui.Debug("Keeping existing infrastructure compose file")
 	}
 
-	// Generate per-service compose files
 	for _, svc := range services {
-		svcPath, err := g.generateService(svc, envContext, portAlloc)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate compose for %s: %w", svc.Name, err)
+		if g.localServices[svc.Name] {
+			continue
 		}
-		fileSet.ServicePaths[svc.Name] = svcPath
+		containerPort := svc.Port.Value()
+		owner := portAlloc.usedPorts[containerPort]
+		hostPort, _ := portAlloc.allocate(svc.Name, containerPort)
+		assignments = append(assignments, ports.HostPortAssignment{
+			Owner:         svc.Name,
+			HostPort:      g.env.HostPort(hostPort),
+			ContainerPort: containerPort,
+			ConflictsWith: owner,
+		})
 	}
 
-	return fileSet, nil
+	return assignments
+}
+
+// HostPorts returns the host ports Generate would publish, infrastructure first
+func (g *ComposeGeneratorImpl) HostPorts(services []*service.Service, infra infrastructure.InfrastructureRequirements) []ports.HostPortAssignment {
+	return append(g.infrastructureHostPorts(infra), g.assignHostPorts(services)...)
+}
+
+// infrastructureHostPorts returns the host ports of the infrastructure containers infra needs
+func (g *ComposeGeneratorImpl) infrastructureHostPorts(infra infrastructure.InfrastructureRequirements) []ports.HostPortAssignment {
+	var assignments []ports.HostPortAssignment
+	for _, c := range []struct {
+		name string
+		port int
+		used bool
+	}{
+		{"postgres", 5432, infra.Postgres != nil},
+		{"mongodb", 27017, infra.MongoDB != nil},
+		{"redis", 6379, infra.Redis != nil},
+		{"localstack", 4566, infra.SQS != nil || infra.SNS != nil || infra.S3 != nil},
+	} {
+		if c.used {
+			assignments = append(assignments, ports.HostPortAssignment{Owner: c.name, HostPort: g.env.HostPort(c.port), ContainerPort: c.port})
+		}
+	}
+	return assignments
+}
+
+// ResolveEnv resolves one service's environment without writing any files
+// Peers and infrastructure come from services and infra. Published ports and host-run
+// services are those grund up recorded in the manifest, or assigned as Generate would
+// without one. With host set, the environment is resolved for a process on the host.
+func (g *ComposeGeneratorImpl) ResolveEnv(services []*service.Service, infra infrastructure.InfrastructureRequirements, name service.ServiceName, host bool) (map[string]string, error) {
+	var svc *service.Service
+	for _, s := range services {
+		if s.Name == name.String() {
+			svc = s
+		}
+	}
+	if svc == nil {
+		return nil, fmt.Errorf("service %s not found", name)
+	}
+
+	state, err := config.LoadState(g.tmpDir)
+	if err != nil {
+		return nil, err
+	}
+	hostPorts, err := g.layoutHostPorts(services, state)
+	if err != nil {
+		return nil, err
+	}
+	envContext := g.buildEnvironmentContext(services, infra)
+
+	if host || g.localServices[svc.Name] {
+		return g.resolveServiceEnv(svc, g.hostSelfContext(svc, envContext, hostPorts))
+	}
+
+	selfContext := envContext
+	selfContext.Self = ports.ServiceContext{
+		Host:   svc.Name,
+		Port:   svc.Port.Value(),
+		Config: serviceConfig(svc),
+	}
+	return g.resolveServiceEnv(svc, selfContext)
 }
 
 // discoverExistingComposeFiles scans tmpDir for existing compose files
-func (g *ComposeGeneratorImpl) discoverExistingComposeFiles(fileSet *ports.ComposeFileSet) {
+// With a manifest, only the services it lists are included.
+func (g *ComposeGeneratorImpl) discoverExistingComposeFiles(fileSet *ports.ComposeFileSet, state *config.State) {
 	// Check if tmp directory exists
 	if _, err := os.Stat(g.tmpDir); os.IsNotExist(err) {
 		return
@@ -270,8 +596,10 @@ func (g *ComposeGeneratorImpl) discoverExistingComposeFiles(fileSet *ports.Compo
 
 		if entry.Name() == "infrastructure" {
 			fileSet.InfrastructurePath = composePath
-		} else {
+		} else if state == nil || state.Has(entry.Name()) {
 			fileSet.ServicePaths[entry.Name()] = composePath
+		} else {
+			ui.Debug("Ignoring stale compose file %s (run 'grund prune' to remove it)", composePath)
 		}
 	}
 
@@ -301,7 +629,7 @@ func (g *ComposeGeneratorImpl) generateInfrastructure(infra infrastructure.Infra
 	compose := &ComposeFile{
 		Services: make(map[string]ComposeService),
 		Networks: map[string]ComposeNetwork{
-			"grund-network": {Driver: "bridge", Name: "grund-network"},
+			networkKey: {Driver: "bridge", Name: g.env.NetworkName()},
 		},
 		Volumes: make(map[string]ComposeVolume),
 	}
@@ -354,7 +682,7 @@ func getServiceNames(services map[string]ComposeService) []string {
 }
 
 // generateService generates a single service's docker-compose.yaml
-func (g *ComposeGeneratorImpl) generateService(svc *service.Service, envContext ports.EnvironmentContext, portAlloc *portAllocator) (string, error) {
+func (g *ComposeGeneratorImpl) generateService(svc *service.Service, envContext ports.EnvironmentContext, hostPorts map[string]int) (string, error) {
 	svcDir := filepath.Join(g.tmpDir, svc.Name)
 	if err := os.MkdirAll(svcDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create service directory: %w", err)
@@ -363,23 +691,20 @@ func (g *ComposeGeneratorImpl) generateService(svc *service.Service, envContext
 	compose := &ComposeFile{
 		Services: make(map[string]ComposeService),
 		Networks: map[string]ComposeNetwork{
-			"grund-network": {External: true, Name: "grund-network"},
+			networkKey: {External: true, Name: g.env.NetworkName()},
 		},
 	}
 
 	// Build self context for this service
 	selfContext := envContext
 	selfContext.Self = ports.ServiceContext{
-		Host: svc.Name,
-		Port: svc.Port.Value(),
-		Config: map[string]any{
-			"postgres.database": getServicePostgresDB(svc),
-			"mongodb.database":  getServiceMongoDB(svc),
-		},
+		Host:   svc.Name,
+		Port:   svc.Port.Value(),
+		Config: serviceConfig(svc),
 	}
 
 	// Add this service to the compose file
-	if err := g.addSingleService(compose, svc, selfContext, portAlloc); err != nil {
+	if err := g.addSingleService(compose, svc, selfContext, hostPorts[svc.Name]); err != nil {
 		return "", err
 	}
 
@@ -392,29 +717,92 @@ func (g *ComposeGeneratorImpl) generateService(svc *service.Service, envContext
 }
 
 // writeComposeFile writes a compose file to disk
+// An unchanged file is left alone so its modification time still says when it last changed.
 func (g *ComposeGeneratorImpl) writeComposeFile(outputPath string, compose *ComposeFile) error {
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create compose file: %w", err)
-	}
-	defer file.Close()
+	var buf bytes.Buffer
 
 	// Add header comment
-	fmt.Fprintf(file, "# AUTO-GENERATED by grund - DO NOT EDIT\n")
-	fmt.Fprintf(file, "# Regenerate with: grund up <services>\n\n")
+	fmt.Fprintf(&buf, "# AUTO-GENERATED by grund - DO NOT EDIT\n")
+	fmt.Fprintf(&buf, "# Regenerate with: grund up <services>\n\n")
 
-	encoder := yaml.NewEncoder(file)
+	encoder := yaml.NewEncoder(&buf)
 	encoder.SetIndent(2)
 	if err := encoder.Encode(compose); err != nil {
 		return fmt.Errorf("failed to write compose file: %w", err)
 	}
 
+	if existing, err := os.ReadFile(outputPath); err == nil && bytes.Equal(existing, buf.Bytes()) {
+		ui.Debug("%s is up to date", outputPath)
+		return nil
+	}
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to create compose file: %w", err)
+	}
+
 	return nil
 }
 
 // addSingleService adds a single service to a compose file
-func (g *ComposeGeneratorImpl) addSingleService(compose *ComposeFile, svc *service.Service, selfContext ports.EnvironmentContext, portAlloc *portAllocator) error {
-	// Resolve environment variables
+func (g *ComposeGeneratorImpl) addSingleService(compose *ComposeFile, svc *service.Service, selfContext ports.EnvironmentContext, hostPort int) error {
+	resolvedEnv, err := g.resolveServiceEnv(svc, selfContext)
+	if err != nil {
+		return err
+	}
+
+	// Build depends_on with conditions
+	dependsOn := g.buildDependsOn(svc)
+
+	// Create compose service
+	composeService := ComposeService{
+		ContainerName: g.env.ContainerName(svc.Name),
+		Environment:   escapeComposeEnv(resolvedEnv),
+		Networks:      []string{networkKey},
+		DependsOn:     dependsOn,
+	}
+
+	// Set build or image
+	if svc.Build != nil {
+		composeService.Build = &ComposeBuild{
+			Context:    svc.Build.Context,
+			Dockerfile: svc.Build.Dockerfile,
+		}
+	}
+
+	// Override the image command and mount the source for hot reload
+	if svc.Run != nil {
+		if svc.Run.Command != "" {
+			composeService.Command = []string{"sh", "-c", svc.Run.Command}
+		}
+		if svc.Run.HotReload && svc.Run.Source != "" {
+			composeService.Volumes = []string{fmt.Sprintf("%s:%s", svc.Run.Source, svc.Run.MountPath())}
+			composeService.WorkingDir = svc.Run.MountPath()
+		}
+	}
+
+	// Set ports (host port assigned by allocateHostPorts)
+	composeService.Ports = []string{fmt.Sprintf("%d:%d", hostPort, svc.Port.Value())}
+
+	// Let containers reach services running on the host (Docker on Linux needs the explicit mapping)
+	if len(g.localServices) > 0 && g.runtime.HostGatewayMapping {
+		composeService.ExtraHosts = []string{g.runtime.HostGateway + ":host-gateway"}
+	}
+
+	// Set healthcheck
+	if svc.Health.Endpoint != "" {
+		composeService.Healthcheck = &ComposeHealth{
+			Test:     []string{"CMD-SHELL", fmt.Sprintf("curl -sf http://localhost:%d%s || exit 1", svc.Port.Value(), svc.Health.Endpoint)},
+			Interval: svc.Health.Interval.String(),
+			Timeout:  svc.Health.Timeout.String(),
+			Retries:  svc.Health.Retries,
+		}
+	}
+
+	compose.Services[svc.Name] = composeService
+	return nil
+}
+
+// resolveServiceEnv builds a service's environment from static vars, env_refs and secrets
+func (g *ComposeGeneratorImpl) resolveServiceEnv(svc *service.Service, selfContext ports.EnvironmentContext) (map[string]string, error) {
 	resolvedEnv := make(map[string]string)
 
 	// Add static environment variables
@@ -422,11 +810,28 @@ func (g *ComposeGeneratorImpl) addSingleService(compose *ComposeFile, svc *servi
 		resolvedEnv[k] = v
 	}
 
+	// Resolve secrets first so env_refs can interpolate them
+	var secrets map[string]string
+	if len(svc.Environment.Secrets) > 0 {
+		var err error
+		secrets, err = g.secretsLoader.ResolveSecrets(svc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve secrets: %w", err)
+		}
+	}
+
 	// Resolve environment references
 	if len(svc.Environment.References) > 0 {
+		selfContext.Env = svc.Environment.Variables
+		selfContext.Secrets = secrets
+
 		resolved, err := g.envResolver.Resolve(svc.Environment.References, selfContext)
 		if err != nil {
-			return fmt.Errorf("failed to resolve env: %w", err)
+			var unresolved *ports.UnresolvedReferencesError
+			if errors.As(err, &unresolved) {
+				unresolved.Locate(svc.Environment.Source, svc.Environment.ReferenceLines)
+			}
+			return nil, fmt.Errorf("failed to resolve env for %s: %w", svc.Name, err)
 		}
 		for k, v := range resolved {
 			resolvedEnv[k] = v
@@ -443,57 +848,116 @@ func (g *ComposeGeneratorImpl) addSingleService(compose *ComposeFile, svc *servi
 	}
 
 	// Add resolved secrets
-	if len(svc.Environment.Secrets) > 0 {
-		secrets, err := g.secretsLoader.ResolveSecrets(svc)
-		if err != nil {
-			return fmt.Errorf("failed to resolve secrets: %w", err)
-		}
-		for k, v := range secrets {
-			resolvedEnv[k] = v
-		}
+	for k, v := range secrets {
+		resolvedEnv[k] = v
 	}
 
-	// Build depends_on with conditions
-	dependsOn := g.buildDependsOn(svc)
+	return resolvedEnv, nil
+}
 
-	// Create compose service
-	composeService := ComposeService{
-		ContainerName: fmt.Sprintf("grund-%s", svc.Name),
-		Environment:   resolvedEnv,
-		Networks:      []string{"grund-network"},
-		DependsOn:     dependsOn,
+// generateLocalEnv resolves a host-run service's env and writes it to ~/.grund/tmp/<service>/local.env
+func (g *ComposeGeneratorImpl) generateLocalEnv(svc *service.Service, envContext ports.EnvironmentContext, hostPorts map[string]int) (string, error) {
+	svcDir := filepath.Join(g.tmpDir, svc.Name)
+	if err := os.MkdirAll(svcDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create service directory: %w", err)
 	}
 
-	// Set build or image
-	if svc.Build != nil {
-		composeService.Build = &ComposeBuild{
-			Context:    svc.Build.Context,
-			Dockerfile: svc.Build.Dockerfile,
-		}
+	env, err := g.resolveServiceEnv(svc, g.hostSelfContext(svc, envContext, hostPorts))
+	if err != nil {
+		return "", err
 	}
 
-	// Set ports with conflict detection
-	containerPort := svc.Port.Value()
-	hostPort, wasReassigned := portAlloc.allocate(svc.Name, containerPort)
-	if wasReassigned {
-		ui.Warnf("Port conflict: %s uses container port %d, assigned host port %d", svc.Name, containerPort, hostPort)
+	outputPath := filepath.Join(svcDir, localEnvFile)
+	if err := writeEnvFile(outputPath, env); err != nil {
+		return "", err
 	}
-	composeService.Ports = []string{fmt.Sprintf("%d:%d", hostPort, containerPort)}
+	return outputPath, nil
+}
 
-	// Set healthcheck
-	if svc.Health.Endpoint != "" {
-		composeService.Healthcheck = &ComposeHealth{
-			Test:     []string{"CMD-SHELL", fmt.Sprintf("curl -sf http://localhost:%d%s || exit 1", svc.Port.Value(), svc.Health.Endpoint)},
-			Interval: svc.Health.Interval.String(),
-			Timeout:  svc.Health.Timeout.String(),
-			Retries:  svc.Health.Retries,
+// hostSelfContext is the environment context of a service running on the host
+func (g *ComposeGeneratorImpl) hostSelfContext(svc *service.Service, envContext ports.EnvironmentContext, hostPorts map[string]int) ports.EnvironmentContext {
+	hostContext := g.buildHostEnvironmentContext(envContext, hostPorts)
+	hostContext.Self = ports.ServiceContext{
+		Host:   "localhost",
+		Port:   svc.Port.Value(),
+		Config: serviceConfig(svc),
+	}
+	return hostContext
+}
+
+// buildHostEnvironmentContext rewrites a container environment context for a process on the host
+// Infrastructure and peers are reached through localhost and their published host ports.
+func (g *ComposeGeneratorImpl) buildHostEnvironmentContext(ctx ports.EnvironmentContext, hostPorts map[string]int) ports.EnvironmentContext {
+	hostLocalStackEndpoint := fmt.Sprintf("http://localhost:%d", g.env.HostPort(4566))
+	containerEndpoint := ctx.LocalStack.Endpoint
+
+	host := ctx
+	host.LocalStack.Endpoint = hostLocalStackEndpoint
+
+	// Infrastructure publishes its standard ports on the host, shifted per environment
+	host.Infrastructure = make(map[string]ports.InfrastructureContext, len(ctx.Infrastructure))
+	for name, infra := range ctx.Infrastructure {
+		infra.Host = "localhost"
+		infra.Port = g.env.HostPort(infra.Port)
+		host.Infrastructure[name] = infra
+	}
+
+	host.Services = make(map[string]ports.ServiceContext, len(ctx.Services))
+	for name, peer := range ctx.Services {
+		peer.Host = "localhost"
+		if port, ok := hostPorts[name]; ok {
+			peer.Port = port
 		}
+		host.Services[name] = peer
 	}
 
-	compose.Services[svc.Name] = composeService
+	host.SQS = make(map[string]ports.QueueContext, len(ctx.SQS))
+	for name, queue := range ctx.SQS {
+		queue.URL = strings.Replace(queue.URL, containerEndpoint, hostLocalStackEndpoint, 1)
+		queue.DLQ = strings.Replace(queue.DLQ, containerEndpoint, hostLocalStackEndpoint, 1)
+		host.SQS[name] = queue
+	}
+
+	host.S3 = make(map[string]ports.BucketContext, len(ctx.S3))
+	for name, bucket := range ctx.S3 {
+		bucket.URL = strings.Replace(bucket.URL, containerEndpoint, hostLocalStackEndpoint, 1)
+		host.S3[name] = bucket
+	}
+
+	return host
+}
+
+// writeEnvFile writes env vars in dotenv format, sorted by key
+// The file may contain secrets, so it is only readable by the owner.
+func writeEnvFile(path string, env map[string]string) error {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# AUTO-GENERATED by grund - DO NOT EDIT\n")
+	fmt.Fprintf(&b, "# Regenerate with: grund up <services> --local <service>\n\n")
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s=%s\n", k, QuoteEnvValue(env[k]))
+	}
+
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write env file: %w", err)
+	}
 	return nil
 }
 
+// QuoteEnvValue double-quotes values that a dotenv parser would otherwise misread
+func QuoteEnvValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n\"'#$\\`") {
+		return value
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "$", `\$`, "`", "\\`")
+	return `"` + replacer.Replace(value) + `"`
+}
+
 func (g *ComposeGeneratorImpl) buildEnvironmentContext(services []*service.Service, infra infrastructure.InfrastructureRequirements) ports.EnvironmentContext {
 	ctx := ports.NewDefaultEnvironmentContext()
 
@@ -557,13 +1021,14 @@ func (g *ComposeGeneratorImpl) buildEnvironmentContext(services []*service.Servi
 
 	// Add service contexts
 	for _, svc := range services {
+		host := svc.Name // Container name in Docker network
+		if g.localServices[svc.Name] {
+			host = g.runtime.HostGateway // Runs on the host, listening on its own port
+		}
 		ctx.Services[svc.Name] = ports.ServiceContext{
-			Host: svc.Name, // Container name in Docker network
-			Port: svc.Port.Value(),
-			Config: map[string]any{
-				"postgres.database": getServicePostgresDB(svc),
-				"mongodb.database":  getServiceMongoDB(svc),
-			},
+			Host:   host,
+			Port:   svc.Port.Value(),
+			Config: serviceConfig(svc),
 		}
 	}
 
@@ -575,15 +1040,15 @@ func (g *ComposeGeneratorImpl) addInfrastructureServices(compose *ComposeFile, i
 	if infra.Postgres != nil {
 		compose.Services["postgres"] = ComposeService{
 			Image:         "postgres:15-alpine",
-			ContainerName: "grund-postgres",
-			Ports:         []string{"5432:5432"},
+			ContainerName: g.env.ContainerName("postgres"),
+			Ports:         []string{g.publish(5432)},
 			Environment: map[string]string{
 				"POSTGRES_USER":     "postgres",
 				"POSTGRES_PASSWORD": "postgres",
 				"POSTGRES_DB":       infra.Postgres.Database,
 			},
 			Volumes:  []string{"postgres-data:/var/lib/postgresql/data"},
-			Networks: []string{"grund-network"},
+			Networks: []string{networkKey},
 			Healthcheck: &ComposeHealth{
 				Test:     []string{"CMD-SHELL", "pg_isready -U postgres"},
 				Interval: "5s",
@@ -598,13 +1063,13 @@ func (g *ComposeGeneratorImpl) addInfrastructureServices(compose *ComposeFile, i
 	if infra.MongoDB != nil {
 		compose.Services["mongodb"] = ComposeService{
 			Image:         "mongo:6",
-			ContainerName: "grund-mongodb",
-			Ports:         []string{"27017:27017"},
+			ContainerName: g.env.ContainerName("mongodb"),
+			Ports:         []string{g.publish(27017)},
 			Environment: map[string]string{
 				"MONGO_INITDB_DATABASE": infra.MongoDB.Database,
 			},
 			Volumes:  []string{"mongodb-data:/data/db"},
-			Networks: []string{"grund-network"},
+			Networks: []string{networkKey},
 			Healthcheck: &ComposeHealth{
 				Test:     []string{"CMD", "mongosh", "--eval", "db.adminCommand('ping')"},
 				Interval: "5s",
@@ -619,9 +1084,9 @@ func (g *ComposeGeneratorImpl) addInfrastructureServices(compose *ComposeFile, i
 	if infra.Redis != nil {
 		compose.Services["redis"] = ComposeService{
 			Image:         "redis:7-alpine",
-			ContainerName: "grund-redis",
-			Ports:         []string{"6379:6379"},
-			Networks:      []string{"grund-network"},
+			ContainerName: g.env.ContainerName("redis"),
+			Ports:         []string{g.publish(6379)},
+			Networks:      []string{networkKey},
 			Healthcheck: &ComposeHealth{
 				Test:     []string{"CMD", "redis-cli", "ping"},
 				Interval: "5s",
@@ -644,22 +1109,20 @@ func (g *ComposeGeneratorImpl) addInfrastructureServices(compose *ComposeFile, i
 			services = append(services, "s3")
 		}
 
-		compose.Services["localstack"] = ComposeService{
+		localstack := ComposeService{
 			Image:         "localstack/localstack:latest",
-			ContainerName: "grund-localstack",
-			Ports:         []string{"4566:4566"},
+			ContainerName: g.env.ContainerName("localstack"),
+			Ports:         []string{g.publish(4566)},
 			Environment: map[string]string{
 				"SERVICES":           strings.Join(services, ","),
 				"DEBUG":              "0",
 				"AWS_DEFAULT_REGION": "us-east-1",
 				"AWS_ACCOUNT_ID":     "000000000000",
-				"DOCKER_HOST":        "unix:///var/run/docker.sock",
 			},
 			Volumes: []string{
-				"/var/run/docker.sock:/var/run/docker.sock",
 				"localstack-data:/var/lib/localstack",
 			},
-			Networks: []string{"grund-network"},
+			Networks: []string{networkKey},
 			Healthcheck: &ComposeHealth{
 				Test:        []string{"CMD-SHELL", "curl -sf http://localhost:4566/_localstack/health || exit 1"},
 				Interval:    "10s",
@@ -668,10 +1131,25 @@ func (g *ComposeGeneratorImpl) addInfrastructureServices(compose *ComposeFile, i
 				StartPeriod: "20s",
 			},
 		}
+
+		// LocalStack starts containers (e.g. Lambda) through the engine's Docker-compatible
+		// API; the host socket (rootless Podman's lives under XDG_RUNTIME_DIR) is mounted
+		// at the path DOCKER_HOST points to inside the container
+		if g.runtime.Socket != "" {
+			localstack.Environment["DOCKER_HOST"] = "unix:///var/run/docker.sock"
+			localstack.Volumes = append([]string{g.runtime.Socket + ":/var/run/docker.sock"}, localstack.Volumes...)
+		}
+
+		compose.Services["localstack"] = localstack
 		compose.Volumes["localstack-data"] = ComposeVolume{}
 	}
 }
 
+// publish maps a container port to the environment's host port
+func (g *ComposeGeneratorImpl) publish(containerPort int) string {
+	return fmt.Sprintf("%d:%d", g.env.HostPort(containerPort), containerPort)
+}
+
 func (g *ComposeGeneratorImpl) buildDependsOn(svc *service.Service) map[string]DependsOnCondition {
 	dependsOn := make(map[string]DependsOnCondition)
 
@@ -699,6 +1177,18 @@ func (g *ComposeGeneratorImpl) buildDependsOn(svc *service.Service) map[string]D
 }
 
 // Helper functions
+
+// serviceConfig builds the per-service values exposed as ${self.*} and ${<service>.*}
+func serviceConfig(svc *service.Service) map[string]any {
+	username, password := getServicePostgresCredentials(svc)
+	return map[string]any{
+		"postgres.database": getServicePostgresDB(svc),
+		"postgres.username": username,
+		"postgres.password": password,
+		"mongodb.database":  getServiceMongoDB(svc),
+	}
+}
+
 func getServicePostgresDB(svc *service.Service) string {
 	if svc.Dependencies.Infrastructure.Postgres != nil {
 		return svc.Dependencies.Infrastructure.Postgres.Database
@@ -706,6 +1196,13 @@ func getServicePostgresDB(svc *service.Service) string {
 	return ""
 }
 
+func getServicePostgresCredentials(svc *service.Service) (string, string) {
+	if svc.Dependencies.Infrastructure.Postgres != nil {
+		return svc.Dependencies.Infrastructure.Postgres.AsDatabase().Credentials()
+	}
+	return "", ""
+}
+
 func getServiceMongoDB(svc *service.Service) string {
 	if svc.Dependencies.Infrastructure.MongoDB != nil {
 		return svc.Dependencies.Infrastructure.MongoDB.Database
@@ -0,0 +1,590 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/config"
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
+	"github.com/vivekkundariya/grund/internal/domain/service"
+	"gopkg.in/yaml.v3"
+)
+
+func newTestService(name string, port int, refs map[string]string) *service.Service {
+	p, _ := service.NewPort(port)
+	return &service.Service{
+		Name:   name,
+		Type:   service.ServiceTypeGo,
+		Port:   p,
+		Build:  &service.BuildConfig{Dockerfile: "Dockerfile", Context: "."},
+		Health: service.HealthConfig{Interval: 5 * time.Second, Timeout: 3 * time.Second, Retries: 3},
+		Dependencies: service.ServiceDependencies{
+			Infrastructure: infrastructure.InfrastructureRequirements{
+				Postgres: &infrastructure.PostgresConfig{Database: name + "_db"},
+				SQS:      &infrastructure.SQSConfig{Queues: []infrastructure.QueueConfig{{Name: "orders"}}},
+			},
+		},
+		Environment: service.Environment{References: refs},
+	}
+}
+
+func readComposeFile(t *testing.T, path string) ComposeFile {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	var compose ComposeFile
+	if err := yaml.Unmarshal(data, &compose); err != nil {
+		t.Fatalf("failed to parse %s: %v", path, err)
+	}
+	return compose
+}
+
+func TestGenerate_LocalService(t *testing.T) {
+	tmpDir := t.TempDir()
+	api := newTestService("api", 8080, map[string]string{
+		"DATABASE_URL": "postgres://${postgres.host}:${postgres.port}/${self.postgres.database}",
+		"QUEUE_URL":    "${sqs.orders.url}",
+		"WORKER_URL":   "http://${worker.host}:${worker.port}",
+	})
+	worker := newTestService("worker", 8080, map[string]string{
+		"API_URL": "http://${api.host}:${api.port}",
+	})
+
+	g := NewComposeGenerator(tmpDir, config.DefaultEnvironment(), config.DefaultRuntime())
+	g.SetLocalServices([]service.ServiceName{"api"})
+
+	services := []*service.Service{api, worker}
+	infra := infrastructure.Aggregate(api.Dependencies.Infrastructure, worker.Dependencies.Infrastructure)
+	fileSet, err := g.Generate(services, infra)
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+
+	if _, ok := fileSet.ServicePaths["api"]; ok {
+		t.Error("Expected no compose file for host-run service")
+	}
+
+	// The host-run service talks to infrastructure and peers via localhost + published ports
+	envData, err := os.ReadFile(fileSet.LocalEnvPaths["api"])
+	if err != nil {
+		t.Fatalf("failed to read local env: %v", err)
+	}
+	env := string(envData)
+	for _, want := range []string{
+		"DATABASE_URL=postgres://localhost:5432/api_db",
+		"QUEUE_URL=http://localhost:4566/000000000000/orders",
+		"WORKER_URL=http://localhost:8081", // 8080 is taken by api on the host
+		"AWS_ENDPOINT=http://localhost:4566",
+	} {
+		if !strings.Contains(env, want) {
+			t.Errorf("local env missing %q:\n%s", want, env)
+		}
+	}
+
+	// Containers reach the host-run service through the host gateway
+	compose := readComposeFile(t, fileSet.ServicePaths["worker"])
+	svc := compose.Services["worker"]
+	if got := svc.Environment["API_URL"]; got != "http://host.docker.internal:8080" {
+		t.Errorf("worker API_URL = %q, want http://host.docker.internal:8080", got)
+	}
+	if len(svc.ExtraHosts) != 1 || svc.ExtraHosts[0] != "host.docker.internal:host-gateway" {
+		t.Errorf("worker extra_hosts = %v, want host gateway mapping", svc.ExtraHosts)
+	}
+	if len(svc.Ports) != 1 || svc.Ports[0] != "8081:8080" {
+		t.Errorf("worker ports = %v, want [8081:8080]", svc.Ports)
+	}
+}
+
+func TestGenerate_NoLocalServices(t *testing.T) {
+	tmpDir := t.TempDir()
+	api := newTestService("api", 8080, nil)
+
+	fileSet, err := NewComposeGenerator(tmpDir, config.DefaultEnvironment(), config.DefaultRuntime()).Generate([]*service.Service{api}, api.Dependencies.Infrastructure)
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+
+	compose := readComposeFile(t, fileSet.ServicePaths["api"])
+	if hosts := compose.Services["api"].ExtraHosts; len(hosts) != 0 {
+		t.Errorf("Expected no extra_hosts without local services, got %v", hosts)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "api", localEnvFile)); !os.IsNotExist(err) {
+		t.Error("Expected no local env file")
+	}
+}
+
+func TestGenerate_NamedEnvironment(t *testing.T) {
+	tmpDir := t.TempDir()
+	api := newTestService("api", 8080, nil)
+	worker := newTestService("worker", 8081, map[string]string{
+		"DATABASE_URL": "postgres://${postgres.host}:${postgres.port}/${self.postgres.database}",
+	})
+
+	g := NewComposeGenerator(tmpDir, &config.Environment{Name: "review", PortOffset: 1000}, config.DefaultRuntime())
+	g.SetLocalServices([]service.ServiceName{"worker"})
+
+	services := []*service.Service{api, worker}
+	infra := infrastructure.Aggregate(api.Dependencies.Infrastructure, worker.Dependencies.Infrastructure)
+	fileSet, err := g.Generate(services, infra)
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+
+	infraCompose := readComposeFile(t, fileSet.InfrastructurePath)
+	if net := infraCompose.Networks["grund-network"]; net.Name != "grund-review-network" {
+		t.Errorf("network name = %q, want grund-review-network", net.Name)
+	}
+	postgres := infraCompose.Services["postgres"]
+	if postgres.ContainerName != "grund-review-postgres" {
+		t.Errorf("postgres container_name = %q, want grund-review-postgres", postgres.ContainerName)
+	}
+	if len(postgres.Ports) != 1 || postgres.Ports[0] != "6432:5432" {
+		t.Errorf("postgres ports = %v, want [6432:5432]", postgres.Ports)
+	}
+
+	apiSvc := readComposeFile(t, fileSet.ServicePaths["api"]).Services["api"]
+	if apiSvc.ContainerName != "grund-review-api" {
+		t.Errorf("api container_name = %q, want grund-review-api", apiSvc.ContainerName)
+	}
+	if len(apiSvc.Ports) != 1 || apiSvc.Ports[0] != "9080:8080" {
+		t.Errorf("api ports = %v, want [9080:8080]", apiSvc.Ports)
+	}
+
+	// Host-run services reach infrastructure on the environment's shifted ports
+	envData, err := os.ReadFile(fileSet.LocalEnvPaths["worker"])
+	if err != nil {
+		t.Fatalf("failed to read local env: %v", err)
+	}
+	if !strings.Contains(string(envData), "DATABASE_URL=postgres://localhost:6432/worker_db") {
+		t.Errorf("local env missing shifted postgres port:\n%s", envData)
+	}
+}
+
+func TestGenerate_StateManifest(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// A compose directory left by a service generated before the manifest existed
+	legacy := filepath.Join(tmpDir, "legacy", "docker-compose.yaml")
+	if err := os.MkdirAll(filepath.Dir(legacy), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(legacy, []byte("services: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewComposeGenerator(tmpDir, config.DefaultEnvironment(), config.DefaultRuntime())
+	api := newTestService("api", 8080, nil)
+	if _, err := g.Generate([]*service.Service{api}, api.Dependencies.Infrastructure); err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+
+	state, err := config.LoadState(tmpDir)
+	if err != nil || state == nil {
+		t.Fatalf("LoadState() = %v, %v; want the manifest grund up wrote", state, err)
+	}
+	if !state.Has("api") || !state.Has("legacy") {
+		t.Fatalf("manifest services = %v, want api and the adopted legacy service", state.Services)
+	}
+	if state.Services["api"].ConfigHash != "" || state.Services["api"].GeneratedAt.IsZero() {
+		t.Errorf("api state = %+v, want a generation time and no hash until it starts", state.Services["api"])
+	}
+	// Infrastructure ports are recorded with the services', for other environments to avoid
+	if state.HostPorts["api"] != 8080 || state.HostPorts["postgres"] != 5432 || state.HostPorts["localstack"] != 4566 {
+		t.Errorf("host ports = %v, want api, postgres and localstack", state.HostPorts)
+	}
+
+	// Hashes are recorded once the service starts
+	if err := g.RecordStarted([]service.ServiceName{"api"}); err != nil {
+		t.Fatalf("RecordStarted() error: %v", err)
+	}
+	state, _ = config.LoadState(tmpDir)
+	data, _ := os.ReadFile(filepath.Join(tmpDir, "api", "docker-compose.yaml"))
+	if state.Services["api"].ConfigHash != config.HashConfig(data) {
+		t.Errorf("api state = %+v, want the compose file's hash", state.Services["api"])
+	}
+
+	// Once a manifest exists, directories it doesn't list are ignored
+	stale := filepath.Join(tmpDir, "renamed", "docker-compose.yaml")
+	if err := os.MkdirAll(filepath.Dir(stale), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stale, []byte("services: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fileSet, err := g.Generate([]*service.Service{api}, api.Dependencies.Infrastructure)
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if _, ok := fileSet.ServicePaths["renamed"]; ok {
+		t.Error("a compose directory missing from the manifest should be ignored")
+	}
+	if _, ok := fileSet.ServicePaths["legacy"]; !ok {
+		t.Error("services in the manifest should still be included")
+	}
+}
+
+func TestGenerate_Changes(t *testing.T) {
+	tmpDir := t.TempDir()
+	contextDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(contextDir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewComposeGenerator(tmpDir, config.DefaultEnvironment(), config.DefaultRuntime())
+	api := newTestService("api", 8080, nil)
+	api.Build.Context = contextDir
+	generate := func() ports.ServiceChange {
+		t.Helper()
+		fileSet, err := g.Generate([]*service.Service{api}, api.Dependencies.Infrastructure)
+		if err != nil {
+			t.Fatalf("Generate() error: %v", err)
+		}
+		return fileSet.Changes["api"]
+	}
+
+	if got := generate(); !got.Config || !got.Build {
+		t.Errorf("first run changes = %+v, want config and build", got)
+	}
+	// Until the service starts, the next run still sees the changes
+	if got := generate(); !got.Config || !got.Build {
+		t.Errorf("changes before start = %+v, want config and build", got)
+	}
+
+	if err := g.RecordStarted([]service.ServiceName{"api"}); err != nil {
+		t.Fatalf("RecordStarted() error: %v", err)
+	}
+	if got := generate(); !got.UpToDate() {
+		t.Errorf("changes after start = %+v, want up to date", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(contextDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := generate(); got.Config || !got.Build {
+		t.Errorf("changes after a source edit = %+v, want build only", got)
+	}
+
+	api.Environment.Variables = map[string]string{"LOG_LEVEL": "debug"}
+	if got := generate(); !got.Config {
+		t.Errorf("changes after an env edit = %+v, want config", got)
+	}
+}
+
+func TestGenerateServices(t *testing.T) {
+	tmpDir := t.TempDir()
+	g := NewComposeGenerator(tmpDir, config.DefaultEnvironment(), config.DefaultRuntime())
+	api := newTestService("api", 8080, map[string]string{"WORKER_URL": "http://${worker.host}:${worker.port}"})
+	worker := newTestService("worker", 8081, nil)
+
+	if env, err := g.GeneratedEnv("api"); err != nil || env != nil {
+		t.Fatalf("GeneratedEnv() before generating = %v, %v; want nil", env, err)
+	}
+
+	infra := infrastructure.Aggregate(api.Dependencies.Infrastructure, worker.Dependencies.Infrastructure)
+	fileSet, err := g.GenerateServices([]*service.Service{api, worker}, infra, []service.ServiceName{"api"})
+	if err != nil {
+		t.Fatalf("GenerateServices() error: %v", err)
+	}
+
+	if _, ok := fileSet.ServicePaths["worker"]; ok {
+		t.Error("only the named services should be generated")
+	}
+	if fileSet.InfrastructurePath != "" {
+		t.Errorf("infrastructure should be left alone, got %s", fileSet.InfrastructurePath)
+	}
+	if _, ok := fileSet.Changes["api"]; !ok {
+		t.Errorf("changes = %v, want api", fileSet.Changes)
+	}
+
+	env, err := g.GeneratedEnv("api")
+	if err != nil {
+		t.Fatalf("GeneratedEnv() error: %v", err)
+	}
+	if env["WORKER_URL"] != "http://worker:8081" {
+		t.Errorf("WORKER_URL = %q, want it resolved against worker", env["WORKER_URL"])
+	}
+}
+
+// grund restart runs in a fresh process that knows neither the --local services nor
+// the other services grund up allocated ports around; both come from the manifest
+func TestGenerateServices_KeepsLayout(t *testing.T) {
+	tmpDir := t.TempDir()
+	api := newTestService("api", 8080, nil)
+	worker := newTestService("worker", 8080, nil)
+
+	up := NewComposeGenerator(tmpDir, config.DefaultEnvironment(), config.DefaultRuntime())
+	up.SetLocalServices([]service.ServiceName{"api"})
+	infra := infrastructure.Aggregate(api.Dependencies.Infrastructure, worker.Dependencies.Infrastructure)
+	if _, err := up.Generate([]*service.Service{api, worker}, infra); err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+
+	restart := NewComposeGenerator(tmpDir, config.DefaultEnvironment(), config.DefaultRuntime())
+	fileSet, err := restart.GenerateServices([]*service.Service{worker}, worker.Dependencies.Infrastructure, []service.ServiceName{"worker"})
+	if err != nil {
+		t.Fatalf("GenerateServices() error: %v", err)
+	}
+
+	svc := readComposeFile(t, fileSet.ServicePaths["worker"]).Services["worker"]
+	if len(svc.Ports) != 1 || svc.Ports[0] != "8081:8080" {
+		t.Errorf("worker ports = %v, want the [8081:8080] grund up published", svc.Ports)
+	}
+	if len(svc.ExtraHosts) != 1 {
+		t.Errorf("worker extra_hosts = %v, want the host gateway for the host-run api", svc.ExtraHosts)
+	}
+
+	state, err := config.LoadState(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.IsLocal("api") || state.HostPorts["worker"] != 8081 {
+		t.Errorf("state = %+v, want api local and worker on 8081", state)
+	}
+}
+
+func TestResolveEnv(t *testing.T) {
+	tmpDir := t.TempDir()
+	api := newTestService("api", 8080, map[string]string{
+		"DATABASE_URL": "postgres://${postgres.host}:${postgres.port}/${self.postgres.database}",
+		"QUEUE_URL":    "${sqs.orders.url}",
+		"WORKER_URL":   "http://${worker.host}:${worker.port}",
+	})
+	worker := newTestService("worker", 8080, nil)
+
+	g := NewComposeGenerator(tmpDir, &config.Environment{Name: "review", PortOffset: 1000}, config.DefaultRuntime())
+	services := []*service.Service{api, worker}
+	infra := infrastructure.Aggregate(api.Dependencies.Infrastructure, worker.Dependencies.Infrastructure)
+
+	tests := []struct {
+		name string
+		host bool
+		want map[string]string
+	}{
+		{"container", false, map[string]string{
+			"DATABASE_URL": "postgres://postgres:5432/api_db",
+			"QUEUE_URL":    "http://localstack:4566/000000000000/orders",
+			"WORKER_URL":   "http://worker:8080",
+			"AWS_ENDPOINT": "http://localstack:4566",
+		}},
+		{"host", true, map[string]string{
+			"DATABASE_URL": "postgres://localhost:6432/api_db",
+			"QUEUE_URL":    "http://localhost:5566/000000000000/orders",
+			"WORKER_URL":   "http://localhost:9081", // api was allocated 8080 first, then shifted
+			"AWS_ENDPOINT": "http://localhost:5566",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := g.ResolveEnv(services, infra, "api", tt.host)
+			if err != nil {
+				t.Fatalf("ResolveEnv() error: %v", err)
+			}
+			for k, want := range tt.want {
+				if env[k] != want {
+					t.Errorf("%s = %q, want %q", k, env[k], want)
+				}
+			}
+		})
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("ResolveEnv() wrote files: %v", entries)
+	}
+
+	if _, err := g.ResolveEnv(services, infra, "missing", false); err == nil {
+		t.Error("ResolveEnv() should fail for a service not in the set")
+	}
+}
+
+// grund env <service> --host resolves only a service and its dependencies; the ports
+// of its peers must still be those grund up published for the whole stack
+func TestResolveEnv_UsesRecordedHostPorts(t *testing.T) {
+	tmpDir := t.TempDir()
+	a := newTestService("a", 8080, nil)
+	b := newTestService("b", 8080, nil)
+	c := newTestService("c", 9000, map[string]string{"B_URL": "http://${b.host}:${b.port}"})
+	infra := infrastructure.Aggregate(a.Dependencies.Infrastructure, b.Dependencies.Infrastructure, c.Dependencies.Infrastructure)
+
+	if _, err := NewComposeGenerator(tmpDir, config.DefaultEnvironment(), config.DefaultRuntime()).Generate([]*service.Service{a, b, c}, infra); err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+
+	g := NewComposeGenerator(tmpDir, config.DefaultEnvironment(), config.DefaultRuntime())
+	env, err := g.ResolveEnv([]*service.Service{c, b}, infra, "c", true)
+	if err != nil {
+		t.Fatalf("ResolveEnv() error: %v", err)
+	}
+	if env["B_URL"] != "http://localhost:8081" {
+		t.Errorf("B_URL = %q, want b's published http://localhost:8081, not a's 8080", env["B_URL"])
+	}
+}
+
+func TestHostPorts(t *testing.T) {
+	api := newTestService("api", 8080, nil)
+	worker := newTestService("worker", 8080, nil)
+	g := NewComposeGenerator(t.TempDir(), &config.Environment{Name: "review", PortOffset: 1000}, config.DefaultRuntime())
+	infra := infrastructure.Aggregate(api.Dependencies.Infrastructure, worker.Dependencies.Infrastructure)
+
+	got := g.HostPorts([]*service.Service{api, worker}, infra)
+	want := []ports.HostPortAssignment{
+		{Owner: "postgres", HostPort: 6432, ContainerPort: 5432},
+		{Owner: "localstack", HostPort: 5566, ContainerPort: 4566},
+		{Owner: "api", HostPort: 9080, ContainerPort: 8080},
+		{Owner: "worker", HostPort: 9081, ContainerPort: 8080, ConflictsWith: "api"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("HostPorts() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("HostPorts()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestGenerate_PodmanRuntime(t *testing.T) {
+	tmpDir := t.TempDir()
+	api := newTestService("api", 8080, nil)
+	worker := newTestService("worker", 8081, map[string]string{
+		"API_URL": "http://${api.host}:${api.port}",
+	})
+
+	podman := &config.ContainerRuntime{
+		Name:        config.RuntimePodman,
+		HostGateway: "host.containers.internal",
+		Socket:      "/run/user/1000/podman/podman.sock",
+	}
+	g := NewComposeGenerator(tmpDir, config.DefaultEnvironment(), podman)
+	g.SetLocalServices([]service.ServiceName{"api"})
+
+	services := []*service.Service{api, worker}
+	infra := infrastructure.Aggregate(api.Dependencies.Infrastructure, worker.Dependencies.Infrastructure)
+	fileSet, err := g.Generate(services, infra)
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+
+	// Podman resolves its gateway name itself, so no extra_hosts entry is needed
+	workerSvc := readComposeFile(t, fileSet.ServicePaths["worker"]).Services["worker"]
+	if got := workerSvc.Environment["API_URL"]; got != "http://host.containers.internal:8080" {
+		t.Errorf("worker API_URL = %q, want the podman host gateway", got)
+	}
+	if len(workerSvc.ExtraHosts) != 0 {
+		t.Errorf("worker extra_hosts = %v, want none with podman", workerSvc.ExtraHosts)
+	}
+
+	// LocalStack gets the rootless podman socket
+	localstack := readComposeFile(t, fileSet.InfrastructurePath).Services["localstack"]
+	if len(localstack.Volumes) == 0 || localstack.Volumes[0] != "/run/user/1000/podman/podman.sock:/var/run/docker.sock" {
+		t.Errorf("localstack volumes = %v, want the podman socket mounted", localstack.Volumes)
+	}
+	if localstack.Environment["DOCKER_HOST"] != "unix:///var/run/docker.sock" {
+		t.Errorf("localstack DOCKER_HOST = %q", localstack.Environment["DOCKER_HOST"])
+	}
+}
+
+func TestGenerate_RunConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	api := newTestService("api", 8080, nil)
+	api.Run = &service.RunConfig{
+		Command:   "go run ./cmd/api",
+		HotReload: true,
+		Source:    "/home/dev/api",
+	}
+	worker := newTestService("worker", 8081, nil)
+	worker.Run = &service.RunConfig{Command: "./worker --verbose"}
+
+	services := []*service.Service{api, worker}
+	infra := infrastructure.Aggregate(api.Dependencies.Infrastructure, worker.Dependencies.Infrastructure)
+	fileSet, err := NewComposeGenerator(tmpDir, config.DefaultEnvironment(), config.DefaultRuntime()).Generate(services, infra)
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+
+	apiSvc := readComposeFile(t, fileSet.ServicePaths["api"]).Services["api"]
+	if got := strings.Join(apiSvc.Command, " "); got != "sh -c go run ./cmd/api" {
+		t.Errorf("api command = %v, want run.command under sh -c", apiSvc.Command)
+	}
+	if len(apiSvc.Volumes) != 1 || apiSvc.Volumes[0] != "/home/dev/api:/app" {
+		t.Errorf("api volumes = %v, want [/home/dev/api:/app]", apiSvc.Volumes)
+	}
+	if apiSvc.WorkingDir != "/app" {
+		t.Errorf("api working_dir = %q, want /app", apiSvc.WorkingDir)
+	}
+
+	// Without hot_reload the image's files are used as built
+	workerSvc := readComposeFile(t, fileSet.ServicePaths["worker"]).Services["worker"]
+	if got := strings.Join(workerSvc.Command, " "); got != "sh -c ./worker --verbose" {
+		t.Errorf("worker command = %v, want run.command under sh -c", workerSvc.Command)
+	}
+	if len(workerSvc.Volumes) != 0 || workerSvc.WorkingDir != "" {
+		t.Errorf("worker should not mount source, got volumes=%v working_dir=%q", workerSvc.Volumes, workerSvc.WorkingDir)
+	}
+}
+
+func TestQuoteEnvValue(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", "plain"},
+		{"", `""`},
+		{"has space", `"has space"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{"line1\nline2", `"line1\nline2"`},
+		{"$HOME", `"\$HOME"`},
+	}
+
+	for _, tt := range tests {
+		if got := QuoteEnvValue(tt.in); got != tt.want {
+			t.Errorf("QuoteEnvValue(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGenerate_HostPortOutOfRange(t *testing.T) {
+	g := NewComposeGenerator(t.TempDir(), &config.Environment{Name: "review", PortOffset: 30000}, config.DefaultRuntime())
+	api := newTestService("api", 40000, nil)
+
+	_, err := g.Generate([]*service.Service{api}, api.Dependencies.Infrastructure)
+	if err == nil || !strings.Contains(err.Error(), "70000") {
+		t.Errorf("Generate() error = %v, want host port 70000 rejected", err)
+	}
+}
+
+func TestGenerate_EscapesDollarInEnvironment(t *testing.T) {
+	tmpDir := t.TempDir()
+	g := NewComposeGenerator(tmpDir, config.DefaultEnvironment(), config.DefaultRuntime())
+	api := newTestService("api", 8080, map[string]string{"TEMPLATE": "hello $${name}"})
+	api.Environment.Variables = map[string]string{"PASSWORD": "pa$word"}
+
+	fileSet, err := g.Generate([]*service.Service{api}, api.Dependencies.Infrastructure)
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+
+	// Compose interpolates $ itself, so the written file must carry $$
+	env := readComposeFile(t, fileSet.ServicePaths["api"]).Services["api"].Environment
+	if env["TEMPLATE"] != "hello $${name}" || env["PASSWORD"] != "pa$$word" {
+		t.Errorf("written environment = TEMPLATE=%q PASSWORD=%q, want $ escaped as $$", env["TEMPLATE"], env["PASSWORD"])
+	}
+
+	resolved, err := g.GeneratedEnv("api")
+	if err != nil {
+		t.Fatalf("GeneratedEnv() error: %v", err)
+	}
+	if resolved["TEMPLATE"] != "hello ${name}" || resolved["PASSWORD"] != "pa$word" {
+		t.Errorf("GeneratedEnv() = TEMPLATE=%q PASSWORD=%q, want the values containers see", resolved["TEMPLATE"], resolved["PASSWORD"])
+	}
+}
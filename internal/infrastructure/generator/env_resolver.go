@@ -2,7 +2,8 @@ package generator
 
 import (
 	"fmt"
-	"regexp"
+	"os"
+	"sort"
 	"strings"
 
 	"github.com/vivekkundariya/grund/internal/application/ports"
@@ -27,41 +28,127 @@ func NewEnvironmentResolver() ports.EnvironmentResolver {
 //   - ${s3.<bucket-name>.name}, ${s3.<bucket-name>.url}
 //   - ${<service-name>.host}, ${<service-name>.port}
 //   - ${self.host}, ${self.port}, ${self.postgres.database}
+//   - ${self.postgres.username}, ${self.postgres.password}
 //   - ${tunnel.<name>.url}, ${tunnel.<name>.host}
+//   - ${env.<NAME>} from the service's env map, then the host environment
+//   - ${secret.<NAME>} for a secret declared under secrets
+//
+// ${path:-default} falls back to default when path can't be resolved or is empty,
+// and placeholders may be nested, e.g. ${redis.host:-${env.REDIS_HOST}}.
+// $${ is a literal ${. Every unresolved placeholder is reported in one
+// *ports.UnresolvedReferencesError.
 func (r *EnvironmentResolverImpl) Resolve(envRefs map[string]string, context ports.EnvironmentContext) (map[string]string, error) {
 	resolved := make(map[string]string)
+	unresolvedErr := &ports.UnresolvedReferencesError{}
 
-	for key, value := range envRefs {
-		resolvedValue, err := r.resolveValue(value, context)
-		if err != nil {
-			return nil, fmt.Errorf("failed to resolve %s: %w", key, err)
+	keys := make([]string, 0, len(envRefs))
+	for key := range envRefs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var unresolved []ports.UnresolvedReference
+		resolved[key] = r.resolveValue(envRefs[key], context, &unresolved)
+		for _, ref := range unresolved {
+			ref.Key = key
+			unresolvedErr.References = append(unresolvedErr.References, ref)
 		}
-		resolved[key] = resolvedValue
 	}
 
+	if len(unresolvedErr.References) > 0 {
+		return nil, unresolvedErr
+	}
 	return resolved, nil
 }
 
-func (r *EnvironmentResolverImpl) resolveValue(value string, context ports.EnvironmentContext) (string, error) {
-	result := value
-
-	// Find all ${...} placeholders
-	placeholderRegex := regexp.MustCompile(`\$\{([^}]+)\}`)
-	matches := placeholderRegex.FindAllStringSubmatch(value, -1)
+// resolveValue expands the placeholders in value, recording the ones that can't be resolved
+func (r *EnvironmentResolverImpl) resolveValue(value string, context ports.EnvironmentContext, unresolved *[]ports.UnresolvedReference) string {
+	var b strings.Builder
+
+	for i := 0; i < len(value); {
+		switch {
+		case strings.HasPrefix(value[i:], "$${"):
+			b.WriteString("${")
+			i += 3
+		case strings.HasPrefix(value[i:], "${"):
+			end := placeholderEnd(value, i)
+			if end < 0 {
+				*unresolved = append(*unresolved, ports.UnresolvedReference{Placeholder: value[i:], Reason: "missing closing }"})
+				return b.String()
+			}
+			b.WriteString(r.resolveExpression(value[i:end+1], context, unresolved))
+			i = end + 1
+		default:
+			b.WriteByte(value[i])
+			i++
+		}
+	}
 
-	for _, match := range matches {
-		placeholder := match[0] // Full match like ${postgres.host}
-		path := match[1]        // Inner part like postgres.host
+	return b.String()
+}
 
-		resolved, err := r.resolvePlaceholder(path, context)
-		if err != nil {
-			return "", fmt.Errorf("cannot resolve %s: %w", placeholder, err)
+// resolveExpression resolves a single ${path} or ${path:-default} placeholder
+func (r *EnvironmentResolverImpl) resolveExpression(placeholder string, context ports.EnvironmentContext, unresolved *[]ports.UnresolvedReference) string {
+	inner := placeholder[2 : len(placeholder)-1]
+	path, def, hasDefault := splitDefault(inner)
+
+	// Nested placeholders in the path are resolved first, e.g. ${sqs.${env.QUEUE}.url}
+	if strings.Contains(path, "${") {
+		var nested []ports.UnresolvedReference
+		path = r.resolveValue(path, context, &nested)
+		if len(nested) > 0 && !hasDefault {
+			*unresolved = append(*unresolved, nested...)
+			return ""
 		}
+	}
 
-		result = strings.Replace(result, placeholder, resolved, 1)
+	value, err := r.resolvePlaceholder(path, context)
+	if err == nil && value != "" {
+		return value
+	}
+	if hasDefault {
+		return r.resolveValue(def, context, unresolved)
+	}
+	if err != nil {
+		*unresolved = append(*unresolved, ports.UnresolvedReference{Placeholder: placeholder, Reason: err.Error()})
 	}
+	return value
+}
 
-	return result, nil
+// placeholderEnd returns the index of the } closing the placeholder that starts at start, or -1
+func placeholderEnd(value string, start int) int {
+	depth := 0
+	for i := start; i < len(value); i++ {
+		switch {
+		case strings.HasPrefix(value[i:], "${"):
+			depth++
+			i++
+		case value[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitDefault splits path:-default at the first :- outside nested placeholders
+func splitDefault(inner string) (path, def string, ok bool) {
+	depth := 0
+	for i := 0; i < len(inner); i++ {
+		switch {
+		case strings.HasPrefix(inner[i:], "${"):
+			depth++
+			i++
+		case inner[i] == '}':
+			depth--
+		case depth == 0 && strings.HasPrefix(inner[i:], ":-"):
+			return inner[:i], inner[i+2:], true
+		}
+	}
+	return inner, "", false
 }
 
 func (r *EnvironmentResolverImpl) resolvePlaceholder(path string, context ports.EnvironmentContext) (string, error) {
@@ -92,6 +179,10 @@ func (r *EnvironmentResolverImpl) resolvePlaceholder(path string, context ports.
 		return r.resolveSelf(parts[1:], context)
 	case "tunnel":
 		return r.resolveTunnel(parts[1:], context)
+	case "env":
+		return r.resolveEnv(strings.Join(parts[1:], "."), context)
+	case "secret":
+		return r.resolveSecret(strings.Join(parts[1:], "."), context)
 	default:
 		// Try to resolve as a service reference
 		return r.resolveService(prefix, parts[1:], context)
@@ -291,6 +382,7 @@ func (r *EnvironmentResolverImpl) resolveSelf(parts []string, context ports.Envi
 		return fmt.Sprintf("%d", context.Self.Port), nil
 	case "postgres":
 		// self.postgres.database -> the database name for this service
+		// self.postgres.username/password -> this service's own role
 		if len(parts) < 2 {
 			return "", fmt.Errorf("missing property for self.postgres")
 		}
@@ -347,3 +439,22 @@ func (r *EnvironmentResolverImpl) resolveTunnel(parts []string, context ports.En
 		return "", fmt.Errorf("unknown tunnel property %s", property)
 	}
 }
+
+// resolveEnv reads a static env var, falling back to the host environment
+func (r *EnvironmentResolverImpl) resolveEnv(name string, context ports.EnvironmentContext) (string, error) {
+	if value, ok := context.Env[name]; ok {
+		return value, nil
+	}
+	if value, ok := os.LookupEnv(name); ok {
+		return value, nil
+	}
+	return "", fmt.Errorf("%s is not set in env or the host environment", name)
+}
+
+// resolveSecret reads a resolved secret
+func (r *EnvironmentResolverImpl) resolveSecret(name string, context ports.EnvironmentContext) (string, error) {
+	if value, ok := context.Secrets[name]; ok {
+		return value, nil
+	}
+	return "", fmt.Errorf("secret %s is not set (declare it under secrets and set it in ~/.grund/secrets.env)", name)
+}
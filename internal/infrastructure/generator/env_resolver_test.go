@@ -1,6 +1,8 @@
 package generator
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/vivekkundariya/grund/internal/application/ports"
@@ -378,3 +380,136 @@ func TestResolveTunnelPlaceholders(t *testing.T) {
 		t.Errorf("expected abc-xyz.trycloudflare.com, got %s", resolved["PUBLIC_S3_HOST"])
 	}
 }
+
+func TestEnvironmentResolver_Defaults(t *testing.T) {
+	resolver := NewEnvironmentResolver()
+
+	ctx := ports.NewDefaultEnvironmentContext()
+	ctx.Infrastructure["redis"] = ports.InfrastructureContext{Host: "redis", Port: 6379}
+	ctx.Env = map[string]string{"EMPTY": "", "CACHE_HOST": "cache"}
+
+	envRefs := map[string]string{
+		"CONFIGURED":  "${redis.host:-localhost}",
+		"MISSING":     "${postgres.host:-localhost}:${postgres.port:-5432}",
+		"EMPTY_VALUE": "${env.EMPTY:-fallback}",
+		"NO_DEFAULT":  "${env.EMPTY:-}",
+		"NESTED":      "${mongodb.host:-${env.CACHE_HOST}}",
+		"WITH_COLON":  "${postgres.host:-http://localhost:8080}",
+	}
+
+	resolved, err := resolver.Resolve(envRefs, ctx)
+	if err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+
+	want := map[string]string{
+		"CONFIGURED":  "redis",
+		"MISSING":     "localhost:5432",
+		"EMPTY_VALUE": "fallback",
+		"NO_DEFAULT":  "",
+		"NESTED":      "cache",
+		"WITH_COLON":  "http://localhost:8080",
+	}
+	for k, v := range want {
+		if resolved[k] != v {
+			t.Errorf("%s = %q, want %q", k, resolved[k], v)
+		}
+	}
+}
+
+func TestEnvironmentResolver_Escaping(t *testing.T) {
+	resolver := NewEnvironmentResolver()
+
+	ctx := ports.NewDefaultEnvironmentContext()
+	ctx.Infrastructure["redis"] = ports.InfrastructureContext{Host: "redis", Port: 6379}
+
+	envRefs := map[string]string{
+		"TEMPLATE": "$${HOME}/cache on ${redis.host}",
+		"DOLLARS":  "costs $5 or $$5",
+	}
+
+	resolved, err := resolver.Resolve(envRefs, ctx)
+	if err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+
+	if resolved["TEMPLATE"] != "${HOME}/cache on redis" {
+		t.Errorf("TEMPLATE = %q, want literal ${HOME}", resolved["TEMPLATE"])
+	}
+	if resolved["DOLLARS"] != "costs $5 or $$5" {
+		t.Errorf("DOLLARS = %q, want unchanged", resolved["DOLLARS"])
+	}
+}
+
+func TestEnvironmentResolver_EnvAndSecrets(t *testing.T) {
+	t.Setenv("GRUND_TEST_HOST_VAR", "from-host")
+	t.Setenv("APP_ENV", "from-host")
+	resolver := NewEnvironmentResolver()
+
+	ctx := ports.NewDefaultEnvironmentContext()
+	ctx.Env = map[string]string{"APP_ENV": "development"}
+	ctx.Secrets = map[string]string{"API_KEY": "s3cr3t"}
+	ctx.SQS["orders-development"] = ports.QueueContext{URL: "http://localstack:4566/000000000000/orders-development"}
+
+	envRefs := map[string]string{
+		"APP":       "${env.APP_ENV}",
+		"HOST":      "${env.GRUND_TEST_HOST_VAR}",
+		"API_URL":   "https://api.example.com?key=${secret.API_KEY}",
+		"QUEUE_URL": "${sqs.orders-${env.APP_ENV}.url}",
+	}
+
+	resolved, err := resolver.Resolve(envRefs, ctx)
+	if err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+
+	want := map[string]string{
+		"APP":       "development", // the service's env map wins over the host
+		"HOST":      "from-host",
+		"API_URL":   "https://api.example.com?key=s3cr3t",
+		"QUEUE_URL": "http://localstack:4566/000000000000/orders-development",
+	}
+	for k, v := range want {
+		if resolved[k] != v {
+			t.Errorf("%s = %q, want %q", k, resolved[k], v)
+		}
+	}
+}
+
+func TestEnvironmentResolver_ReportsAllUnresolved(t *testing.T) {
+	resolver := NewEnvironmentResolver()
+
+	envRefs := map[string]string{
+		"DATABASE_URL": "postgres://${postgres.host}:${postgres.port}/app",
+		"API_KEY":      "${secret.API_KEY}",
+		"BROKEN":       "${redis.host",
+		"OK":           "${redis.host:-localhost}",
+	}
+
+	_, err := resolver.Resolve(envRefs, ports.NewDefaultEnvironmentContext())
+
+	var unresolved *ports.UnresolvedReferencesError
+	if !errors.As(err, &unresolved) {
+		t.Fatalf("Resolve() error = %v, want *ports.UnresolvedReferencesError", err)
+	}
+	if len(unresolved.References) != 4 {
+		t.Fatalf("got %d unresolved references, want 4: %v", len(unresolved.References), err)
+	}
+
+	unresolved.Locate("/svc/grund.yaml", map[string]int{"DATABASE_URL": 12, "API_KEY": 14, "BROKEN": 13})
+	msg := err.Error()
+	for _, want := range []string{
+		"4 unresolved env_refs placeholders:",
+		"/svc/grund.yaml:12: DATABASE_URL: ${postgres.host}: postgres not configured",
+		"/svc/grund.yaml:12: DATABASE_URL: ${postgres.port}: postgres not configured",
+		"/svc/grund.yaml:13: BROKEN: ${redis.host: missing closing }",
+		"/svc/grund.yaml:14: API_KEY: ${secret.API_KEY}: secret API_KEY is not set",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error missing %q:\n%s", want, msg)
+		}
+	}
+	if strings.Index(msg, "BROKEN") > strings.Index(msg, "API_KEY") {
+		t.Errorf("references should be ordered by line:\n%s", msg)
+	}
+}
@@ -1,100 +1,79 @@
 package generator
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
+	"sort"
 
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/config"
 	"github.com/vivekkundariya/grund/internal/domain/service"
-	"github.com/vivekkundariya/grund/internal/ui"
+	"github.com/vivekkundariya/grund/internal/infrastructure/secrets"
 )
 
-// SecretsLoader loads secrets from ~/.grund/secrets.env and shell environment
+// SecretsLoader looks up secrets in the backends configured in ~/.grund/config.yaml
+// Backends are tried in order; without configuration that is ~/.grund/secrets.env, then the shell environment.
 type SecretsLoader struct {
-	secretsFilePath string
-	fileSecrets     map[string]string
-	loaded          bool
+	backends []secrets.Backend
+	loaded   bool
 }
 
-// NewSecretsLoader creates a new secrets loader
+// NewSecretsLoader creates a secrets loader for the configured backends
 func NewSecretsLoader() *SecretsLoader {
-	homeDir, _ := os.UserHomeDir()
-	return &SecretsLoader{
-		secretsFilePath: filepath.Join(homeDir, ".grund", "secrets.env"),
-		fileSecrets:     make(map[string]string),
-	}
+	return &SecretsLoader{}
+}
+
+// NewSecretsLoaderWithBackends creates a secrets loader for the given backends
+func NewSecretsLoaderWithBackends(backends ...secrets.Backend) *SecretsLoader {
+	return &SecretsLoader{backends: backends, loaded: true}
 }
 
-// Load loads secrets from the secrets file
+// Load creates the configured backends
+// Backends read their sources lazily, on the first lookup.
 func (l *SecretsLoader) Load() error {
 	if l.loaded {
 		return nil
 	}
 
-	l.fileSecrets = make(map[string]string)
-
-	file, err := os.Open(l.secretsFilePath)
+	cfg, err := config.LoadGlobalConfig()
 	if err != nil {
-		if os.IsNotExist(err) {
-			ui.Debug("No secrets file found at %s, using shell environment only", l.secretsFilePath)
-			l.loaded = true
-			return nil
-		}
-		return fmt.Errorf("failed to open secrets file: %w", err)
+		return err
 	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
-	for scanner.Scan() {
-		lineNum++
-		line := strings.TrimSpace(scanner.Text())
-
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-
-		// Parse KEY=VALUE
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			ui.Warnf("Invalid line %d in secrets file: %s", lineNum, line)
-			continue
-		}
-
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-
-		// Remove surrounding quotes if present
-		value = strings.Trim(value, `"'`)
-
-		l.fileSecrets[key] = value
+	configs, err := cfg.GetSecretBackends()
+	if err != nil {
+		return err
 	}
-
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("failed to read secrets file: %w", err)
+	l.backends, err = secrets.NewBackends(configs, secrets.NewExecRunner())
+	if err != nil {
+		return err
 	}
 
-	ui.Debug("Loaded %d secrets from %s", len(l.fileSecrets), l.secretsFilePath)
 	l.loaded = true
 	return nil
 }
 
-// GetSecretValue gets a secret value with priority: secrets.env > shell environment
-func (l *SecretsLoader) GetSecretValue(name string) (string, bool) {
-	// Priority 1: secrets.env file
-	if value, ok := l.fileSecrets[name]; ok && value != "" {
-		return value, true
+// Lookup returns a service's secret value and the name of the backend that supplied it
+func (l *SecretsLoader) Lookup(serviceName, name string) (value, source string, found bool, err error) {
+	if err := l.Load(); err != nil {
+		return "", "", false, err
 	}
 
-	// Priority 2: shell environment
-	if value := os.Getenv(name); value != "" {
-		return value, true
+	for _, backend := range l.backends {
+		value, found, err := backend.Lookup(context.Background(), serviceName, name)
+		if err != nil {
+			return "", "", false, fmt.Errorf("secret backend %s: %w", backend.Name(), err)
+		}
+		if found {
+			return value, backend.Name(), true, nil
+		}
 	}
+	return "", "", false, nil
+}
 
-	return "", false
+// GetSecretValue gets a service's secret value from the first backend that has it
+func (l *SecretsLoader) GetSecretValue(serviceName, name string) (string, bool) {
+	value, _, found, err := l.Lookup(serviceName, name)
+	return value, found && err == nil
 }
 
 // SecretStatus represents the status of a secret
@@ -103,52 +82,65 @@ type SecretStatus struct {
 	Description string
 	Required    bool
 	Found       bool
-	Source      string // "file", "env", or ""
+	Source      string   // name of the backend that has it, or ""
+	MissingFor  []string // services the secret is missing for
 }
 
-// ValidateSecrets validates that all required secrets are present
-// Returns list of missing required secrets
+// ValidateSecrets looks up every secret the services declare
+// A secret is found only when it is found for each service declaring it, since
+// per-service files can supply different values.
 func (l *SecretsLoader) ValidateSecrets(services []*service.Service) ([]SecretStatus, error) {
 	if err := l.Load(); err != nil {
 		return nil, err
 	}
 
-	// Collect all unique secrets from all services
-	secretsMap := make(map[string]service.SecretRequirement)
+	statuses := make(map[string]*SecretStatus)
+	var order []string
 	for _, svc := range services {
-		for name, secret := range svc.Environment.Secrets {
+		names := make([]string, 0, len(svc.Environment.Secrets))
+		for name := range svc.Environment.Secrets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			secret := svc.Environment.Secrets[name]
+			status, ok := statuses[name]
+			if !ok {
+				status = &SecretStatus{Name: name, Description: secret.Description, Found: true}
+				statuses[name] = status
+				order = append(order, name)
+			}
 			// If same secret defined in multiple services, use the one that's required
-			if existing, ok := secretsMap[name]; ok {
-				if secret.Required && !existing.Required {
-					secretsMap[name] = secret
+			if secret.Required && !status.Required {
+				status.Required = true
+				if secret.Description != "" {
+					status.Description = secret.Description
 				}
-			} else {
-				secretsMap[name] = secret
 			}
-		}
-	}
 
-	var statuses []SecretStatus
-	for name, secret := range secretsMap {
-		status := SecretStatus{
-			Name:        name,
-			Description: secret.Description,
-			Required:    secret.Required,
+			_, source, found, err := l.Lookup(svc.Name, name)
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				status.Found = false
+				status.MissingFor = append(status.MissingFor, svc.Name)
+			} else if status.Source == "" {
+				status.Source = source
+			}
 		}
+	}
 
-		// Check if secret is available
-		if _, ok := l.fileSecrets[name]; ok {
-			status.Found = true
-			status.Source = "file"
-		} else if os.Getenv(name) != "" {
-			status.Found = true
-			status.Source = "env"
+	result := make([]SecretStatus, 0, len(order))
+	for _, name := range order {
+		status := *statuses[name]
+		if !status.Found {
+			status.Source = ""
 		}
-
-		statuses = append(statuses, status)
+		result = append(result, status)
 	}
-
-	return statuses, nil
+	return result, nil
 }
 
 // GetMissingRequired returns only the missing required secrets
@@ -167,27 +159,56 @@ func (l *SecretsLoader) GetMissingRequired(services []*service.Service) ([]Secre
 	return missing, nil
 }
 
-// ResolveSecrets resolves all secrets for a service and returns a map of key=value
-func (l *SecretsLoader) ResolveSecrets(svc *service.Service) (map[string]string, error) {
-	if err := l.Load(); err != nil {
+// MissingSecrets implements ports.SecretChecker
+func (l *SecretsLoader) MissingSecrets(services []*service.Service) ([]ports.MissingSecret, error) {
+	statuses, err := l.GetMissingRequired(services)
+	if err != nil {
 		return nil, err
 	}
+	missing := make([]ports.MissingSecret, len(statuses))
+	for i, s := range statuses {
+		missing[i] = ports.MissingSecret{Name: s.Name, Services: s.MissingFor}
+	}
+	return missing, nil
+}
 
+// ResolveSecrets resolves all secrets for a service and returns a map of key=value
+func (l *SecretsLoader) ResolveSecrets(svc *service.Service) (map[string]string, error) {
 	resolved := make(map[string]string)
 	for name := range svc.Environment.Secrets {
-		if value, ok := l.GetSecretValue(name); ok {
+		value, _, found, err := l.Lookup(svc.Name, name)
+		if err != nil {
+			return nil, err
+		}
+		if found {
 			resolved[name] = value
 		}
 	}
 	return resolved, nil
 }
 
-// GetSecretsFilePath returns the path to the secrets file
-func (l *SecretsLoader) GetSecretsFilePath() string {
-	return l.secretsFilePath
+// Backends returns the names of the backends secrets are looked up in, in order
+func (l *SecretsLoader) Backends() ([]string, error) {
+	if err := l.Load(); err != nil {
+		return nil, err
+	}
+	names := make([]string, len(l.backends))
+	for i, backend := range l.backends {
+		names[i] = backend.Name()
+	}
+	return names, nil
 }
 
-// GetLoadedCount returns the number of secrets loaded from file
-func (l *SecretsLoader) GetLoadedCount() int {
-	return len(l.fileSecrets)
+// GetSecretsFilePath returns the plaintext secrets file grund secrets init writes to
+// It is empty when no file backend is configured.
+func (l *SecretsLoader) GetSecretsFilePath() string {
+	if err := l.Load(); err != nil {
+		return ""
+	}
+	for _, backend := range l.backends {
+		if path, ok := secrets.PlaintextPath(backend); ok {
+			return path
+		}
+	}
+	return ""
 }
@@ -0,0 +1,101 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vivekkundariya/grund/internal/domain/service"
+)
+
+type mapBackend struct {
+	name   string
+	values map[string]string
+	err    error
+}
+
+func (b mapBackend) Name() string { return b.name }
+
+// Lookup prefers a "<service>/<name>" key, like a per-service secrets file
+func (b mapBackend) Lookup(ctx context.Context, service, name string) (string, bool, error) {
+	if value, ok := b.values[service+"/"+name]; ok {
+		return value, true, b.err
+	}
+	value, ok := b.values[name]
+	return value, ok, b.err
+}
+
+func TestSecretsLoader_PriorityOrder(t *testing.T) {
+	loader := NewSecretsLoaderWithBackends(
+		mapBackend{name: "sops", values: map[string]string{"API_KEY": "from-sops"}},
+		mapBackend{name: "1password", values: map[string]string{"API_KEY": "from-op", "TOKEN": "tok"}},
+	)
+
+	value, source, found, err := loader.Lookup("", "API_KEY")
+	if err != nil || !found || value != "from-sops" || source != "sops" {
+		t.Errorf("Lookup(API_KEY) = %q, %q, %v, %v; want the first backend", value, source, found, err)
+	}
+
+	svc := &service.Service{Name: "order-service", Environment: service.Environment{Secrets: map[string]service.SecretRequirement{
+		"TOKEN":   {Required: true},
+		"MISSING": {Required: true},
+	}}}
+	statuses, err := loader.ValidateSecrets([]*service.Service{svc})
+	if err != nil {
+		t.Fatalf("ValidateSecrets() error: %v", err)
+	}
+	for _, s := range statuses {
+		switch s.Name {
+		case "TOKEN":
+			if !s.Found || s.Source != "1password" {
+				t.Errorf("TOKEN status = %+v, want found in 1password", s)
+			}
+		case "MISSING":
+			if s.Found || s.Source != "" {
+				t.Errorf("MISSING status = %+v, want not found", s)
+			}
+		}
+	}
+
+	names, _ := loader.Backends()
+	if len(names) != 2 || names[0] != "sops" || names[1] != "1password" {
+		t.Errorf("Backends() = %v", names)
+	}
+	if path := loader.GetSecretsFilePath(); path != "" {
+		t.Errorf("GetSecretsFilePath() = %q, want empty without a file backend", path)
+	}
+}
+
+func TestSecretsLoader_PerService(t *testing.T) {
+	loader := NewSecretsLoaderWithBackends(
+		mapBackend{name: "file", values: map[string]string{"order-service/API_KEY": "orders", "user-service/API_KEY": "users"}},
+	)
+	requirement := map[string]service.SecretRequirement{"API_KEY": {Required: true}}
+	orders := &service.Service{Name: "order-service", Environment: service.Environment{Secrets: requirement}}
+	users := &service.Service{Name: "user-service", Environment: service.Environment{Secrets: requirement}}
+	billing := &service.Service{Name: "billing-service", Environment: service.Environment{Secrets: requirement}}
+
+	resolved, err := loader.ResolveSecrets(users)
+	if err != nil || resolved["API_KEY"] != "users" {
+		t.Errorf("ResolveSecrets(user-service) = %v, %v", resolved, err)
+	}
+
+	missing, err := loader.GetMissingRequired([]*service.Service{orders, users, billing})
+	if err != nil {
+		t.Fatalf("GetMissingRequired() error: %v", err)
+	}
+	if len(missing) != 1 || len(missing[0].MissingFor) != 1 || missing[0].MissingFor[0] != "billing-service" {
+		t.Errorf("GetMissingRequired() = %+v, want API_KEY missing for billing-service", missing)
+	}
+}
+
+func TestSecretsLoader_BackendError(t *testing.T) {
+	loader := NewSecretsLoaderWithBackends(
+		mapBackend{name: "sops", err: errors.New("no key")},
+		mapBackend{name: "env", values: map[string]string{"API_KEY": "x"}},
+	)
+
+	if _, _, _, err := loader.Lookup("", "API_KEY"); err == nil {
+		t.Error("a failing backend should stop the lookup")
+	}
+}
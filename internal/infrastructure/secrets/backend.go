@@ -0,0 +1,125 @@
+// Package secrets looks up secret values in the backends configured in ~/.grund/config.yaml
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/vivekkundariya/grund/internal/config"
+)
+
+// Backend is a source of secret values
+type Backend interface {
+	// Name identifies the backend in grund secrets list
+	Name() string
+	// Lookup returns a secret's value; found is false when the backend doesn't have it
+	// service scopes the lookup to a service's own values where the backend has them,
+	// such as secrets.d/<service>.env; it may be empty.
+	Lookup(ctx context.Context, service, name string) (value string, found bool, err error)
+}
+
+// CommandRunner runs a backend's CLI and returns its stdout
+// Every sops, age, pass and exec invocation goes through it, so tests can substitute a fake.
+type CommandRunner interface {
+	Output(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// ExitError is returned by a CommandRunner when the command ran but exited non-zero
+type ExitError struct {
+	Command string
+	Code    int
+	Stderr  string
+}
+
+func (e *ExitError) Error() string {
+	if e.Stderr != "" {
+		return fmt.Sprintf("%s exited with code %d: %s", e.Command, e.Code, e.Stderr)
+	}
+	return fmt.Sprintf("%s exited with code %d", e.Command, e.Code)
+}
+
+// ExecRunner runs backend commands as local processes
+type ExecRunner struct{}
+
+// NewExecRunner creates a runner that executes commands on the host
+func NewExecRunner() CommandRunner {
+	return ExecRunner{}
+}
+
+// Output runs the command and returns its stdout
+func (ExecRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return output, &ExitError{
+			Command: name,
+			Code:    exitErr.ExitCode(),
+			Stderr:  strings.TrimSpace(stderr.String()),
+		}
+	}
+	if err != nil {
+		return output, fmt.Errorf("failed to run %s: %w", name, err)
+	}
+	return output, nil
+}
+
+// NewBackends creates backends from their configuration, in the same order
+func NewBackends(configs []config.SecretBackendConfig, runner CommandRunner) ([]Backend, error) {
+	backends := make([]Backend, 0, len(configs))
+	for _, cfg := range configs {
+		backend, err := NewBackend(cfg, runner)
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, backend)
+	}
+	return backends, nil
+}
+
+// NewBackend creates a single backend from its configuration
+func NewBackend(cfg config.SecretBackendConfig, runner CommandRunner) (Backend, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	name := cfg.DisplayName()
+
+	switch cfg.Type {
+	case config.SecretBackendFile:
+		return newFileBackend(name, cfg.Path), nil
+	case config.SecretBackendEnv:
+		return envBackend{name: name}, nil
+	case config.SecretBackendSops:
+		return newDecryptedFileBackend(name, cfg.Path, ParseSopsDotenv, runner, "sops", "--decrypt", "--output-type", "dotenv", cfg.Path), nil
+	case config.SecretBackendAge:
+		return newDecryptedFileBackend(name, cfg.Path, parseExpandedDotenv, runner, "age", "--decrypt", "--identity", cfg.Identity, cfg.Path), nil
+	case config.SecretBackendPass:
+		return newPassBackend(name, cfg.Prefix, runner), nil
+	case config.SecretBackendExec:
+		return newExecBackend(name, cfg.Command, cfg.NotFoundExitCode, runner)
+	default:
+		return nil, fmt.Errorf("unknown secret backend type %q", cfg.Type)
+	}
+}
+
+// envBackend reads secrets from the shell environment
+type envBackend struct {
+	name string
+}
+
+func (b envBackend) Name() string {
+	return b.name
+}
+
+func (b envBackend) Lookup(ctx context.Context, service, name string) (string, bool, error) {
+	value := os.Getenv(name)
+	return value, value != "", nil
+}
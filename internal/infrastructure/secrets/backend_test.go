@@ -0,0 +1,258 @@
+package secrets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vivekkundariya/grund/internal/config"
+)
+
+// fakeRunner returns canned output per command line and records what ran
+type fakeRunner struct {
+	output   map[string]string
+	failures map[string]*ExitError
+	commands []string
+}
+
+func (f *fakeRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	line := strings.Join(append([]string{name}, args...), " ")
+	f.commands = append(f.commands, line)
+	if err, ok := f.failures[line]; ok {
+		return nil, err
+	}
+	return []byte(f.output[line]), nil
+}
+
+func lookup(t *testing.T, b Backend, name string) (string, bool) {
+	t.Helper()
+	value, found, err := b.Lookup(context.Background(), "", name)
+	if err != nil {
+		t.Fatalf("%s.Lookup(%s) error: %v", b.Name(), name, err)
+	}
+	return value, found
+}
+
+func TestFileBackend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.env")
+	content := "# comment\nAPI_KEY=abc123\nQUOTED=\"with space\"\nEMPTY=\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := NewBackend(config.SecretBackendConfig{Type: "file", Path: path}, nil)
+	if err != nil {
+		t.Fatalf("NewBackend() error: %v", err)
+	}
+
+	if v, ok := lookup(t, b, "API_KEY"); !ok || v != "abc123" {
+		t.Errorf("API_KEY = %q, %v", v, ok)
+	}
+	if v, ok := lookup(t, b, "QUOTED"); !ok || v != "with space" {
+		t.Errorf("QUOTED = %q, %v", v, ok)
+	}
+	if _, ok := lookup(t, b, "EMPTY"); ok {
+		t.Error("an empty value should not count as found")
+	}
+	if p, ok := PlaintextPath(b); !ok || p != path {
+		t.Errorf("PlaintextPath() = %q, %v", p, ok)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.env")
+	os.WriteFile(bad, []byte("OK=1\nBROKEN=\"unterminated\n"), 0600)
+	b, _ = NewBackend(config.SecretBackendConfig{Type: "file", Path: bad}, nil)
+	if _, _, err := b.Lookup(context.Background(), "", "OK"); err == nil || !strings.Contains(err.Error(), "bad.env:2:") {
+		t.Errorf("Lookup() error = %v, want a line-numbered parse error", err)
+	}
+
+	missing, _ := NewBackend(config.SecretBackendConfig{Type: "file", Path: filepath.Join(t.TempDir(), "none.env")}, nil)
+	if _, ok := lookup(t, missing, "API_KEY"); ok {
+		t.Error("a missing file should have no secrets")
+	}
+}
+
+func TestFileBackend_ServiceFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secrets.env")
+	os.WriteFile(path, []byte("API_KEY=shared\nHOST=db.internal\n"), 0600)
+	os.MkdirAll(filepath.Join(dir, ServicesDir), 0700)
+	os.WriteFile(ServiceFilePath(path, "order-service"), []byte("API_KEY=orders\nDSN=postgres://${HOST}/orders\n"), 0600)
+
+	b, _ := NewBackend(config.SecretBackendConfig{Type: "file", Path: path}, nil)
+
+	tests := []struct {
+		service, name, want string
+	}{
+		{"order-service", "API_KEY", "orders"},
+		{"order-service", "DSN", "postgres://db.internal/orders"},
+		{"order-service", "HOST", "db.internal"},
+		{"user-service", "API_KEY", "shared"},
+		{"", "API_KEY", "shared"},
+	}
+	for _, tt := range tests {
+		value, found, err := b.Lookup(context.Background(), tt.service, tt.name)
+		if err != nil || !found || value != tt.want {
+			t.Errorf("Lookup(%q, %q) = %q, %v, %v; want %q", tt.service, tt.name, value, found, err, tt.want)
+		}
+	}
+}
+
+func TestDecryptedFileBackends(t *testing.T) {
+	runner := &fakeRunner{output: map[string]string{
+		"sops --decrypt --output-type dotenv /s/secrets.enc.yaml":   "API_KEY=from-sops\n",
+		"age --decrypt --identity /keys/age.txt /s/secrets.env.age": "API_KEY=from-age\n",
+	}}
+
+	sops, err := NewBackend(config.SecretBackendConfig{Type: "sops", Path: "/s/secrets.enc.yaml"}, runner)
+	if err != nil {
+		t.Fatalf("NewBackend(sops) error: %v", err)
+	}
+	age, err := NewBackend(config.SecretBackendConfig{Type: "age", Name: "team-age", Path: "/s/secrets.env.age", Identity: "/keys/age.txt"}, runner)
+	if err != nil {
+		t.Fatalf("NewBackend(age) error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if v, ok := lookup(t, sops, "API_KEY"); !ok || v != "from-sops" {
+			t.Errorf("sops API_KEY = %q, %v", v, ok)
+		}
+	}
+	if v, ok := lookup(t, age, "API_KEY"); !ok || v != "from-age" {
+		t.Errorf("age API_KEY = %q, %v", v, ok)
+	}
+
+	if age.Name() != "team-age" {
+		t.Errorf("Name() = %q, want team-age", age.Name())
+	}
+	if _, ok := PlaintextPath(sops); ok {
+		t.Error("sops files are not plaintext")
+	}
+	if len(runner.commands) != 2 {
+		t.Errorf("files should be decrypted once, ran %v", runner.commands)
+	}
+
+	runner.failures = map[string]*ExitError{"sops --decrypt --output-type dotenv /s/broken.yaml": {Command: "sops", Code: 128, Stderr: "no key"}}
+	broken, _ := NewBackend(config.SecretBackendConfig{Type: "sops", Path: "/s/broken.yaml"}, runner)
+	if _, _, err := broken.Lookup(context.Background(), "", "API_KEY"); err == nil || !strings.Contains(err.Error(), "no key") {
+		t.Errorf("Lookup() error = %v, want the decryption failure", err)
+	}
+}
+
+func TestSopsBackend_LiteralValues(t *testing.T) {
+	t.Setenv("HOST_TOKEN", "from-host")
+	runner := &fakeRunner{output: map[string]string{
+		"sops --decrypt --output-type dotenv /s/secrets.enc.yaml": "# managed by sops\n" +
+			"TEMPLATE=hello ${HOST_TOKEN}\n" +
+			"PASSWORD=abc #def\n" +
+			"QUOTED=\"half\n" +
+			`PEM=-----BEGIN KEY-----\nMIIB\n-----END KEY-----` + "\n",
+	}}
+	b, err := NewBackend(config.SecretBackendConfig{Type: "sops", Path: "/s/secrets.enc.yaml"}, runner)
+	if err != nil {
+		t.Fatalf("NewBackend() error: %v", err)
+	}
+
+	for name, want := range map[string]string{
+		"TEMPLATE": "hello ${HOST_TOKEN}",
+		"PASSWORD": "abc #def",
+		"QUOTED":   `"half`,
+		"PEM":      "-----BEGIN KEY-----\nMIIB\n-----END KEY-----",
+	} {
+		if v, ok := lookup(t, b, name); !ok || v != want {
+			t.Errorf("%s = %q, %v; want %q", name, v, ok, want)
+		}
+	}
+}
+
+func TestPassBackend(t *testing.T) {
+	runner := &fakeRunner{
+		output: map[string]string{"pass show grund/API_KEY": "s3cret\nurl: https://example.com\n"},
+		failures: map[string]*ExitError{
+			"pass show grund/MISSING": {Command: "pass", Code: 1, Stderr: "Error: grund/MISSING is not in the password store."},
+			"pass show grund/LOCKED":  {Command: "pass", Code: 2, Stderr: "gpg: decryption failed: No secret key"},
+		},
+	}
+	b, _ := NewBackend(config.SecretBackendConfig{Type: "pass", Prefix: "grund/"}, runner)
+
+	if v, ok := lookup(t, b, "API_KEY"); !ok || v != "s3cret" {
+		t.Errorf("API_KEY = %q, %v, want the first line", v, ok)
+	}
+	if _, ok := lookup(t, b, "MISSING"); ok {
+		t.Error("MISSING should not be found")
+	}
+	if _, _, err := b.Lookup(context.Background(), "", "LOCKED"); err == nil {
+		t.Error("a decryption failure should be an error")
+	}
+}
+
+func TestExecBackend(t *testing.T) {
+	runner := &fakeRunner{
+		output:   map[string]string{"op read op://dev vault/API_KEY": "from-op\n"},
+		failures: map[string]*ExitError{"op read op://dev vault/MISSING": {Command: "op", Code: 1}},
+	}
+	b, err := NewBackend(config.SecretBackendConfig{Type: "exec", Name: "1password", Command: `op read "op://dev vault/{name}"`}, runner)
+	if err != nil {
+		t.Fatalf("NewBackend() error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if v, ok := lookup(t, b, "API_KEY"); !ok || v != "from-op" {
+			t.Errorf("API_KEY = %q, %v", v, ok)
+		}
+	}
+	if _, ok := lookup(t, b, "MISSING"); ok {
+		t.Error("a non-zero exit should mean not found")
+	}
+	if len(runner.commands) != 2 {
+		t.Errorf("each secret should run the command once, ran %v", runner.commands)
+	}
+}
+
+func TestExecBackend_NotFoundExitCode(t *testing.T) {
+	runner := &fakeRunner{
+		failures: map[string]*ExitError{
+			"vault read secret/MISSING": {Command: "vault", Code: 2},
+			"vault read secret/EXPIRED": {Command: "vault", Code: 1, Stderr: "permission denied"},
+		},
+	}
+	b, err := NewBackend(config.SecretBackendConfig{Type: "exec", Command: "vault read secret/{name}", NotFoundExitCode: 2}, runner)
+	if err != nil {
+		t.Fatalf("NewBackend() error: %v", err)
+	}
+
+	if _, ok := lookup(t, b, "MISSING"); ok {
+		t.Error("the declared exit code should mean not found")
+	}
+	_, _, err = b.Lookup(context.Background(), "", "EXPIRED")
+	if err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("Lookup(EXPIRED) error = %v, want the command's stderr", err)
+	}
+}
+
+func TestNewBackend_Invalid(t *testing.T) {
+	for _, cfg := range []config.SecretBackendConfig{
+		{Type: "vault"},
+		{Type: "sops"},
+		{Type: "age", Path: "/s/secrets.age"},
+		{Type: "exec", Command: "op read op://vault/API_KEY"},
+		{Type: "exec", Command: `op read "op://vault/{name}`},
+	} {
+		if _, err := NewBackend(cfg, &fakeRunner{}); err == nil {
+			t.Errorf("NewBackend(%+v) should fail", cfg)
+		}
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	got, err := splitCommand(`sh -c 'vault kv get -field=value "secret/{name}"'  --flag`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"sh", "-c", `vault kv get -field=value "secret/{name}"`, "--flag"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitCommand() = %q, want %q", got, want)
+	}
+}
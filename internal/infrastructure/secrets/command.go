@@ -0,0 +1,170 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/vivekkundariya/grund/internal/ui"
+)
+
+// commandResult is a cached lookup
+type commandResult struct {
+	value string
+	found bool
+}
+
+// commandCache remembers lookups so each secret runs its command at most once
+type commandCache struct {
+	mu      sync.Mutex
+	results map[string]commandResult
+}
+
+func (c *commandCache) lookup(name string, run func() (string, bool, error)) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if r, ok := c.results[name]; ok {
+		return r.value, r.found, nil
+	}
+	value, found, err := run()
+	if err != nil {
+		return "", false, err
+	}
+	if c.results == nil {
+		c.results = make(map[string]commandResult)
+	}
+	c.results[name] = commandResult{value: value, found: found}
+	return value, found, nil
+}
+
+// passBackend reads secrets from the pass password store, one entry per secret
+// The value is the first line of the entry, following the pass convention.
+type passBackend struct {
+	name   string
+	prefix string
+	runner CommandRunner
+	cache  commandCache
+}
+
+func newPassBackend(name, prefix string, runner CommandRunner) *passBackend {
+	return &passBackend{name: name, prefix: prefix, runner: runner}
+}
+
+func (b *passBackend) Name() string {
+	return b.name
+}
+
+func (b *passBackend) Lookup(ctx context.Context, service, name string) (string, bool, error) {
+	return b.cache.lookup(name, func() (string, bool, error) {
+		output, err := b.runner.Output(ctx, "pass", "show", b.prefix+name)
+		var exitErr *ExitError
+		if errors.As(err, &exitErr) && strings.Contains(exitErr.Stderr, "is not in the password store") {
+			return "", false, nil
+		}
+		if err != nil {
+			return "", false, fmt.Errorf("pass show %s%s: %w", b.prefix, name, err)
+		}
+
+		value, _, _ := strings.Cut(string(output), "\n")
+		value = strings.TrimRight(value, "\r")
+		return value, value != "", nil
+	})
+}
+
+// execBackend runs a command per secret and uses its stdout as the value
+// With notFoundCode set, only that exit code means the command doesn't have the secret
+// and other failures are errors. Otherwise any non-zero exit falls through to later
+// backends, with a warning so an expired session isn't mistaken for a missing secret.
+type execBackend struct {
+	name         string
+	args         []string
+	notFoundCode int
+	runner       CommandRunner
+	cache        commandCache
+}
+
+func newExecBackend(name, command string, notFoundCode int, runner CommandRunner) (*execBackend, error) {
+	args, err := splitCommand(command)
+	if err != nil {
+		return nil, fmt.Errorf("exec secret backend: %w", err)
+	}
+	return &execBackend{name: name, args: args, notFoundCode: notFoundCode, runner: runner}, nil
+}
+
+func (b *execBackend) Name() string {
+	return b.name
+}
+
+func (b *execBackend) Lookup(ctx context.Context, service, name string) (string, bool, error) {
+	return b.cache.lookup(name, func() (string, bool, error) {
+		args := make([]string, len(b.args))
+		for i, arg := range b.args {
+			args[i] = strings.ReplaceAll(arg, "{name}", name)
+		}
+
+		output, err := b.runner.Output(ctx, args[0], args[1:]...)
+		var exitErr *ExitError
+		switch {
+		case errors.As(err, &exitErr) && b.notFoundCode != 0:
+			if exitErr.Code != b.notFoundCode {
+				return "", false, fmt.Errorf("%s backend failed to look up %s: %w", b.name, name, err)
+			}
+			ui.Debug("Secret %s not found by %s", name, b.name)
+			return "", false, nil
+		case errors.As(err, &exitErr):
+			ui.Warnf("%s backend failed to look up %s, treating it as not found: %v", b.name, name, err)
+			return "", false, nil
+		case err != nil:
+			return "", false, err
+		}
+
+		value := strings.TrimRight(string(output), "\r\n")
+		return value, value != "", nil
+	})
+}
+
+// splitCommand splits a command line into arguments, honouring single and double quotes
+// Secret names are substituted into the arguments afterwards, so they never reach a shell.
+func splitCommand(command string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+
+	for _, r := range command {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", command)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	return args, nil
+}
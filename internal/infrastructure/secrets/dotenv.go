@@ -0,0 +1,341 @@
+package secrets
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// dotenvKeyPattern matches valid variable names
+var dotenvKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// DotenvError reports a syntax error in a dotenv file
+type DotenvError struct {
+	Source  string
+	Line    int
+	Message string
+}
+
+func (e *DotenvError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.Source, e.Line, e.Message)
+}
+
+// ParseDotenv parses a dotenv file
+//
+// Lines are KEY=VALUE, optionally prefixed with export. Unquoted values end at
+// the line or at an inline " #" comment. Double-quoted values may span lines and
+// support \n, \r, \t, \" and \\ escapes. Single-quoted values are literal and
+// may span lines. ${VAR} in unquoted and double-quoted values expands to an
+// earlier key in the file, then to lookup; unknown variables expand to "" and
+// \$ is a literal $.
+func ParseDotenv(data []byte, source string, lookup func(string) (string, bool)) (map[string]string, error) {
+	p, err := parseDotenv(data, source, lookup)
+	if err != nil {
+		return nil, err
+	}
+	return p.values, nil
+}
+
+// ParseSopsDotenv parses what sops --decrypt --output-type dotenv prints
+//
+// sops writes each value raw after KEY=, with newlines escaped as \n and nothing
+// else quoted, so values are taken literally: no quotes, comments or ${VAR}
+// expansion. Blank lines and lines starting with # are skipped.
+func ParseSopsDotenv(data []byte, source string) (map[string]string, error) {
+	values := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || !dotenvKeyPattern.MatchString(key) {
+			return nil, &DotenvError{Source: source, Line: i + 1, Message: "expected KEY=value"}
+		}
+		values[key] = strings.ReplaceAll(value, `\n`, "\n")
+	}
+	return values, nil
+}
+
+func parseDotenv(data []byte, source string, lookup func(string) (string, bool)) (*dotenvParser, error) {
+	p := &dotenvParser{
+		src:    string(data),
+		line:   1,
+		source: source,
+		lookup: lookup,
+		values: make(map[string]string),
+	}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+type dotenvParser struct {
+	src     string
+	pos     int
+	line    int
+	source  string
+	lookup  func(string) (string, bool)
+	values  map[string]string
+	entries []dotenvEntry
+}
+
+// dotenvEntry records the lines an assignment spans, so it can be rewritten in place
+type dotenvEntry struct {
+	key       string
+	firstLine int
+	lastLine  int
+}
+
+func (p *dotenvParser) errorf(line int, format string, args ...any) error {
+	return &DotenvError{Source: p.source, Line: line, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *dotenvParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *dotenvParser) peek() byte {
+	return p.src[p.pos]
+}
+
+// next consumes one byte, counting lines
+func (p *dotenvParser) next() byte {
+	c := p.src[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+// skipBlanks skips spaces and tabs on the current line
+func (p *dotenvParser) skipBlanks() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t' || p.peek() == '\r') {
+		p.pos++
+	}
+}
+
+// skipLine consumes the rest of the current line, including the newline
+func (p *dotenvParser) skipLine() {
+	for !p.eof() {
+		if p.next() == '\n' {
+			return
+		}
+	}
+}
+
+func (p *dotenvParser) parse() error {
+	for {
+		// Skip blank lines and comments
+		for !p.eof() {
+			p.skipBlanks()
+			if p.eof() {
+				break
+			}
+			if p.peek() == '\n' || p.peek() == '#' {
+				p.skipLine()
+				continue
+			}
+			break
+		}
+		if p.eof() {
+			return nil
+		}
+		if err := p.parseEntry(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *dotenvParser) parseEntry() error {
+	line := p.line
+	key := p.readWord()
+	if key == "export" {
+		p.skipBlanks()
+		if !p.eof() && p.peek() != '\n' && p.peek() != '=' {
+			key = p.readWord()
+		}
+	}
+
+	if !dotenvKeyPattern.MatchString(key) {
+		return p.errorf(line, "invalid variable name %q", key)
+	}
+	p.skipBlanks()
+	if p.eof() || p.peek() != '=' {
+		return p.errorf(line, "expected '=' after %s", key)
+	}
+	p.pos++
+	p.skipBlanks()
+
+	var value string
+	var err error
+	switch {
+	case p.eof() || p.peek() == '\n':
+		value = ""
+	case p.peek() == '"':
+		value, err = p.readDoubleQuoted(line)
+	case p.peek() == '\'':
+		value, err = p.readSingleQuoted(line)
+	default:
+		value, err = p.readUnquoted(line)
+	}
+	if err != nil {
+		return err
+	}
+
+	p.values[key] = value
+
+	lastLine := p.line
+	if p.pos > 0 && p.src[p.pos-1] == '\n' {
+		lastLine--
+	}
+	p.entries = append(p.entries, dotenvEntry{key: key, firstLine: line, lastLine: lastLine})
+	return nil
+}
+
+// readWord reads up to whitespace or '='
+func (p *dotenvParser) readWord() string {
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		if c == '=' || c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// readUnquoted reads to the end of the line, dropping an inline comment
+func (p *dotenvParser) readUnquoted(line int) (string, error) {
+	start := p.pos
+	for !p.eof() && p.peek() != '\n' {
+		c := p.next()
+		if c == '#' && p.pos-1 > start && (p.src[p.pos-2] == ' ' || p.src[p.pos-2] == '\t') {
+			raw := p.src[start : p.pos-1]
+			p.skipLine()
+			return p.expand(strings.TrimRight(raw, " \t\r"), line)
+		}
+	}
+	raw := p.src[start:p.pos]
+	p.skipLine()
+	return p.expand(strings.TrimRight(raw, " \t\r"), line)
+}
+
+func (p *dotenvParser) readSingleQuoted(line int) (string, error) {
+	p.pos++
+	start := p.pos
+	for !p.eof() {
+		if p.next() == '\'' {
+			value := p.src[start : p.pos-1]
+			return value, p.endQuoted()
+		}
+	}
+	return "", p.errorf(line, "unterminated single-quoted value")
+}
+
+func (p *dotenvParser) readDoubleQuoted(line int) (string, error) {
+	p.pos++
+	var b strings.Builder
+	for !p.eof() {
+		c := p.next()
+		switch c {
+		case '"':
+			return b.String(), p.endQuoted()
+		case '\\':
+			if p.eof() {
+				continue
+			}
+			switch e := p.next(); e {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\', '$':
+				b.WriteByte(e)
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(e)
+			}
+		case '$':
+			if p.eof() || p.peek() != '{' {
+				b.WriteByte(c)
+				continue
+			}
+			end := strings.IndexAny(p.src[p.pos:], "}\"\n")
+			if end < 0 || p.src[p.pos+end] != '}' {
+				return "", p.errorf(line, "unterminated ${ in value")
+			}
+			value, err := p.variable(p.src[p.pos+1:p.pos+end], line)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(value)
+			p.pos += end + 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf(line, "unterminated double-quoted value")
+}
+
+// endQuoted checks that only a comment follows a closing quote
+func (p *dotenvParser) endQuoted() error {
+	p.skipBlanks()
+	if !p.eof() && p.peek() != '\n' && p.peek() != '#' {
+		return p.errorf(p.line, "unexpected characters after quoted value")
+	}
+	p.skipLine()
+	return nil
+}
+
+// expand replaces ${VAR} references in an unquoted value; \$ is a literal $
+func (p *dotenvParser) expand(value string, line int) (string, error) {
+	if !strings.Contains(value, "$") {
+		return value, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) && value[i+1] == '$' {
+			b.WriteByte('$')
+			i++
+			continue
+		}
+		if value[i] != '$' || i+1 >= len(value) || value[i+1] != '{' {
+			b.WriteByte(value[i])
+			continue
+		}
+
+		end := strings.IndexByte(value[i+2:], '}')
+		if end < 0 {
+			return "", p.errorf(line, "unterminated ${ in value")
+		}
+		v, err := p.variable(value[i+2:i+2+end], line)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(v)
+		i += end + 2
+	}
+	return b.String(), nil
+}
+
+// variable returns the value ${name} expands to
+func (p *dotenvParser) variable(name string, line int) (string, error) {
+	if !dotenvKeyPattern.MatchString(name) {
+		return "", p.errorf(line, "invalid variable name %q in ${}", name)
+	}
+	if v, ok := p.values[name]; ok {
+		return v, nil
+	}
+	if p.lookup != nil {
+		v, _ := p.lookup(name)
+		return v, nil
+	}
+	return "", nil
+}
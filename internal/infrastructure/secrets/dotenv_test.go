@@ -0,0 +1,86 @@
+package secrets
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	data := `# Grund secrets
+export API_KEY=abc123
+PLAIN = value with spaces   # inline comment
+HASH=abc#def
+TRAILING_QUOTE=abc"
+SINGLE='literal ${API_KEY} \n # not a comment'
+DOUBLE="line1\nline2\t\"quoted\" \\ \$HOME"
+EXPANDED="key=${API_KEY}"
+UNQUOTED_EXPANDED=${API_KEY}-${FROM_ENV}-${UNKNOWN}
+ESCAPED=\${API_KEY}
+PEM="-----BEGIN KEY-----
+MIIB
+-----END KEY-----"
+AFTER_PEM=ok
+EMPTY=
+QUOTED_EMPTY=""   # comment after quotes
+`
+	env := map[string]string{"FROM_ENV": "env"}
+	got, err := ParseDotenv([]byte(data), "secrets.env", func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	})
+	if err != nil {
+		t.Fatalf("ParseDotenv() error: %v", err)
+	}
+
+	want := map[string]string{
+		"API_KEY":           "abc123",
+		"PLAIN":             "value with spaces",
+		"HASH":              "abc#def",
+		"TRAILING_QUOTE":    `abc"`,
+		"SINGLE":            `literal ${API_KEY} \n # not a comment`,
+		"DOUBLE":            "line1\nline2\t\"quoted\" \\ $HOME",
+		"EXPANDED":          "key=abc123",
+		"UNQUOTED_EXPANDED": "abc123-env-",
+		"ESCAPED":           "${API_KEY}",
+		"PEM":               "-----BEGIN KEY-----\nMIIB\n-----END KEY-----",
+		"AFTER_PEM":         "ok",
+		"EMPTY":             "",
+		"QUOTED_EMPTY":      "",
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %q, want %q", key, got[key], value)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d keys, want %d: %v", len(got), len(want), got)
+	}
+}
+
+func TestParseDotenv_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		line int
+	}{
+		{"missing equals", "A=1\nJUST_A_KEY\n", 2},
+		{"invalid name", "A=1\n\n1BAD=x\n", 3},
+		{"unterminated double quote", "A=1\nPEM=\"-----BEGIN\nabc\n", 2},
+		{"unterminated single quote", "A='abc\n", 1},
+		{"text after quotes", "A=\"abc\" def\n", 1},
+		{"unterminated expansion", "A=${B\n", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDotenv([]byte(tt.data), "secrets.env", nil)
+			var dotenvErr *DotenvError
+			if !errors.As(err, &dotenvErr) {
+				t.Fatalf("ParseDotenv() error = %v, want a DotenvError", err)
+			}
+			if dotenvErr.Line != tt.line || dotenvErr.Source != "secrets.env" {
+				t.Errorf("error at %s:%d, want line %d (%v)", dotenvErr.Source, dotenvErr.Line, tt.line, err)
+			}
+		})
+	}
+}
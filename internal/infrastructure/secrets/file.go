@@ -0,0 +1,160 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/vivekkundariya/grund/internal/ui"
+)
+
+// ServicesDir is the directory, next to a plaintext secrets file, holding per-service files
+// Values in <ServicesDir>/<service>.env override the shared file for that service.
+const ServicesDir = "secrets.d"
+
+// fileBackend reads secrets from a dotenv file, loaded once on first lookup
+// The file is plaintext, or decrypted by a command for sops and age.
+type fileBackend struct {
+	name      string
+	path      string
+	plaintext bool
+	read      func(ctx context.Context) ([]byte, error)
+	parse     func(data []byte, source string) (map[string]string, error)
+
+	once   sync.Once
+	values map[string]string
+	err    error
+
+	mu       sync.Mutex
+	services map[string]serviceFile
+}
+
+// serviceFile is a parsed per-service secrets file
+type serviceFile struct {
+	values map[string]string
+	err    error
+}
+
+// newFileBackend reads a plaintext dotenv file and its per-service files
+// A missing file has no secrets.
+func newFileBackend(name, path string) *fileBackend {
+	return &fileBackend{
+		name:      name,
+		path:      path,
+		plaintext: true,
+		read: func(ctx context.Context) ([]byte, error) {
+			return readOptionalFile(path)
+		},
+		parse: parseExpandedDotenv,
+	}
+}
+
+// newDecryptedFileBackend decrypts an encrypted file with a command such as sops or age,
+// then parses its output with parse
+func newDecryptedFileBackend(name, path string, parse func([]byte, string) (map[string]string, error), runner CommandRunner, command string, args ...string) *fileBackend {
+	return &fileBackend{
+		name: name,
+		path: path,
+		read: func(ctx context.Context) ([]byte, error) {
+			return runner.Output(ctx, command, args...)
+		},
+		parse: parse,
+	}
+}
+
+// parseExpandedDotenv parses a dotenv file written by hand, expanding ${VAR} from the host environment
+func parseExpandedDotenv(data []byte, source string) (map[string]string, error) {
+	return ParseDotenv(data, source, os.LookupEnv)
+}
+
+func (b *fileBackend) Name() string {
+	return b.name
+}
+
+// PlaintextPath returns the file a plaintext file backend reads
+// ok is false for every other backend, including sops and age files.
+func PlaintextPath(backend Backend) (path string, ok bool) {
+	if b, isFile := backend.(*fileBackend); isFile && b.plaintext {
+		return b.path, true
+	}
+	return "", false
+}
+
+// ServiceFilePath returns the per-service secrets file next to a plaintext secrets file
+func ServiceFilePath(path, service string) string {
+	return filepath.Join(filepath.Dir(path), ServicesDir, service+".env")
+}
+
+func (b *fileBackend) Lookup(ctx context.Context, service, name string) (string, bool, error) {
+	b.once.Do(func() {
+		data, err := b.read(ctx)
+		if err != nil {
+			b.err = fmt.Errorf("failed to read secrets from %s: %w", b.path, err)
+			return
+		}
+		b.values, b.err = b.parse(data, b.path)
+		ui.Debug("Loaded %d secrets from %s", len(b.values), b.path)
+	})
+	if b.err != nil {
+		return "", false, b.err
+	}
+
+	if service != "" && b.plaintext {
+		values, err := b.serviceValues(service)
+		if err != nil {
+			return "", false, err
+		}
+		if value := values[name]; value != "" {
+			return value, true, nil
+		}
+	}
+
+	value, ok := b.values[name]
+	return value, ok && value != "", nil
+}
+
+// serviceValues loads a service's own secrets file once
+// Its ${VAR} references can use keys from the shared file.
+func (b *fileBackend) serviceValues(service string) (map[string]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if f, ok := b.services[service]; ok {
+		return f.values, f.err
+	}
+
+	path := ServiceFilePath(b.path, service)
+	var f serviceFile
+	data, err := readOptionalFile(path)
+	if err != nil {
+		f.err = fmt.Errorf("failed to read secrets from %s: %w", path, err)
+	} else {
+		f.values, f.err = ParseDotenv(data, path, func(key string) (string, bool) {
+			if value, ok := b.values[key]; ok {
+				return value, true
+			}
+			return os.LookupEnv(key)
+		})
+	}
+
+	if b.services == nil {
+		b.services = make(map[string]serviceFile)
+	}
+	b.services[service] = f
+	return f.values, f.err
+}
+
+// readOptionalFile reads a secrets file; a missing file reads as empty
+func readOptionalFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		ui.Debug("No secrets file found at %s", path)
+		return nil, nil
+	}
+	if err == nil {
+		WarnIfReadable(path)
+	}
+	return data, err
+}
@@ -0,0 +1,237 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/vivekkundariya/grund/internal/ui"
+)
+
+const (
+	// lockTimeout is how long an edit waits for another grund process to finish
+	lockTimeout = 10 * time.Second
+	// staleLockAge is when a lock is assumed to be left behind by a crashed process
+	staleLockAge = time.Minute
+)
+
+// FileStore edits a plaintext dotenv secrets file
+// Edits hold a lock file and replace the file atomically, so concurrent writers
+// don't lose each other's changes and readers never see a partial file.
+type FileStore struct {
+	path string
+}
+
+// NewFileStore creates a store for the secrets file at path
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Path returns the secrets file
+func (s *FileStore) Path() string {
+	return s.path
+}
+
+// ValidName reports whether name can be used as a secret name
+func ValidName(name string) bool {
+	return dotenvKeyPattern.MatchString(name)
+}
+
+// Get returns the value assigned in the file
+func (s *FileStore) Get(name string) (string, bool, error) {
+	data, err := readOptionalFile(s.path)
+	if err != nil {
+		return "", false, err
+	}
+	values, err := ParseDotenv(data, s.path, os.LookupEnv)
+	if err != nil {
+		return "", false, err
+	}
+	value, ok := values[name]
+	return value, ok, nil
+}
+
+// Set assigns a value, replacing an existing assignment in place
+func (s *FileStore) Set(name, value string) error {
+	if !ValidName(name) {
+		return fmt.Errorf("invalid secret name %q", name)
+	}
+	line := name + "=" + FormatDotenvValue(value)
+	return s.Update(func(data []byte) ([]byte, error) {
+		out, _, err := rewriteEntry(data, s.path, name, &line)
+		return out, err
+	})
+}
+
+// Unset removes a value; removed is false when the file didn't assign it
+func (s *FileStore) Unset(name string) (removed bool, err error) {
+	if _, err := os.Stat(s.path); os.IsNotExist(err) {
+		return false, nil
+	}
+	err = s.Update(func(data []byte) ([]byte, error) {
+		out, found, err := rewriteEntry(data, s.path, name, nil)
+		removed = found
+		return out, err
+	})
+	return removed, err
+}
+
+// Update applies fn to the file's contents under the lock and writes the result atomically
+// A new file is created 0600; an existing file keeps its permissions.
+func (s *FileStore) Update(fn func(data []byte) ([]byte, error)) error {
+	path := s.path
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	data, err := readOptionalFile(path)
+	if err != nil {
+		return err
+	}
+
+	out, err := fn(data)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, out, mode)
+}
+
+// WarnIfReadable warns when a secrets file is world-readable
+func WarnIfReadable(path string) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	if info.Mode().Perm()&0o004 != 0 {
+		ui.Warnf("%s is readable by all users (mode %04o); run: chmod 600 %s", path, info.Mode().Perm(), path)
+	}
+}
+
+// plainDotenvValue matches values that need no quoting
+var plainDotenvValue = regexp.MustCompile(`^[A-Za-z0-9_./:@%+=,-]*$`)
+
+// FormatDotenvValue quotes a value so ParseDotenv reads it back unchanged
+func FormatDotenvValue(value string) string {
+	if plainDotenvValue.MatchString(value) {
+		return value
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`)
+	return `"` + r.Replace(value) + `"`
+}
+
+// rewriteEntry replaces every assignment of name with line, at the first one's position
+// A nil line removes the assignments; found reports whether there were any.
+func rewriteEntry(data []byte, source, name string, line *string) (out []byte, found bool, err error) {
+	p, err := parseDotenv(data, source, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	first := 0
+	remove := make(map[int]bool)
+	for _, e := range p.entries {
+		if e.key != name {
+			continue
+		}
+		if first == 0 {
+			first = e.firstLine
+		}
+		for l := e.firstLine; l <= e.lastLine; l++ {
+			remove[l] = true
+		}
+	}
+
+	var b strings.Builder
+	for i, l := range strings.SplitAfter(string(data), "\n") {
+		if i+1 == first && line != nil {
+			b.WriteString(*line + "\n")
+		}
+		if !remove[i+1] {
+			b.WriteString(l)
+		}
+	}
+	if first == 0 && line != nil {
+		if b.Len() > 0 && !strings.HasSuffix(b.String(), "\n") {
+			b.WriteString("\n")
+		}
+		b.WriteString(*line + "\n")
+	}
+	return []byte(b.String()), first != 0, nil
+}
+
+// lockFile creates path exclusively, waiting while another process holds it
+func lockFile(path string) (unlock func(), err error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			ui.Warnf("Removing stale lock %s", path)
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for %s; remove it if no other grund process is running", path)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// writeFileAtomic writes to a temporary file in the same directory and renames it into place
+func writeFileAtomic(path string, data []byte, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
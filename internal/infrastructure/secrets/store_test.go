@@ -0,0 +1,117 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestFileStore_SetAndUnset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "grund", "secrets.env")
+	store := NewFileStore(path)
+
+	if removed, err := store.Unset("API_KEY"); err != nil || removed {
+		t.Errorf("Unset() on a missing file = %v, %v", removed, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Unset() should not create the file")
+	}
+
+	values := map[string]string{
+		"API_KEY": "abc123",
+		"PEM":     "-----BEGIN KEY-----\nMIIB\n-----END KEY-----",
+		"TRICKY":  `a "quoted" \ ${HOME} # not a comment`,
+	}
+	for name, value := range values {
+		if err := store.Set(name, value); err != nil {
+			t.Fatalf("Set(%s) error: %v", name, err)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %04o, want 0600", info.Mode().Perm())
+	}
+
+	for name, want := range values {
+		got, found, err := store.Get(name)
+		if err != nil || !found || got != want {
+			t.Errorf("Get(%s) = %q, %v, %v; want %q", name, got, found, err, want)
+		}
+	}
+
+	if err := store.Set("API_KEY", "rotated"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _, _ := store.Get("API_KEY"); got != "rotated" {
+		t.Errorf("API_KEY = %q, want rotated", got)
+	}
+
+	if removed, err := store.Unset("PEM"); err != nil || !removed {
+		t.Errorf("Unset(PEM) = %v, %v", removed, err)
+	}
+	if _, found, _ := store.Get("PEM"); found {
+		t.Error("PEM should be removed")
+	}
+
+	if err := store.Set("1BAD", "x"); err == nil {
+		t.Error("Set() should reject invalid names")
+	}
+}
+
+func TestFileStore_PreservesLayout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.env")
+	original := "# header\nA=1  # keep me\nPEM=\"line1\nline2\"\nB=2\nPEM=dup\n# footer"
+	if err := os.WriteFile(path, []byte(original), 0640); err != nil {
+		t.Fatal(err)
+	}
+	store := NewFileStore(path)
+
+	if err := store.Set("PEM", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("C", "3"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(path)
+	want := "# header\nA=1  # keep me\nPEM=new\nB=2\n# footer\nC=3\n"
+	if string(data) != want {
+		t.Errorf("file = %q, want %q", data, want)
+	}
+
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0640 {
+		t.Errorf("mode = %04o, want the existing 0640 kept", info.Mode().Perm())
+	}
+}
+
+func TestFileStore_ConcurrentSets(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "secrets.env"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := store.Set(fmt.Sprintf("KEY_%d", i), fmt.Sprint(i)); err != nil {
+				t.Errorf("Set() error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < 10; i++ {
+		if got, found, _ := store.Get(fmt.Sprintf("KEY_%d", i)); !found || got != fmt.Sprint(i) {
+			t.Errorf("KEY_%d = %q, %v; a concurrent edit was lost", i, got, found)
+		}
+	}
+	if _, err := os.Stat(store.Path() + ".lock"); !os.IsNotExist(err) {
+		t.Error("the lock file should be removed")
+	}
+}
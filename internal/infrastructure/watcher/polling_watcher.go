@@ -0,0 +1,182 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vivekkundariya/grund/internal/application/ports"
+	"github.com/vivekkundariya/grund/internal/ui"
+)
+
+// defaultPollInterval is how often the tree is scanned for changes
+const defaultPollInterval = 500 * time.Millisecond
+
+// defaultIgnore is always excluded from watching
+var defaultIgnore = []string{".git", ".idea", ".vscode", ".DS_Store"}
+
+// PollingWatcher implements FileWatcher by periodically scanning modification times
+// Polling keeps grund free of platform-specific notification APIs and works
+// across bind mounts and network filesystems.
+type PollingWatcher struct {
+	interval time.Duration
+}
+
+// NewPollingWatcher creates a new polling file watcher
+func NewPollingWatcher() ports.FileWatcher {
+	return &PollingWatcher{interval: defaultPollInterval}
+}
+
+// fileState is the part of a file's metadata used to detect changes
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watch scans dir every interval and reports changes after a quiet interval
+func (w *PollingWatcher) Watch(ctx context.Context, dir string, ignore []string, onChange func(changed []string)) error {
+	patterns := append(append([]string{}, defaultIgnore...), ignore...)
+
+	previous, err := scan(dir, patterns)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	pending := make(map[string]bool)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := scan(dir, patterns)
+		if err != nil {
+			ui.Debug("Failed to scan %s: %v", dir, err)
+			continue
+		}
+
+		changed := diff(previous, current)
+		previous = current
+		for _, path := range changed {
+			pending[path] = true
+		}
+
+		// Wait for a quiet tick so one save (or a git checkout) triggers a single reload
+		if len(changed) == 0 && len(pending) > 0 {
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			pending = make(map[string]bool)
+			onChange(paths)
+		}
+	}
+}
+
+// scan records the state of every non-ignored file under dir
+func scan(dir string, patterns []string) (map[string]fileState, error) {
+	files := make(map[string]fileState)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Files can disappear mid-scan; they will show up as removed next time
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if Ignored(rel, patterns) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files[rel] = fileState{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+// diff returns files that were added, removed or modified
+func diff(previous, current map[string]fileState) []string {
+	var changed []string
+	for path, state := range current {
+		if old, ok := previous[path]; !ok || !old.modTime.Equal(state.modTime) || old.size != state.size {
+			changed = append(changed, path)
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
+
+// Ignored reports whether a slash-separated relative path matches any ignore glob
+//   - "node_modules" or "*.log" (no slash) match any path segment
+//   - "dist/**" matches everything under dist
+//   - "**/*.tmp" matches at any depth
+//   - other patterns match the whole relative path
+func Ignored(rel string, patterns []string) bool {
+	segments := strings.Split(rel, "/")
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "./"), "/")
+		if pattern == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasSuffix(pattern, "/**"):
+			prefix := strings.TrimSuffix(pattern, "/**")
+			if rel == prefix || strings.HasPrefix(rel, prefix+"/") {
+				return true
+			}
+		case strings.HasPrefix(pattern, "**/"):
+			sub := strings.TrimPrefix(pattern, "**/")
+			for i := range segments {
+				if ok, _ := filepath.Match(sub, strings.Join(segments[i:], "/")); ok {
+					return true
+				}
+			}
+		case !strings.Contains(pattern, "/"):
+			for _, segment := range segments {
+				if ok, _ := filepath.Match(pattern, segment); ok {
+					return true
+				}
+			}
+		default:
+			if ok, _ := filepath.Match(pattern, rel); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIgnored(t *testing.T) {
+	patterns := []string{"node_modules", "*.log", "dist/**", "**/*.tmp", "config/local.yaml"}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"main.go", false},
+		{"node_modules", true},
+		{"web/node_modules/react/index.js", true},
+		{"logs/app.log", true},
+		{"dist", true},
+		{"dist/bundle.js", true},
+		{"src/dist/bundle.js", false},
+		{"a/b/c.tmp", true},
+		{"config/local.yaml", true},
+		{"config/prod.yaml", false},
+	}
+
+	for _, tt := range tests {
+		if got := Ignored(tt.path, patterns); got != tt.want {
+			t.Errorf("Ignored(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	now := time.Now()
+	previous := map[string]fileState{
+		"same.go":    {modTime: now, size: 1},
+		"edited.go":  {modTime: now, size: 1},
+		"removed.go": {modTime: now, size: 1},
+	}
+	current := map[string]fileState{
+		"same.go":   {modTime: now, size: 1},
+		"edited.go": {modTime: now.Add(time.Second), size: 1},
+		"added.go":  {modTime: now, size: 1},
+	}
+
+	changed := map[string]bool{}
+	for _, path := range diff(previous, current) {
+		changed[path] = true
+	}
+	if len(changed) != 3 || !changed["edited.go"] || !changed["removed.go"] || !changed["added.go"] {
+		t.Errorf("diff() = %v, want edited.go, removed.go, added.go", changed)
+	}
+}
+
+func TestPollingWatcher_Watch(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "tmp"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	w := &PollingWatcher{interval: 10 * time.Millisecond}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan []string, 1)
+	go func() {
+		_ = w.Watch(ctx, dir, []string{"tmp/**"}, func(changed []string) {
+			select {
+			case changes <- changed:
+			default:
+			}
+		})
+	}()
+
+	// Let the watcher take its initial snapshot
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(filepath.Join(dir, "tmp", "ignored.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case changed := <-changes:
+		if len(changed) != 1 || changed[0] != "main.go" {
+			t.Errorf("onChange() got %v, want [main.go]", changed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for change")
+	}
+}
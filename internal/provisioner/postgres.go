@@ -1,12 +1,20 @@
 package provisioner
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/vivekkundariya/grund/internal/config"
+	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
+	"github.com/vivekkundariya/grund/internal/infrastructure/docker"
 )
 
 // RunPostgresMigrations runs database migrations if specified
+// The database and role are taken from databaseURL; migrations run in the grund-postgres container
 func RunPostgresMigrations(migrationPath, databaseURL string) error {
 	if migrationPath == "" {
 		return nil
@@ -17,13 +25,18 @@ func RunPostgresMigrations(migrationPath, databaseURL string) error {
 		return fmt.Errorf("migration path does not exist: %s", migrationPath)
 	}
 
-	// TODO: Implement actual migration running
-	// This could use a migration tool like golang-migrate or similar
-	fmt.Printf("Running migrations from %s\n", migrationPath)
-	return nil
+	db, err := parseDatabaseURL(databaseURL)
+	if err != nil {
+		return err
+	}
+	db.Migrations = migrationPath
+
+	_, err = docker.NewPostgresMigrator(config.DefaultEnvironment(), defaultRuntime()).Migrate(context.Background(), db)
+	return err
 }
 
 // SeedPostgresDatabase seeds the database with initial data
+// The seed runs once per database; see RunPostgresMigrations for how databaseURL is used
 func SeedPostgresDatabase(seedPath, databaseURL string) error {
 	if seedPath == "" {
 		return nil
@@ -34,9 +47,43 @@ func SeedPostgresDatabase(seedPath, databaseURL string) error {
 		return fmt.Errorf("seed file does not exist: %s", seedPath)
 	}
 
-	// TODO: Implement actual database seeding
-	fmt.Printf("Seeding database from %s\n", seedPath)
-	return nil
+	db, err := parseDatabaseURL(databaseURL)
+	if err != nil {
+		return err
+	}
+	db.Seed = seedPath
+
+	_, err = docker.NewDatabaseSeeder(config.DefaultEnvironment(), defaultRuntime()).SeedPostgres(context.Background(), db, false)
+	return err
+}
+
+// defaultRuntime returns the runtime for the configured compose command,
+// falling back to Docker when it isn't recognised
+func defaultRuntime() *docker.Runtime {
+	rt, err := config.DetectRuntime(config.GetDockerComposeCommand())
+	if err != nil {
+		rt = config.DefaultRuntime()
+	}
+	return docker.NewRuntime(rt, docker.NewExecRunner())
+}
+
+// parseDatabaseURL extracts the database and role from a postgres:// URL
+func parseDatabaseURL(databaseURL string) (infrastructure.PostgresDatabase, error) {
+	u, err := url.Parse(databaseURL)
+	if err != nil {
+		return infrastructure.PostgresDatabase{}, fmt.Errorf("invalid database URL: %w", err)
+	}
+	db := infrastructure.PostgresDatabase{
+		Database: strings.TrimPrefix(u.Path, "/"),
+		Username: u.User.Username(),
+	}
+	if password, ok := u.User.Password(); ok {
+		db.Password = password
+	}
+	if db.Database == "" {
+		return infrastructure.PostgresDatabase{}, fmt.Errorf("database URL has no database name: %s", databaseURL)
+	}
+	return db, nil
 }
 
 // GetPostgresInitDir returns the directory for postgres init scripts
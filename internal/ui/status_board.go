@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// StatusBoard shows one status line per item
+// On a terminal the lines are redrawn in place as statuses change; otherwise
+// (pipes, CI logs) each change is printed as a new line.
+type StatusBoard struct {
+	names  []string
+	status map[string]string
+	width  int
+	live   bool
+	drawn  bool
+}
+
+// NewStatusBoard creates a board with a line for each name, in order
+func NewStatusBoard(names []string) *StatusBoard {
+	b := &StatusBoard{
+		names:  names,
+		status: make(map[string]string, len(names)),
+	}
+	for _, name := range names {
+		if len(name) > b.width {
+			b.width = len(name)
+		}
+	}
+
+	defaultLogger.mu.Lock()
+	b.live = IsTerminal(defaultLogger.out)
+	defaultLogger.mu.Unlock()
+
+	return b
+}
+
+// Set updates an item's status
+func (b *StatusBoard) Set(name, format string, args ...any) {
+	defaultLogger.mu.Lock()
+	defer defaultLogger.mu.Unlock()
+
+	status := format
+	if len(args) > 0 {
+		status = fmt.Sprintf(format, args...)
+	}
+	if b.status[name] == status {
+		return
+	}
+	b.status[name] = status
+
+	if !b.live {
+		fmt.Fprintln(defaultLogger.out, b.line(name))
+		return
+	}
+
+	// Move back to the first line and redraw every line
+	if b.drawn {
+		fmt.Fprintf(defaultLogger.out, "\033[%dA", len(b.names))
+	}
+	for _, n := range b.names {
+		fmt.Fprintf(defaultLogger.out, "\033[2K%s\n", b.line(n))
+	}
+	b.drawn = true
+}
+
+// Progress updates an item's status only where lines are redrawn in place
+// Use it for ticks like elapsed time, which would flood a CI log with a line per change.
+func (b *StatusBoard) Progress(name, format string, args ...any) {
+	if b.live {
+		b.Set(name, format, args...)
+	}
+}
+
+// IsTerminal reports whether w is a terminal, where output can be redrawn in place
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (b *StatusBoard) line(name string) string {
+	bullet := "•"
+	if defaultLogger.useColor {
+		bullet = Colorize("•", Purple)
+	}
+	return fmt.Sprintf("    %s %s%s  %s", bullet, name, strings.Repeat(" ", b.width-len(name)), b.status[name])
+}
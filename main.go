@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"os"
 
 	"github.com/vivekkundariya/grund/internal/cli"
@@ -8,6 +9,11 @@ import (
 
 func main() {
 	if err := cli.Execute(); err != nil {
+		// grund run exits with the code of the command it ran
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			os.Exit(exitErr.ExitCode())
+		}
 		os.Exit(1)
 	}
 }
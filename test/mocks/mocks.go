@@ -3,6 +3,8 @@ package mocks
 import (
 	"context"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/vivekkundariya/grund/internal/application/ports"
 	"github.com/vivekkundariya/grund/internal/domain/infrastructure"
@@ -73,7 +75,7 @@ type MockContainerOrchestrator struct {
 	StopServicesFunc     func(ctx context.Context) error
 	RestartServiceFunc   func(ctx context.Context, name service.ServiceName) error
 	GetServiceStatusFunc func(ctx context.Context, name service.ServiceName) (ports.ServiceStatus, error)
-	GetLogsFunc          func(ctx context.Context, name service.ServiceName, follow bool, tail int) (ports.LogStream, error)
+	GetLogsFunc          func(ctx context.Context, names []service.ServiceName, opts ports.LogOptions) (ports.LogStream, error)
 
 	// Track calls
 	StartServicesCalls  [][]service.ServiceName
@@ -116,9 +118,9 @@ func (m *MockContainerOrchestrator) GetServiceStatus(ctx context.Context, name s
 	}, nil
 }
 
-func (m *MockContainerOrchestrator) GetLogs(ctx context.Context, name service.ServiceName, follow bool, tail int) (ports.LogStream, error) {
+func (m *MockContainerOrchestrator) GetLogs(ctx context.Context, names []service.ServiceName, opts ports.LogOptions) (ports.LogStream, error) {
 	if m.GetLogsFunc != nil {
-		return m.GetLogsFunc(ctx, name, follow, tail)
+		return m.GetLogsFunc(ctx, names, opts)
 	}
 	return nil, fmt.Errorf("GetLogs not implemented")
 }
@@ -193,15 +195,15 @@ func (m *MockComposeGenerator) Generate(services []*service.Service, infra infra
 
 // MockHealthChecker is a mock implementation of ports.HealthChecker
 type MockHealthChecker struct {
-	CheckHealthFunc    func(ctx context.Context, endpoint string, timeout int) error
-	WaitForHealthyFunc func(ctx context.Context, endpoint string, interval, timeout int, retries int) error
+	CheckHealthFunc    func(ctx context.Context, endpoint string, timeout time.Duration) error
+	WaitForHealthyFunc func(ctx context.Context, endpoint string, interval, timeout time.Duration, retries int) error
 
 	// Track calls
 	CheckHealthCalls    []string
 	WaitForHealthyCalls []string
 }
 
-func (m *MockHealthChecker) CheckHealth(ctx context.Context, endpoint string, timeout int) error {
+func (m *MockHealthChecker) CheckHealth(ctx context.Context, endpoint string, timeout time.Duration) error {
 	m.CheckHealthCalls = append(m.CheckHealthCalls, endpoint)
 	if m.CheckHealthFunc != nil {
 		return m.CheckHealthFunc(ctx, endpoint, timeout)
@@ -209,7 +211,7 @@ func (m *MockHealthChecker) CheckHealth(ctx context.Context, endpoint string, ti
 	return nil
 }
 
-func (m *MockHealthChecker) WaitForHealthy(ctx context.Context, endpoint string, interval, timeout int, retries int) error {
+func (m *MockHealthChecker) WaitForHealthy(ctx context.Context, endpoint string, interval, timeout time.Duration, retries int) error {
 	m.WaitForHealthyCalls = append(m.WaitForHealthyCalls, endpoint)
 	if m.WaitForHealthyFunc != nil {
 		return m.WaitForHealthyFunc(ctx, endpoint, interval, timeout, retries)
@@ -232,25 +234,30 @@ func (m *MockEnvironmentResolver) Resolve(envRefs map[string]string, context por
 
 // MockLogStream is a mock implementation of ports.LogStream
 type MockLogStream struct {
-	ReadFunc  func() ([]byte, error)
+	NextFunc  func() (ports.LogEntry, error)
 	CloseFunc func() error
 
-	logs   []byte
-	closed bool
+	entries []ports.LogEntry
+	closed  bool
 }
 
-func NewMockLogStream(logs string) *MockLogStream {
-	return &MockLogStream{logs: []byte(logs)}
+func NewMockLogStream(entries ...ports.LogEntry) *MockLogStream {
+	return &MockLogStream{entries: entries}
 }
 
-func (m *MockLogStream) Read() ([]byte, error) {
-	if m.ReadFunc != nil {
-		return m.ReadFunc()
+func (m *MockLogStream) Next() (ports.LogEntry, error) {
+	if m.NextFunc != nil {
+		return m.NextFunc()
 	}
 	if m.closed {
-		return nil, fmt.Errorf("stream closed")
+		return ports.LogEntry{}, fmt.Errorf("stream closed")
 	}
-	return m.logs, nil
+	if len(m.entries) == 0 {
+		return ports.LogEntry{}, io.EOF
+	}
+	entry := m.entries[0]
+	m.entries = m.entries[1:]
+	return entry, nil
 }
 
 func (m *MockLogStream) Close() error {